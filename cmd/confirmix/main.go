@@ -5,49 +5,119 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math/big"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/SolidityDevSK/Confirmix/internal/validator"
 	"github.com/SolidityDevSK/Confirmix/pkg/api"
 	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/SolidityDevSK/Confirmix/pkg/ethrpc"
 	"github.com/SolidityDevSK/Confirmix/pkg/network"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func main() {
 	// Komut satırı parametrelerini tanımla
 	apiPort := flag.Int("api-port", 8080, "HTTP API port")
+	rpcPort := flag.Int("rpc-port", 8545, "Ethereum JSON-RPC port, serving HTTP and WebSocket (0 disables)")
+	rpcOrigins := flag.String("rpc-allowed-origins", strings.Join(ethrpc.DefaultConfig().AllowedOrigins, ","), "Comma separated browser origins allowed to use the JSON-RPC API over CORS and WebSocket (* allows all)")
 	p2pPort := flag.Int("p2p-port", 9000, "P2P network port")
 	bootstrapNode := flag.String("bootstrap", "", "Bootstrap node address")
+	syncMode := flag.String("sync-mode", network.SyncModeFull, "Sync mode for a fresh node: full or snapshot")
+	snapshotInterval := flag.Uint64("snapshot-interval", 1000, "Produce a state snapshot every N blocks (0 disables)")
+	genesisPath := flag.String("genesis", "", "Genesis file with the EVM chain config (chain ID and fork activation), genesis timestamp, validator public keys, contract code limits and account allocations; all forks up to Shanghai are active and the local validator runs the chain alone if empty")
+	chainID := flag.Uint64("chain-id", 0, "Chain ID of the EVM and the P2P handshake, overrides the genesis chain ID if set")
+	permissioned := flag.Bool("permissioned", false, "Only accept peers bound to an authority or listed in the allowlist")
+	allowlistPath := flag.String("allowlist", "", "Allowlist file for permissioned mode (reloaded on SIGHUP)")
+	solcPath := flag.String("solc", "", "solc binary for contract source verification (looked up in PATH if empty)")
+	keyType := flag.String("key-type", string(validator.KeyTypeSecp256k1), "Key type of generated validator keys: secp256k1 (an Ethereum account that can sign governance transactions) or p256")
+	validatorKey := flag.String("validator-key", "", "File with the hex encoded secp256k1 private key of the local validator (generated if empty)")
 	flag.Parse()
 
-	// Genesis validator'ı oluştur
-	genesisValidator, err := validator.NewAuthority()
+	if *syncMode != network.SyncModeFull && *syncMode != network.SyncModeSnapshot {
+		log.Fatalf("Geçersiz sync modu: %s", *syncMode)
+	}
+
+	// Genesis validator'ı oluştur; secp256k1 anahtarı verildiyse aynı hesap işlem de imzalayabilir
+	var genesisValidator *validator.Authority
+	var err error
+	if *validatorKey != "" {
+		key, keyErr := crypto.LoadECDSA(*validatorKey)
+		if keyErr != nil {
+			log.Fatal("Validator anahtarı yüklenemedi:", keyErr)
+		}
+		genesisValidator, err = validator.NewAuthority(key)
+	} else {
+		genesisValidator, err = validator.GenerateAuthority(validator.KeyType(*keyType))
+	}
 	if err != nil {
 		log.Fatal("Genesis validator oluşturulamadı:", err)
 	}
+	log.Printf("Genesis validator: %s (hesap %s, public key %s)", genesisValidator.Address, genesisValidator.Account().Hex(),
+		hexutil.Encode(genesisValidator.PublicKeyBytes()))
+
+	// Genesis: EVM chain ID ve fork kuralları
+	genesis := blockchain.DefaultGenesis()
+	if *genesisPath != "" {
+		if genesis, err = blockchain.LoadGenesis(*genesisPath); err != nil {
+			log.Fatal("Genesis yüklenemedi:", err)
+		}
+	}
+	if *chainID != 0 {
+		genesis.Config.ChainID = new(big.Int).SetUint64(*chainID)
+	}
 
 	// Blockchain'i başlat
-	bc, err := blockchain.NewBlockchain(genesisValidator)
+	bc, err := blockchain.NewBlockchainWithGenesis(genesisValidator, genesis)
 	if err != nil {
 		log.Fatal("Blockchain oluşturulamadı:", err)
 	}
 
-	// Test için ikinci bir validator ekle
-	validator2, err := validator.NewAuthority()
-	if err != nil {
-		log.Fatal("İkinci validator oluşturulamadı:", err)
+	// Checkpoint yüksekliklerinde state snapshot'ı üret
+	if *snapshotInterval > 0 {
+		bc.EnableSnapshots(blockchain.SnapshotConfig{Interval: *snapshotInterval, Keep: 2})
+	}
+
+	// Kaynak kod doğrulaması için solc; bulunamazsa yalnızca artifact doğrulaması yapılır
+	if compiler, err := contracts.NewSolcCompiler(*solcPath); err == nil {
+		bc.Compiler = compiler
+	} else {
+		log.Printf("Kaynak kod doğrulaması yalnızca artifact ile yapılabilir: %v", err)
+	}
+
+	// İzinli ağ modu için peer izinlerini yükle
+	nodeConfig := network.NodeConfig{ListenPort: *p2pPort, ChainID: genesis.Config.ChainID.Uint64(), SyncMode: *syncMode}
+	if *permissioned {
+		permissions, err := network.NewPeerPermissions(*allowlistPath, bc)
+		if err != nil {
+			log.Fatal("Peer izinleri yüklenemedi:", err)
+		}
+		nodeConfig.Permissions = permissions
 	}
-	bc.AddValidator(validator2)
 
 	// P2P node'unu başlat
-	node, err := network.NewNode(*p2pPort, bc)
+	node, err := network.NewNode(nodeConfig, bc)
 	if err != nil {
 		log.Fatal("P2P node oluşturulamadı:", err)
 	}
 	defer node.Close()
 
+	if node.IsPermissioned() {
+		// Diğer node'ların allowlist'ine eklenebilmesi için peer ID'yi imzala
+		signature, err := network.SignPeerID(genesisValidator, node.ID())
+		if err != nil {
+			log.Fatal("Peer ID imzalanamadı:", err)
+		}
+		log.Printf("Permissioned mode enabled. Allowlist entry for this node: {\"peer_id\": %q, \"authority\": %q, \"signature\": %q}",
+			node.ID().String(), genesisValidator.Address, signature)
+	}
+
 	// Bootstrap node'una bağlan
 	if *bootstrapNode != "" {
 		ctx := context.Background()
@@ -56,11 +126,15 @@ func main() {
 		}
 	}
 
+	// Diğer node'ların doğrudan bağlanabilmesi için authority kaydını yayınla
+	announceCtx, stopAnnouncements := context.WithCancel(context.Background())
+	defer stopAnnouncements()
+	node.StartValidatorAnnouncements(announceCtx, genesisValidator, time.Minute)
+
 	// HTTP API sunucusunu başlat
-	server := api.NewServer(bc)
+	server := api.NewServer(bc, node)
 	go func() {
 		log.Printf("Genesis Validator Address: %s", genesisValidator.Address)
-		log.Printf("Second Validator Address: %s", validator2.Address)
 		log.Printf("P2P Node Address: %s", node.GetMultiaddr())
 		log.Printf("HTTP API sunucusu başlatılıyor: http://localhost:%d", *apiPort)
 		if err := server.Run(fmt.Sprintf(":%d", *apiPort)); err != nil {
@@ -68,6 +142,34 @@ func main() {
 		}
 	}()
 
+	// Ethereum JSON-RPC sunucusunu başlat
+	if *rpcPort != 0 {
+		rpcServer, err := ethrpc.NewServer(bc, node, ethrpc.Config{AllowedOrigins: strings.Split(*rpcOrigins, ",")})
+		if err != nil {
+			log.Fatal("JSON-RPC sunucusu oluşturulamadı:", err)
+		}
+		defer rpcServer.Stop()
+		go func() {
+			log.Printf("JSON-RPC sunucusu başlatılıyor: http://localhost:%d (ws://localhost:%d)", *rpcPort, *rpcPort)
+			if err := rpcServer.Run(fmt.Sprintf(":%d", *rpcPort)); err != nil {
+				log.Fatal("JSON-RPC sunucusu başlatılamadı:", err)
+			}
+		}()
+	}
+
+	// SIGHUP ile allowlist'i yeniden yükle
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	go func() {
+		for range reloadChan {
+			if err := node.ReloadPermissions(); err != nil {
+				log.Printf("Allowlist yeniden yüklenemedi: %v", err)
+				continue
+			}
+			log.Println("Allowlist yeniden yüklendi")
+		}
+	}()
+
 	// Graceful shutdown için sinyal bekle
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -9,6 +9,9 @@ import (
 	"math/big"
 	"sync"
 	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // Status represents the validator status
@@ -20,6 +23,18 @@ const (
 	StatusPenalized
 )
 
+// KeyType is the elliptic curve of an authority's key
+type KeyType string
+
+const (
+	// KeyTypeP256 keys have the hex SHA-256 hash of the public key as address
+	KeyTypeP256 KeyType = "p256"
+
+	// KeyTypeSecp256k1 keys are Ethereum accounts: the address is the checksummed
+	// Keccak-256 address, usable as transaction sender and contract owner
+	KeyTypeSecp256k1 KeyType = "secp256k1"
+)
+
 // Authority represents a validator in the PoA system
 type Authority struct {
 	Address    string
@@ -37,7 +52,8 @@ type Authority struct {
 	ConsecutiveMisses uint64
 }
 
-// NewAuthority creates a new authority with a keypair
+// NewAuthority creates a new authority with a keypair. The key may be a P-256 or a
+// secp256k1 key; a new P-256 key is generated if it is nil.
 func NewAuthority(privateKey *ecdsa.PrivateKey) (*Authority, error) {
 	if privateKey == nil {
 		var err error
@@ -48,10 +64,12 @@ func NewAuthority(privateKey *ecdsa.PrivateKey) (*Authority, error) {
 	}
 
 	publicKey := &privateKey.PublicKey
-	address := fmt.Sprintf("%x", sha256.Sum256(elliptic.Marshal(elliptic.P256(), publicKey.X, publicKey.Y)))
+	if publicKey.Curve != elliptic.P256() && publicKey.Curve != crypto.S256() {
+		return nil, fmt.Errorf("unsupported key curve, want P-256 or secp256k1")
+	}
 
 	return &Authority{
-		Address:    address,
+		Address:    publicKeyAddress(publicKey),
 		PrivateKey: privateKey,
 		PublicKey:  publicKey,
 		Status:     StatusActive,
@@ -59,6 +77,107 @@ func NewAuthority(privateKey *ecdsa.PrivateKey) (*Authority, error) {
 	}, nil
 }
 
+// GenerateAuthority creates an authority with a new key of the given type
+func GenerateAuthority(keyType KeyType) (*Authority, error) {
+	switch keyType {
+	case KeyTypeP256:
+		return NewAuthority(nil)
+	case KeyTypeSecp256k1:
+		privateKey, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		return NewAuthority(privateKey)
+	}
+	return nil, fmt.Errorf("unknown key type %q, want %s or %s", keyType, KeyTypeP256, KeyTypeSecp256k1)
+}
+
+// NewAuthorityFromPublicKey creates an authority known only by its uncompressed P-256 or
+// secp256k1 public key. It can verify blocks but not sign them.
+func NewAuthorityFromPublicKey(publicKey []byte) (*Authority, error) {
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authority{
+		Address:    publicKeyAddress(key),
+		PublicKey:  key,
+		Status:     StatusActive,
+		LastActive: time.Now(),
+	}, nil
+}
+
+// ParsePublicKey decodes an uncompressed P-256 or secp256k1 public key
+func ParsePublicKey(publicKey []byte) (*ecdsa.PublicKey, error) {
+	if x, y := elliptic.Unmarshal(elliptic.P256(), publicKey); x != nil {
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	}
+	if key, err := crypto.UnmarshalPubkey(publicKey); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("invalid public key, want an uncompressed P-256 or secp256k1 key")
+}
+
+// AccountFromPublicKey returns the EVM account of an uncompressed P-256 or secp256k1 public key
+func AccountFromPublicKey(publicKey []byte) (common.Address, error) {
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return publicKeyAccount(key), nil
+}
+
+// publicKeyAddress returns the authority address of a public key
+func publicKeyAddress(publicKey *ecdsa.PublicKey) string {
+	if keyTypeOf(publicKey) == KeyTypeSecp256k1 {
+		return crypto.PubkeyToAddress(*publicKey).Hex()
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(marshalPublicKey(publicKey)))
+}
+
+// publicKeyAccount returns the EVM account of a public key: the Keccak address for secp256k1
+// keys, the last 20 bytes of the SHA-256 address for P-256 keys
+func publicKeyAccount(publicKey *ecdsa.PublicKey) common.Address {
+	if keyTypeOf(publicKey) == KeyTypeSecp256k1 {
+		return crypto.PubkeyToAddress(*publicKey)
+	}
+	hash := sha256.Sum256(marshalPublicKey(publicKey))
+	return common.BytesToAddress(hash[:])
+}
+
+// marshalPublicKey returns the uncompressed encoding of a public key
+func marshalPublicKey(publicKey *ecdsa.PublicKey) []byte {
+	if keyTypeOf(publicKey) == KeyTypeSecp256k1 {
+		return crypto.FromECDSAPub(publicKey)
+	}
+	return elliptic.Marshal(elliptic.P256(), publicKey.X, publicKey.Y)
+}
+
+// keyTypeOf returns the key type of a public key
+func keyTypeOf(publicKey *ecdsa.PublicKey) KeyType {
+	if publicKey.Curve == crypto.S256() {
+		return KeyTypeSecp256k1
+	}
+	return KeyTypeP256
+}
+
+// KeyType returns the type of the authority's key
+func (a *Authority) KeyType() KeyType {
+	return keyTypeOf(a.PublicKey)
+}
+
+// Account returns the EVM account of the authority. For secp256k1 authorities it is the
+// account of their Ethereum key, so the same identity signs blocks and transactions.
+func (a *Authority) Account() common.Address {
+	return publicKeyAccount(a.PublicKey)
+}
+
+// PublicKeyBytes returns the uncompressed encoding of the authority's public key
+func (a *Authority) PublicKeyBytes() []byte {
+	return marshalPublicKey(a.PublicKey)
+}
+
 // Sign signs a message with the authority's private key
 func (a *Authority) Sign(message []byte) ([]byte, error) {
 	a.mu.RLock()
@@ -67,6 +186,17 @@ func (a *Authority) Sign(message []byte) ([]byte, error) {
 	fmt.Printf("Signing message with validator %s\n", a.Address)
 	fmt.Printf("Message hash: %x\n", message)
 
+	// secp256k1 imzaları Ethereum biçimindedir: [R || S || V]
+	if a.KeyType() == KeyTypeSecp256k1 {
+		signature, err := crypto.Sign(message, a.PrivateKey)
+		if err != nil {
+			fmt.Printf("Failed to sign message: %v\n", err)
+			return nil, err
+		}
+		fmt.Printf("Generated signature: %x\n", signature)
+		return signature, nil
+	}
+
 	r, s, err := ecdsa.Sign(rand.Reader, a.PrivateKey, message)
 	if err != nil {
 		fmt.Printf("Failed to sign message: %v\n", err)
@@ -81,8 +211,12 @@ func (a *Authority) Sign(message []byte) ([]byte, error) {
 	return signature, nil
 }
 
-// Verify verifies a signature with the authority's public key
+// Verify verifies a signature with the authority's public key. secp256k1 signatures may
+// carry the recovery ID as 65th byte.
 func (a *Authority) Verify(message, signature []byte) bool {
+	if a.KeyType() == KeyTypeSecp256k1 && len(signature) == crypto.SignatureLength {
+		signature = signature[:64]
+	}
 	if len(signature) != 64 {
 		fmt.Printf("Invalid signature length: %d\n", len(signature))
 		return false
@@ -92,10 +226,14 @@ func (a *Authority) Verify(message, signature []byte) bool {
 	fmt.Printf("Message hash: %x\n", message)
 	fmt.Printf("Signature: %x\n", signature)
 
-	r := new(big.Int).SetBytes(signature[:32])
-	s := new(big.Int).SetBytes(signature[32:])
-
-	valid := ecdsa.Verify(a.PublicKey, message, r, s)
+	var valid bool
+	if a.KeyType() == KeyTypeSecp256k1 {
+		valid = crypto.VerifySignature(a.PublicKeyBytes(), message, signature)
+	} else {
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		valid = ecdsa.Verify(a.PublicKey, message, r, s)
+	}
 	if !valid {
 		fmt.Printf("Signature verification failed for validator %s\n", a.Address)
 	} else {
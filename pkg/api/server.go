@@ -3,22 +3,32 @@ package api
 import (
 	"net/http"
 	"fmt"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/SolidityDevSK/Confirmix/internal/validator"
 	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/SolidityDevSK/Confirmix/pkg/network"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"encoding/hex"
 )
 
 // Server represents the HTTP API server
 type Server struct {
 	blockchain *blockchain.Blockchain
+	node      *network.Node
 	router    *gin.Engine
 }
 
 // NewServer creates a new HTTP API server
-func NewServer(bc *blockchain.Blockchain) *Server {
+func NewServer(bc *blockchain.Blockchain, node *network.Node) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
 	
@@ -46,6 +56,7 @@ func NewServer(bc *blockchain.Blockchain) *Server {
 
 	server := &Server{
 		blockchain: bc,
+		node:      node,
 		router:    router,
 	}
 	server.setupRoutes()
@@ -64,25 +75,55 @@ func (s *Server) setupRoutes() {
 	s.router.GET("/blocks", s.getBlocks)
 	s.router.GET("/blocks/:hash", s.getBlockByHash)
 	s.router.GET("/transactions", s.getTransactions)
+	s.router.GET("/transactions/:hash/receipt", s.getReceipt)
+	s.router.GET("/transactions/:hash/trace", s.traceTransaction)
+	s.router.GET("/logs", s.getLogs)
 	
 	// Validator işlemleri
 	s.router.GET("/validators", s.getValidators)
 	s.router.GET("/validators/current", s.getCurrentValidator)
 	s.router.POST("/validators", s.addValidator)
 	s.router.DELETE("/validators/:address", s.removeValidator)
+	s.router.GET("/validators/records", s.getValidatorRecords)
 	
 	// İşlem gönderme
 	s.router.POST("/transactions", s.submitTransaction)
 
+	// P2P peer bilgileri
+	s.router.GET("/peers", s.getPeers)
+	s.router.POST("/peers/permissions/reload", s.reloadPeerPermissions)
+
 	// Akıllı kontrat endpoint'leri
-	contracts := s.router.Group("/contracts")
+	contractRoutes := s.router.Group("/contracts")
 	{
-		contracts.POST("", s.deployContract)
-		contracts.GET("", s.listContracts)
-		contracts.GET("/:address", s.getContract)
-		contracts.POST("/:address/execute", s.executeContract)
-		contracts.POST("/:address/disable", s.disableContract)
-		contracts.POST("/:address/enable", s.enableContract)
+		contractRoutes.POST("", s.deployContract)
+		contractRoutes.GET("", s.listContracts)
+		contractRoutes.GET("/deployments/:id", s.getDeployment)
+		contractRoutes.GET("/system", s.listSystemContracts)
+		contractRoutes.POST("/registry", s.createProxy)
+		contractRoutes.GET("/registry/:name", s.getContractVersions)
+		contractRoutes.POST("/registry/:name/upgrade", s.upgradeContract)
+		contractRoutes.GET("/:address", s.getContract)
+		contractRoutes.POST("/estimate-gas", s.estimateGas)
+		contractRoutes.POST("/validate", s.validateCode)
+		contractRoutes.POST("/trace", s.traceCall)
+		contractRoutes.POST("/:address/execute", s.executeContract)
+		contractRoutes.POST("/:address/call", s.callContract)
+		contractRoutes.POST("/:address/estimate-gas", s.estimateGas)
+		contractRoutes.POST("/:address/trace", s.traceCall)
+		contractRoutes.POST("/:address/methods/:method/call", s.callMethod)
+		contractRoutes.POST("/:address/methods/:method/execute", s.executeMethod)
+		contractRoutes.GET("/:address/events", s.getContractEvents)
+		contractRoutes.POST("/:address/verify", s.verifyContract)
+		contractRoutes.GET("/:address/source", s.getContractSource)
+		contractRoutes.GET("/:address/storage", s.getStorageRange)
+		contractRoutes.GET("/:address/storage/:slot", s.getStorageSlot)
+		contractRoutes.GET("/:address/code", s.getCode)
+		contractRoutes.POST("/:address/disable", s.contractAction(contracts.ActionDisable))
+		contractRoutes.POST("/:address/enable", s.contractAction(contracts.ActionEnable))
+		contractRoutes.POST("/:address/transfer-ownership", s.contractAction(contracts.ActionTransferOwnership))
+		contractRoutes.POST("/:address/roles/grant", s.contractAction(contracts.ActionGrantRole))
+		contractRoutes.POST("/:address/roles/revoke", s.contractAction(contracts.ActionRevokeRole))
 	}
 }
 
@@ -100,7 +141,7 @@ func (s *Server) getBlockchainInfo(c *gin.Context) {
 		"current_block":      len(s.blockchain.Blocks) - 1,
 		"active_validators":  s.blockchain.GetActiveValidatorCount(),
 		"validator_count":    len(s.blockchain.Validators),
-		"pending_transactions": 0, // TODO: Add mempool integration
+		"pending_transactions": s.blockchain.Mempool.Count(),
 	}
 
 	fmt.Printf("[API] Sending blockchain info: %+v\n", info)
@@ -134,14 +175,26 @@ func (s *Server) getTransactions(c *gin.Context) {
 	
 	for _, block := range s.blockchain.Blocks[startBlock:] {
 		for _, tx := range block.Transactions {
+			txType := "transfer"
+			if tx.IsContractCreation() {
+				txType = "contract_deployment"
+			} else if len(tx.Data) > 0 {
+				txType = "contract_call"
+			}
+
+			status := "success"
+			if receipt, exists := s.blockchain.GetReceipt(hex.EncodeToString(tx.Hash)); exists && receipt.Status == blockchain.TxFailed {
+				status = "failed"
+			}
+
 			transactions = append(transactions, gin.H{
 				"hash": hex.EncodeToString(tx.Hash),
 				"from": tx.From,
 				"to": tx.To,
 				"value": tx.Value.String(),
-				"type": "transfer", // Default to transfer type for now
+				"type": txType,
 				"timestamp": block.Header.Timestamp.Unix() * 1000,
-				"status": "success", // Default to success for now
+				"status": status,
 			})
 		}
 	}
@@ -149,6 +202,27 @@ func (s *Server) getTransactions(c *gin.Context) {
 	c.JSON(http.StatusOK, transactions)
 }
 
+// getReceipt returns the receipt of an included transaction
+func (s *Server) getReceipt(c *gin.Context) {
+	hash, err := hex.DecodeString(c.Param("hash"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction hash"})
+		return
+	}
+
+	receipt, exists := s.blockchain.GetReceipt(c.Param("hash"))
+	if !exists {
+		// İşlem henüz bir bloğa dahil edilmemiş olabilir
+		if s.blockchain.Mempool.GetTransaction(hash) != nil {
+			c.JSON(http.StatusAccepted, gin.H{"status": "pending"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Receipt not found"})
+		return
+	}
+	c.JSON(http.StatusOK, receipt)
+}
+
 // getBlockByHash returns a specific block by its hash
 func (s *Server) getBlockByHash(c *gin.Context) {
 	hash := c.Param("hash")
@@ -180,31 +254,126 @@ func (s *Server) getCurrentValidator(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"address": v.Address})
 }
 
-// addValidator adds a new validator to the blockchain
+// validatorChangeRequest is the body of the endpoints changing the validator set
+type validatorChangeRequest struct {
+	From      string `json:"from" binding:"required"` // Validator account sending the transaction
+	PublicKey string `json:"public_key"`              // Uncompressed public key of the validator to add (hex)
+	GasLimit  uint64 `json:"gas_limit" binding:"required"`
+	GasPrice  uint64 `json:"gas_price"`
+
+	signedFields
+}
+
+// addValidator submits a transaction adding a validator to the validator set system
+// contract, signed by a current validator
 func (s *Server) addValidator(c *gin.Context) {
-	// Create a new private key for the validator
-	newValidator, err := validator.NewAuthority(nil)
+	var req validatorChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	publicKey, err := hexutil.Decode(req.PublicKey)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid public key"})
 		return
 	}
-	
-	s.blockchain.AddValidator(newValidator)
-	c.JSON(http.StatusCreated, gin.H{
-		"address": newValidator.Address,
-		"message": "Validator added successfully",
-	})
+	if _, err := contracts.ValidatorAccount(publicKey); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := contracts.PackAddValidator(publicKey)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s.submitValidatorChange(c, &req, data)
 }
 
-// removeValidator removes a validator from the blockchain
+// removeValidator submits a transaction removing a validator from the validator set system
+// contract, signed by a current validator. The validator is given by its address or account.
 func (s *Server) removeValidator(c *gin.Context) {
-	address := c.Param("address")
-	s.blockchain.RemoveValidator(address)
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Validator removed successfully",
+	var req validatorChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// P-256 validator adresleri 32 baytlık hash'tir, hesapları son 20 baytıdır
+	address := common.FromHex(c.Param("address"))
+	if len(address) != common.AddressLength && len(address) != common.HashLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid validator address"})
+		return
+	}
+
+	data, err := contracts.PackRemoveValidator(common.BytesToAddress(address))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s.submitValidatorChange(c, &req, data)
+}
+
+// submitValidatorChange submits a signed transaction to the validator set system contract.
+// The change takes effect when a block including it is added.
+func (s *Server) submitValidatorChange(c *gin.Context, req *validatorChangeRequest, data []byte) {
+	if !common.IsHexAddress(req.From) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sender address"})
+		return
+	}
+
+	tx := &blockchain.Transaction{
+		From:     common.HexToAddress(req.From).Hex(),
+		To:       contracts.ValidatorSetAddress.Hex(),
+		Data:     data,
+		GasLimit: req.GasLimit,
+		GasPrice: req.GasPrice,
+	}
+	if !s.signTransaction(c, tx, req.signedFields) {
+		return
+	}
+	if err := s.blockchain.SubmitTransaction(tx); err != nil {
+		respondTransactionError(c, tx, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Validator change submitted",
+		"tx_hash": hex.EncodeToString(tx.Hash),
+		"nonce":   tx.Nonce,
 	})
 }
 
+// getValidatorRecords returns the signed records announced by authorities
+func (s *Server) getValidatorRecords(c *gin.Context) {
+	if s.node == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "P2P node is not running"})
+		return
+	}
+	c.JSON(http.StatusOK, s.node.GetValidatorRecords())
+}
+
+// getPeers returns the known peers with their scores and ban state
+func (s *Server) getPeers(c *gin.Context) {
+	if s.node == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "P2P node is not running"})
+		return
+	}
+	c.JSON(http.StatusOK, s.node.GetPeers())
+}
+
+// reloadPeerPermissions reloads the allowlist of a permissioned node
+func (s *Server) reloadPeerPermissions(c *gin.Context) {
+	if s.node == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "P2P node is not running"})
+		return
+	}
+	if err := s.node.ReloadPermissions(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Peer permissions reloaded"})
+}
+
 // TransactionRequest represents a new transaction request
 type TransactionRequest struct {
 	Data      string `json:"data" binding:"required"`
@@ -226,15 +395,8 @@ func (s *Server) submitTransaction(c *gin.Context) {
 		return
 	}
 
-	// Create a new block
-	prevBlock := s.blockchain.GetLatestBlock()
-	newBlock, err := blockchain.NewBlock(
-		prevBlock.Header.Height + 1,
-		prevBlock.GetHash(),
-		prevBlock.Header.StateRoot,
-		1000000, // Default gas limit
-		v,
-	)
+	// Mempool'daki bekleyen işlemlerle yeni bir blok oluştur
+	newBlock, err := s.blockchain.CreateBlock(v)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -252,13 +414,68 @@ func (s *Server) submitTransaction(c *gin.Context) {
 	})
 }
 
+// signedFields are the nonce and the sender's signature of the requests submitting a
+// transaction. The signature is personal_sign over the transaction hash; a request without
+// it is answered with the hash to sign.
+type signedFields struct {
+	Nonce     *uint64 `json:"nonce"`     // Next nonce of the sender if omitted
+	Signature string  `json:"signature"` // personal_sign signature of the transaction hash (hex)
+}
+
+// signTransaction sets the nonce and the signature of a transaction built from a request
+func (s *Server) signTransaction(c *gin.Context, tx *blockchain.Transaction, fields signedFields) bool {
+	if fields.Nonce != nil {
+		tx.Nonce = *fields.Nonce
+	} else {
+		tx.Nonce = s.blockchain.GetNonce(tx.From)
+	}
+	if fields.Signature == "" {
+		return true
+	}
+
+	signature, err := hexutil.Decode(fields.Signature)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature"})
+		return false
+	}
+	tx.Signature = signature
+	return true
+}
+
+// respondTransactionError writes the error of a rejected transaction. Unsigned transactions
+// are answered with the hash and nonce the sender has to sign.
+func respondTransactionError(c *gin.Context, tx *blockchain.Transaction, err error) {
+	switch {
+	case errors.Is(err, blockchain.ErrUnsignedTransaction):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   err.Error(),
+			"tx_hash": hex.EncodeToString(tx.CalculateHash()),
+			"nonce":   tx.Nonce,
+		})
+	case errors.Is(err, blockchain.ErrInvalidSender), errors.Is(err, contracts.ErrDeployerNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
 // deployContract handles contract deployment
 func (s *Server) deployContract(c *gin.Context) {
 	var req struct {
-		Code    string `json:"code" binding:"required"`
-		Owner   string `json:"owner" binding:"required"`
-		Name    string `json:"name" binding:"required"`
-		Version string `json:"version" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+		Args     string `json:"args"`
+		Salt     string `json:"salt"`
+		Owner    string `json:"owner" binding:"required"`
+		Name     string `json:"name" binding:"required"`
+		Version  string `json:"version" binding:"required"`
+		GasLimit uint64 `json:"gas_limit" binding:"required"`
+		GasPrice uint64 `json:"gas_price"`
+
+		// ABI verilirse kontratla saklanır, constructor argümanları JSON olarak verilebilir
+		ABI             json.RawMessage   `json:"abi"`
+		ConstructorArgs []json.RawMessage `json:"constructor_args"`
+
+		signedFields
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -273,17 +490,214 @@ func (s *Server) deployContract(c *gin.Context) {
 		return
 	}
 
-	// Owner adresini parse et
-	owner := common.HexToAddress(req.Owner)
+	// Constructor argümanları init code'un sonuna eklenir
+	args, err := hex.DecodeString(req.Args)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid constructor arguments"})
+		return
+	}
+	if len(req.ABI) > 0 {
+		contractABI, err := contracts.ParseABI(req.ABI)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.ConstructorArgs != nil {
+			if len(args) > 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "args and constructor_args cannot be combined"})
+				return
+			}
+			if args, err = contracts.EncodeConstructor(contractABI, req.ConstructorArgs); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	} else if req.ConstructorArgs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "constructor_args require an abi"})
+		return
+	}
+	code = append(code, args...)
+
+	salt, err := hex.DecodeString(req.Salt)
+	if err != nil || len(salt) > common.HashLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid salt"})
+		return
+	}
+
+	if !common.IsHexAddress(req.Owner) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid owner address"})
+		return
+	}
+
+	// Deploy işlemi owner tarafından imzalanır
+	tx := &blockchain.Transaction{
+		From:            common.HexToAddress(req.Owner).Hex(),
+		Data:            code,
+		GasLimit:        req.GasLimit,
+		GasPrice:        req.GasPrice,
+		ContractName:    req.Name,
+		ContractVersion: req.Version,
+		Salt:            salt,
+		ContractABI:     string(req.ABI),
+	}
+	if !s.signTransaction(c, tx, req.signedFields) {
+		return
+	}
 
-	// Kontratı deploy et
-	contract, err := s.blockchain.DeployContract(code, owner, req.Name, req.Version)
+	// Deploy arka planda doğrulanıp mempool'a gönderilir, kontrat blok işlenirken oluşturulur
+	deployment, err := s.blockchain.DeployContract(tx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondTransactionError(c, tx, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, contract)
+	c.JSON(http.StatusAccepted, deployment)
+}
+
+// validateCode runs the deploy-time checks on runtime bytecode
+func (s *Server) validateCode(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"` // Runtime code (hex)
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	code, err := hex.DecodeString(strings.TrimPrefix(req.Code, "0x"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid contract code"})
+		return
+	}
+
+	issues := []contracts.ValidationIssue{}
+	var validationErr *contracts.ValidationError
+	if err := s.blockchain.ContractManager.ValidateRuntimeCode(code); errors.As(err, &validationErr) {
+		issues = validationErr.Issues
+	}
+
+	c.JSON(http.StatusOK, gin.H{"valid": len(issues) == 0, "issues": issues})
+}
+
+// proxyRequest is the body of the proxy creation and upgrade endpoints
+type proxyRequest struct {
+	Owner          string `json:"owner" binding:"required"`
+	Name           string `json:"name"`
+	Version        string `json:"version"` // Version of the implementation if omitted
+	Implementation string `json:"implementation" binding:"required"`
+	GasLimit       uint64 `json:"gas_limit" binding:"required"`
+	GasPrice       uint64 `json:"gas_price"`
+
+	signedFields
+}
+
+// bindProxyRequest parses a proxy request and validates its addresses
+func bindProxyRequest(c *gin.Context) (*proxyRequest, bool) {
+	var req proxyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if !common.IsHexAddress(req.Owner) || !common.IsHexAddress(req.Implementation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid owner or implementation address"})
+		return nil, false
+	}
+	return &req, true
+}
+
+// transaction builds the proxy transaction of the request, sent by the owner
+func (req *proxyRequest) transaction() *blockchain.Transaction {
+	return &blockchain.Transaction{
+		From:            common.HexToAddress(req.Owner).Hex(),
+		ContractVersion: req.Version,
+		Implementation:  common.HexToAddress(req.Implementation).Hex(),
+		GasLimit:        req.GasLimit,
+		GasPrice:        req.GasPrice,
+	}
+}
+
+// createProxy handles the creation of an upgradeable proxy registered under a name
+func (s *Server) createProxy(c *gin.Context) {
+	req, ok := bindProxyRequest(c)
+	if !ok {
+		return
+	}
+
+	tx := req.transaction()
+	tx.ContractName = req.Name
+	if !s.signTransaction(c, tx, req.signedFields) {
+		return
+	}
+	if err := s.blockchain.CreateProxy(tx); err != nil {
+		respondTransactionError(c, tx, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Proxy creation submitted",
+		"tx_hash": hex.EncodeToString(tx.Hash),
+		"nonce":   tx.Nonce,
+	})
+}
+
+// upgradeContract handles repointing a named proxy to a new implementation
+func (s *Server) upgradeContract(c *gin.Context) {
+	req, ok := bindProxyRequest(c)
+	if !ok {
+		return
+	}
+
+	// İşlem proxy adresine gönderilir
+	tx := req.transaction()
+	proxy, err := s.blockchain.GetContractVersions(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	tx.To = proxy.Address.Hex()
+	if !s.signTransaction(c, tx, req.signedFields) {
+		return
+	}
+	if err := s.blockchain.UpgradeContract(c.Param("name"), tx); err != nil {
+		respondTransactionError(c, tx, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Contract upgrade submitted",
+		"tx_hash": hex.EncodeToString(tx.Hash),
+		"nonce":   tx.Nonce,
+	})
+}
+
+// getContractVersions returns the proxy registered under a name with its version history
+func (s *Server) getContractVersions(c *gin.Context) {
+	proxy, err := s.blockchain.GetContractVersions(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":            proxy.Name,
+		"proxy":           proxy.Address.Hex(),
+		"owner":           proxy.Owner.Hex(),
+		"current_version": proxy.Version,
+		"implementation":  proxy.Versions[len(proxy.Versions)-1].Implementation.Hex(),
+		"versions":        proxy.Versions,
+	})
+}
+
+// getDeployment returns the status of an asynchronous contract deployment
+func (s *Server) getDeployment(c *gin.Context) {
+	deployment, err := s.blockchain.GetDeployment(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deployment)
 }
 
 // listContracts handles contract listing
@@ -292,6 +706,11 @@ func (s *Server) listContracts(c *gin.Context) {
 	c.JSON(http.StatusOK, contracts)
 }
 
+// listSystemContracts returns the system contracts with their addresses and ABIs
+func (s *Server) listSystemContracts(c *gin.Context) {
+	c.JSON(http.StatusOK, contracts.SystemContracts())
+}
+
 // getContract handles contract retrieval
 func (s *Server) getContract(c *gin.Context) {
 	address := common.HexToAddress(c.Param("address"))
@@ -309,7 +728,12 @@ func (s *Server) executeContract(c *gin.Context) {
 	address := common.HexToAddress(c.Param("address"))
 
 	var req struct {
-		Input string `json:"input" binding:"required"`
+		From     string `json:"from" binding:"required"`
+		Input    string `json:"input" binding:"required"`
+		GasLimit uint64 `json:"gas_limit" binding:"required"`
+		GasPrice uint64 `json:"gas_price"`
+
+		signedFields
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -324,58 +748,655 @@ func (s *Server) executeContract(c *gin.Context) {
 		return
 	}
 
-	// Kontratı çalıştır
-	result, err := s.blockchain.ExecuteContract(address, input)
+	if !common.IsHexAddress(req.From) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sender address"})
+		return
+	}
+
+	// Kontrat çağrısını gönderenin imzasıyla işlem olarak mempool'a gönder
+	tx := &blockchain.Transaction{
+		From:     common.HexToAddress(req.From).Hex(),
+		To:       address.Hex(),
+		Data:     input,
+		GasLimit: req.GasLimit,
+		GasPrice: req.GasPrice,
+	}
+	if !s.signTransaction(c, tx, req.signedFields) {
+		return
+	}
+	if err := s.blockchain.ExecuteContract(tx); err != nil {
+		respondTransactionError(c, tx, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Contract call submitted",
+		"tx_hash": hex.EncodeToString(tx.Hash),
+		"nonce":   tx.Nonce,
+	})
+}
+
+// verifyContract handles contract source verification
+func (s *Server) verifyContract(c *gin.Context) {
+	address := common.HexToAddress(c.Param("address"))
+
+	var req contracts.VerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contract, err := s.blockchain.VerifyContract(address, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := http.StatusBadRequest
+		if errors.Is(err, contracts.ErrBytecodeMismatch) {
+			status = http.StatusUnprocessableEntity
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contract.Source)
+}
+
+// getContractSource returns the verified source of a contract
+func (s *Server) getContractSource(c *gin.Context) {
+	address := common.HexToAddress(c.Param("address"))
+	contract, err := s.blockchain.GetContract(address)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if contract.Source == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "contract source is not verified"})
+		return
+	}
+
+	c.JSON(http.StatusOK, contract.Source)
+}
+
+// stateQuery parses the address path parameter and the optional block_height query
+// parameter of the state inspection endpoints
+func stateQuery(c *gin.Context) (common.Address, *uint64, error) {
+	if !common.IsHexAddress(c.Param("address")) {
+		return common.Address{}, nil, errors.New("invalid address")
+	}
+	height, err := heightQuery(c, "block_height")
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return common.HexToAddress(c.Param("address")), height, nil
+}
+
+// parseSlot parses a storage slot given as hex, with or without 0x, of at most 32 bytes
+func parseSlot(value string) (common.Hash, error) {
+	value = strings.TrimPrefix(value, "0x")
+	if len(value)%2 == 1 {
+		value = "0" + value
+	}
+	slot, err := hex.DecodeString(value)
+	if err != nil || len(slot) > common.HashLength {
+		return common.Hash{}, errors.New("invalid storage slot")
+	}
+	return common.BytesToHash(slot), nil
+}
+
+// getStorageSlot returns a storage slot of an account at a block height
+func (s *Server) getStorageSlot(c *gin.Context) {
+	address, height, err := stateQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	slot, err := parseSlot(c.Param("slot"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	value, err := s.blockchain.GetStorageAt(address, slot, height)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.StorageEntry{Slot: slot, Value: value})
+}
+
+// getStorageRange returns the storage slots of an account in slot order, starting at
+// the start slot, at a block height
+func (s *Server) getStorageRange(c *gin.Context) {
+	address, height, err := stateQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	start, err := parseSlot(c.Query("start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit := 100
+	if value := c.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	storage, err := s.blockchain.StorageRangeAt(address, start, limit, height)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, storage)
+}
+
+// getCode returns the code of an account at a block height
+func (s *Server) getCode(c *gin.Context) {
+	address, height, err := stateQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	code, err := s.blockchain.GetCodeAt(address, height)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"result": hex.EncodeToString(result),
+		"address":   address,
+		"code":      hex.EncodeToString(code),
+		"code_hash": crypto.Keccak256Hash(code),
 	})
 }
 
-// disableContract handles contract disabling
-func (s *Server) disableContract(c *gin.Context) {
-	address := common.HexToAddress(c.Param("address"))
+// contractActionRequest is the body of the contract management endpoints
+type contractActionRequest struct {
+	From     string         `json:"from" binding:"required"`
+	Account  string         `json:"account"` // New owner, or the account of a role change
+	Role     contracts.Role `json:"role"`
+	GasLimit uint64         `json:"gas_limit" binding:"required"`
+	GasPrice uint64         `json:"gas_price"`
 
-	var req struct {
-		Owner string `json:"owner" binding:"required"`
+	signedFields
+}
+
+// contractAction returns a handler submitting a management action as a transaction to the
+// contract access system contract, signed by the contract owner or a role holder
+func (s *Server) contractAction(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req contractActionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !common.IsHexAddress(req.From) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sender address"})
+			return
+		}
+		if req.Account != "" && !common.IsHexAddress(req.Account) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account address"})
+			return
+		}
+
+		address := common.HexToAddress(c.Param("address"))
+		if _, err := s.blockchain.GetContract(address); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		data, err := contracts.PackContractAction(action, address, common.HexToAddress(req.Account), req.Role)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tx := &blockchain.Transaction{
+			From:     common.HexToAddress(req.From).Hex(),
+			To:       contracts.ContractAccessAddress.Hex(),
+			Data:     data,
+			GasLimit: req.GasLimit,
+			GasPrice: req.GasPrice,
+		}
+		if !s.signTransaction(c, tx, req.signedFields) {
+			return
+		}
+		if err := s.blockchain.ExecuteContract(tx); err != nil {
+			respondTransactionError(c, tx, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Contract action submitted",
+			"tx_hash": hex.EncodeToString(tx.Hash),
+			"nonce":   tx.Nonce,
+		})
 	}
+}
 
+// simulationRequest is the body of the call and gas estimation endpoints
+type simulationRequest struct {
+	From        string  `json:"from"`
+	Input       string  `json:"input"` // Call data, or init code for deployments (hex)
+	Args        string  `json:"args"`  // Constructor arguments for deployments (hex)
+	Salt        string  `json:"salt"`
+	Value       string  `json:"value"` // Wei (decimal)
+	GasLimit    uint64  `json:"gas_limit"`
+	GasPrice    uint64  `json:"gas_price"`
+	BlockHeight *uint64 `json:"block_height"` // Latest state if omitted
+}
+
+// parseSimulation builds the message to simulate; without an address in the path it is a deployment
+func (s *Server) parseSimulation(c *gin.Context) (*contracts.Message, *uint64, error) {
+	var req simulationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, nil, err
+	}
+
+	msg := &contracts.Message{
+		Value:    new(big.Int),
+		GasLimit: req.GasLimit,
+		GasPrice: new(big.Int).SetUint64(req.GasPrice),
+	}
+	if req.From != "" {
+		if !common.IsHexAddress(req.From) {
+			return nil, nil, fmt.Errorf("invalid sender address")
+		}
+		msg.From = common.HexToAddress(req.From)
+	}
+	if req.Value != "" {
+		if _, ok := msg.Value.SetString(req.Value, 10); !ok || msg.Value.Sign() < 0 {
+			return nil, nil, fmt.Errorf("invalid value")
+		}
+	}
+
+	input, err := hex.DecodeString(req.Input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid input data")
+	}
+	msg.Data = input
+
+	if address := c.Param("address"); address != "" {
+		to := common.HexToAddress(address)
+		msg.To = &to
+		return msg, req.BlockHeight, nil
+	}
+
+	// Deploy simülasyonu
+	args, err := hex.DecodeString(req.Args)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid constructor arguments")
+	}
+	msg.Data = append(msg.Data, args...)
+
+	salt, err := hex.DecodeString(req.Salt)
+	if err != nil || len(salt) > common.HashLength {
+		return nil, nil, fmt.Errorf("invalid salt")
+	}
+	if len(salt) > 0 {
+		hash := common.BytesToHash(salt)
+		msg.Salt = &hash
+	}
+	return msg, req.BlockHeight, nil
+}
+
+// callContract executes a contract call against a block's state without submitting a transaction
+func (s *Server) callContract(c *gin.Context) {
+	msg, height, err := s.parseSimulation(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	owner := common.HexToAddress(req.Owner)
-	if err := s.blockchain.ContractManager.DisableContract(address, owner); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	result, err := s.blockchain.Call(msg, height)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.Status(http.StatusOK)
+	response := gin.H{
+		"return_data": hex.EncodeToString(result.ReturnData),
+		"gas_used":    result.GasUsed,
+	}
+	if result.Failed() {
+		response["error"] = result.Err.Error()
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
-// enableContract handles contract enabling
-func (s *Server) enableContract(c *gin.Context) {
-	address := common.HexToAddress(c.Param("address"))
+// estimateGas returns the gas limit a deployment or contract call needs to succeed
+func (s *Server) estimateGas(c *gin.Context) {
+	msg, height, err := s.parseSimulation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	gas, err := s.blockchain.EstimateGas(msg, height)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"gas": gas})
+}
+
+// traceConfigQuery parses the tracer and struct logger options from the query
+func traceConfigQuery(c *gin.Context) (*contracts.TraceConfig, error) {
+	config := &contracts.TraceConfig{
+		Tracer:         c.Query("tracer"),
+		DisableStack:   c.Query("disable_stack") == "true",
+		DisableMemory:  c.Query("disable_memory") == "true",
+		DisableStorage: c.Query("disable_storage") == "true",
+	}
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid limit")
+		}
+		config.Limit = value
+	}
+	return config, nil
+}
+
+// traceTransaction re-executes an included contract transaction and returns its trace.
+// Query: tracer (structLogger, callTracer or prestateTracer) and the struct logger options.
+func (s *Server) traceTransaction(c *gin.Context) {
+	config, err := traceConfigQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trace, err := s.blockchain.TraceTransaction(c.Param("hash"), config)
+	if err != nil {
+		status := http.StatusUnprocessableEntity
+		if errors.Is(err, contracts.ErrUnknownTracer) {
+			status = http.StatusBadRequest
+		} else if _, exists := s.blockchain.GetReceipt(c.Param("hash")); !exists {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, trace)
+}
+
+// traceCall simulates a deployment or contract call like callContract and returns its trace
+func (s *Server) traceCall(c *gin.Context) {
+	config, err := traceConfigQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	msg, height, err := s.parseSimulation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, trace, err := s.blockchain.TraceCall(msg, height, config)
+	if err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, contracts.ErrUnknownTracer) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, trace)
+}
+
+// getLogs returns contract logs filtered by block range, address and topics.
+// Query: from_block, to_block, address (comma separated) and topic0..topic3
+// (comma separated alternatives for each position).
+func (s *Server) getLogs(c *gin.Context) {
+	filter := &blockchain.LogFilter{}
+
+	var err error
+	if filter.FromBlock, err = heightQuery(c, "from_block"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.ToBlock, err = heightQuery(c, "to_block"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if value := c.Query("address"); value != "" {
+		for _, address := range strings.Split(value, ",") {
+			if !common.IsHexAddress(address) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid address"})
+				return
+			}
+			filter.Addresses = append(filter.Addresses, common.HexToAddress(address))
+		}
+	}
+
+	// Boş pozisyonlar her topic ile eşleşir
+	for i := 0; i < 4; i++ {
+		value := c.Query(fmt.Sprintf("topic%d", i))
+		var topics []common.Hash
+		if value != "" {
+			for _, topic := range strings.Split(value, ",") {
+				hash, err := hexutil.Decode(topic)
+				if err != nil || len(hash) != common.HashLength {
+					c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid topic%d", i)})
+					return
+				}
+				topics = append(topics, common.BytesToHash(hash))
+			}
+		}
+		filter.Topics = append(filter.Topics, topics)
+	}
+	for len(filter.Topics) > 0 && len(filter.Topics[len(filter.Topics)-1]) == 0 {
+		filter.Topics = filter.Topics[:len(filter.Topics)-1]
+	}
+
+	logs, err := s.blockchain.GetLogs(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+// heightQuery parses an optional block height query parameter
+func heightQuery(c *gin.Context, name string) (*uint64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	height, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", name)
+	}
+	return &height, nil
+}
+
+// contractABI returns the contract at the address in the path and its parsed ABI
+func (s *Server) contractABI(c *gin.Context) (*contracts.Contract, *abi.ABI, bool) {
+	contract, err := s.blockchain.GetContract(common.HexToAddress(c.Param("address")))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	contractABI, err := contract.ParsedABI()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	return contract, contractABI, true
+}
+
+// callMethod simulates an ABI method call and returns the decoded outputs
+func (s *Server) callMethod(c *gin.Context) {
+	contract, contractABI, ok := s.contractABI(c)
+	if !ok {
+		return
+	}
 
 	var req struct {
-		Owner string `json:"owner" binding:"required"`
+		From        string            `json:"from"`
+		Args        []json.RawMessage `json:"args"`
+		GasLimit    uint64            `json:"gas_limit"`
+		BlockHeight *uint64           `json:"block_height"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.From != "" && !common.IsHexAddress(req.From) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sender address"})
+		return
+	}
+
+	method := c.Param("method")
+	input, err := contracts.EncodeCall(contractABI, method, req.Args)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
+	result, err := s.blockchain.Call(&contracts.Message{
+		From:     common.HexToAddress(req.From),
+		To:       &contract.Address,
+		Data:     input,
+		GasLimit: req.GasLimit,
+	}, req.BlockHeight)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if result.Failed() {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":       result.Err.Error(),
+			"return_data": hex.EncodeToString(result.ReturnData),
+			"gas_used":    result.GasUsed,
+		})
+		return
+	}
+
+	outputs, err := contracts.DecodeOutput(contractABI, method, result.ReturnData)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"outputs":  outputs,
+		"gas_used": result.GasUsed,
+	})
+}
+
+// executeMethod encodes an ABI method call and submits it as a transaction
+func (s *Server) executeMethod(c *gin.Context) {
+	contract, contractABI, ok := s.contractABI(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		From     string            `json:"from" binding:"required"`
+		Args     []json.RawMessage `json:"args"`
+		GasLimit uint64            `json:"gas_limit" binding:"required"`
+		GasPrice uint64            `json:"gas_price"`
+
+		signedFields
+	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !common.IsHexAddress(req.From) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sender address"})
+		return
+	}
 
-	owner := common.HexToAddress(req.Owner)
-	if err := s.blockchain.ContractManager.EnableContract(address, owner); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	input, err := contracts.EncodeCall(contractABI, c.Param("method"), req.Args)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.Status(http.StatusOK)
-} 
\ No newline at end of file
+	tx := &blockchain.Transaction{
+		From:     common.HexToAddress(req.From).Hex(),
+		To:       contract.Address.Hex(),
+		Data:     input,
+		GasLimit: req.GasLimit,
+		GasPrice: req.GasPrice,
+	}
+	if !s.signTransaction(c, tx, req.signedFields) {
+		return
+	}
+	if err := s.blockchain.ExecuteContract(tx); err != nil {
+		respondTransactionError(c, tx, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Contract call submitted",
+		"tx_hash": hex.EncodeToString(tx.Hash),
+		"nonce":   tx.Nonce,
+	})
+}
+
+// getContractEvents returns the contract's logs in a block range decoded with its ABI.
+// Query: from_block, to_block and event (event name).
+func (s *Server) getContractEvents(c *gin.Context) {
+	contract, contractABI, ok := s.contractABI(c)
+	if !ok {
+		return
+	}
+
+	filter := &blockchain.LogFilter{Addresses: []common.Address{contract.Address}}
+	var err error
+	if filter.FromBlock, err = heightQuery(c, "from_block"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.ToBlock, err = heightQuery(c, "to_block"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if name := c.Query("event"); name != "" {
+		event, exists := contractABI.Events[name]
+		if !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("event %s not found in ABI", name)})
+			return
+		}
+		filter.Topics = [][]common.Hash{{event.ID}}
+	}
+
+	logs, err := s.blockchain.GetLogs(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events := make([]gin.H, 0, len(logs))
+	for _, log := range logs {
+		event := gin.H{
+			"block_number": log.BlockNumber,
+			"tx_hash":      log.TxHash.Hex(),
+			"log_index":    log.Index,
+		}
+		// ABI'de olmayan log'lar ham haliyle döner
+		if name, args, err := contracts.DecodeLog(contractABI, log); err == nil {
+			event["event"] = name
+			event["args"] = args
+		} else {
+			event["log"] = log
+		}
+		events = append(events, event)
+	}
+
+	c.JSON(http.StatusOK, events)
+}
@@ -23,6 +23,7 @@ type Header struct {
 	GasLimit        uint64    // Block gas limit
 	GasUsed         uint64    // Total gas used by transactions
 	ValidatorAddress string    // Block producer address
+	LogsBloom       []byte    `json:",omitempty"` // Bloom filter over the addresses and topics of the block's logs
 }
 
 // Block represents a block in the blockchain
@@ -193,21 +194,31 @@ func (b *Block) calculateTransactionRoot() ([]byte, error) {
 		txHashes = append(txHashes, txHash[:])
 	}
 
+	return merkleRoot(txHashes), nil
+}
+
+// merkleRoot builds a merkle tree over the given leaf hashes and returns its root
+func merkleRoot(hashes [][]byte) []byte {
+	if len(hashes) == 0 {
+		empty := sha256.Sum256(nil)
+		return empty[:]
+	}
+
 	// Merkle ağacı oluştur
-	for len(txHashes) > 1 {
-		if len(txHashes)%2 == 1 {
-			txHashes = append(txHashes, txHashes[len(txHashes)-1])
+	for len(hashes) > 1 {
+		if len(hashes)%2 == 1 {
+			hashes = append(hashes, hashes[len(hashes)-1])
 		}
 		var temp [][]byte
-		for i := 0; i < len(txHashes); i += 2 {
-			combined := append(txHashes[i], txHashes[i+1]...)
+		for i := 0; i < len(hashes); i += 2 {
+			combined := append(append([]byte{}, hashes[i]...), hashes[i+1]...)
 			hash := sha256.Sum256(combined)
 			temp = append(temp, hash[:])
 		}
-		txHashes = temp
+		hashes = temp
 	}
 
-	return txHashes[0], nil
+	return hashes[0]
 }
 
 // Verify verifies the block's signature
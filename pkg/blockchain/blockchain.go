@@ -5,13 +5,28 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"testing"
 	"time"
 
 	"github.com/SolidityDevSK/Confirmix/internal/validator"
 	"github.com/SolidityDevSK/Confirmix/pkg/consensus"
 	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/SolidityDevSK/Confirmix/pkg/testing"
+)
+
+var (
+	// ErrStaleBlock is returned by AddBlock for a block at or below the chain head, such as
+	// a block announced again after it was added
+	ErrStaleBlock = errors.New("block is not above the chain head")
+
+	// ErrFutureBlock is returned by AddBlock for a block that does not directly follow the
+	// chain head, so the blocks in between have to be synced first
+	ErrFutureBlock = errors.New("block is ahead of the chain head")
+
+	// ErrUnknownProducer is returned by AddBlock, together with ErrFutureBlock, for a block
+	// ahead of the chain head produced by a validator that is not in the current set. The
+	// producer may have joined the set in the blocks in between.
+	ErrUnknownProducer = errors.New("block producer is not a known validator")
 )
 
 // Blockchain represents the entire chain of blocks
@@ -22,31 +37,98 @@ type Blockchain struct {
 	CurrentIndex    int
 	LastBlockTime   time.Time
 	ContractManager *contracts.Manager
+	Mempool         *Mempool
 	EventEmitter   *EventEmitter
 	WebSocketServer *WebSocketServer
 	mu             sync.RWMutex
 	consensus      *consensus.RoundRobin
+
+	// İşlem receipt'leri (tx hash -> receipt)
+	receipts map[string]*Receipt
+
+	// Son blokların değiştirdiği hesapların blok öncesindeki halleri (yükseklik -> hesaplar),
+	// geçmiş state'ler simülasyonlar için güncel state'ten geriye doğru oluşturulur
+	stateChanges map[uint64]map[common.Address]*contracts.Account
+	stateHeight  uint64 // Güncel state'in ait olduğu blok yüksekliği
+
+	// Asenkron kontrat deploy'ları
+	deployments     map[string]*Deployment
+	deploymentsByTx map[string]string // tx hash -> deployment ID
+	deploymentsMu   sync.RWMutex
+
+	// Kaynak kod doğrulaması için Solidity derleyicisi (nil ise yalnızca artifact karşılaştırılır)
+	Compiler contracts.Compiler
+
+	// State snapshot'ları
+	snapshotConfig  SnapshotConfig
+	snapshots       map[uint64]*storedSnapshot
+	pendingSnapshot *Snapshot
 }
 
-// NewBlockchain creates a new blockchain instance
+// NewBlockchain creates a new blockchain instance with the default genesis
 func NewBlockchain(v *validator.Authority) (*Blockchain, error) {
+	return NewBlockchainWithGenesis(v, DefaultGenesis())
+}
+
+// NewBlockchainWithGenesis creates a new blockchain instance whose contracts run with the
+// chain config of the genesis
+func NewBlockchainWithGenesis(v *validator.Authority, genesis *Genesis) (*Blockchain, error) {
 	if v == nil {
 		return nil, errors.New("validator gerekli")
 	}
+	if genesis == nil {
+		genesis = DefaultGenesis()
+	}
+	if err := contracts.ValidateChainConfig(genesis.Config); err != nil {
+		return nil, fmt.Errorf("geçersiz genesis config: %v", err)
+	}
+	authorities, err := genesis.authorities()
+	if err != nil {
+		return nil, err
+	}
+	validation, err := genesis.validationConfig()
+	if err != nil {
+		return nil, err
+	}
 
 	bc := &Blockchain{
 		Blocks:          make([]*Block, 0),
 		Validators:      make(map[string]*validator.Authority),
 		consensus:       consensus.NewRoundRobin(),
 		ContractManager: contracts.NewManager(),
+		Mempool:         NewMempool(DefaultMempoolSize),
 		EventEmitter:   NewEventEmitter(),
+		receipts:        make(map[string]*Receipt),
+		deployments:     make(map[string]*Deployment),
+		deploymentsByTx: make(map[string]string),
+	}
+
+	bc.ContractManager.SetChainConfig(genesis.Config)
+	bc.ContractManager.SetValidationConfig(validation)
+
+	// Genesis validator'larını ekle; listede yoksa zincir yalnızca yerel validator ile çalışır.
+	// Yerel validator listedeyse imza atabilmesi için onun authority'si kullanılır.
+	if len(authorities) == 0 {
+		authorities = append(authorities, v)
+	}
+	publicKeys := make([][]byte, 0, len(authorities))
+	for _, authority := range authorities {
+		if authority.Address == v.Address {
+			authority = v
+		}
+		bc.AddValidator(authority)
+		publicKeys = append(publicKeys, authority.PublicKeyBytes())
 	}
 
-	// Genesis validator'ı ekle
-	bc.AddValidator(v)
+	// Sistem kontratlarının kodu, validator kümesi ve genesis hesapları genesis state'ine yazılır
+	stateRoot, err := genesis.initState(contracts.NewStateDB(bc.ContractManager.State()), publicKeys)
+	if err != nil {
+		return nil, fmt.Errorf("genesis state oluşturulamadı: %v", err)
+	}
 
-	// Genesis bloğu oluştur
-	genesisBlock, err := NewBlock(0, make([]byte, 32), make([]byte, 32), 1000000, v)
+	// Genesis bloğu yalnızca genesis verisinden oluşturulur, böylece aynı genesis ile
+	// başlayan tüm node'larda aynıdır
+	genesisBlock, err := genesisBlock(genesis, stateRoot)
 	if err != nil {
 		return nil, fmt.Errorf("genesis blok oluşturulamadı: %v", err)
 	}
@@ -60,24 +142,44 @@ func NewBlockchain(v *validator.Authority) (*Blockchain, error) {
 	return bc, nil
 }
 
-// AddValidator adds a new validator to the blockchain
+// AddValidator adds a validator to the consensus set of this node. It does not change the
+// validator set in the state, which only transactions to the validator set system contract do.
 func (bc *Blockchain) AddValidator(authority *validator.Authority) {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 
+	bc.addValidatorLocked(authority)
+}
+
+// addValidatorLocked adds a validator. Caller must hold the lock.
+func (bc *Blockchain) addValidatorLocked(authority *validator.Authority) {
 	bc.Validators[authority.Address] = authority
 	bc.consensus.AddValidator(authority)
 }
 
-// RemoveValidator removes a validator from the blockchain
+// RemoveValidator removes a validator from the consensus set of this node. It does not
+// change the validator set in the state.
 func (bc *Blockchain) RemoveValidator(address string) {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 
+	bc.removeValidatorLocked(address)
+}
+
+// removeValidatorLocked removes a validator. Caller must hold the lock.
+func (bc *Blockchain) removeValidatorLocked(address string) {
 	delete(bc.Validators, address)
 	bc.consensus.RemoveValidator(address)
 }
 
+// GetValidator returns the validator with the given address, or nil if it is not in the validator set
+func (bc *Blockchain) GetValidator(address string) *validator.Authority {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	return bc.Validators[address]
+}
+
 // AddBlock adds a new block to the chain
 func (bc *Blockchain) AddBlock(block *Block) error {
 	bc.mu.Lock()
@@ -86,6 +188,35 @@ func (bc *Blockchain) AddBlock(block *Block) error {
 	fmt.Printf("Starting AddBlock for height %d from validator %s\n", 
 		block.Header.Height, block.Header.ValidatorAddress)
 
+	// Get latest block
+	var prevBlock *Block
+	if len(bc.Blocks) > 0 {
+		prevBlock = bc.Blocks[len(bc.Blocks)-1]
+	}
+
+	// Validate block height; blocks that do not extend the chain are not validated further
+	expectedHeight := uint64(1)
+	if prevBlock != nil {
+		expectedHeight = prevBlock.Header.Height + 1
+	}
+	if block.Header.Height != expectedHeight {
+		fmt.Printf("Invalid block height. Expected %d, got %d\n", expectedHeight, block.Header.Height)
+		if block.Header.Height < expectedHeight {
+			return fmt.Errorf("%w: expected %d, got %d", ErrStaleBlock, expectedHeight, block.Header.Height)
+		}
+
+		// İlerideki bloklar senkronizasyon başlatmadan önce mevcut validator setiyle doğrulanır
+		validator := bc.Validators[block.Header.ValidatorAddress]
+		if validator == nil {
+			return fmt.Errorf("%w: %w: expected %d, got %d", ErrFutureBlock, ErrUnknownProducer, expectedHeight, block.Header.Height)
+		}
+		if !block.Verify(validator) {
+			fmt.Printf("Block signature verification failed for block %d\n", block.Header.Height)
+			return errors.New("invalid block signature")
+		}
+		return fmt.Errorf("%w: expected %d, got %d", ErrFutureBlock, expectedHeight, block.Header.Height)
+	}
+
 	// Skip consensus validation in test mode
 	if !testing.Testing() {
 		// Validate block producer
@@ -109,22 +240,6 @@ func (bc *Blockchain) AddBlock(block *Block) error {
 		return errors.New("invalid block signature")
 	}
 
-	// Get latest block
-	var prevBlock *Block
-	if len(bc.Blocks) > 0 {
-		prevBlock = bc.Blocks[len(bc.Blocks)-1]
-	}
-
-	// Validate block height
-	expectedHeight := uint64(1)
-	if prevBlock != nil {
-		expectedHeight = prevBlock.Header.Height + 1
-	}
-	if block.Header.Height != expectedHeight {
-		fmt.Printf("Invalid block height. Expected %d, got %d\n", expectedHeight, block.Header.Height)
-		return errors.New("invalid block height")
-	}
-
 	// Validate previous block hash
 	if prevBlock != nil {
 		if !bytes.Equal(block.Header.PrevHash, prevBlock.GetHash()) {
@@ -134,9 +249,41 @@ func (bc *Blockchain) AddBlock(block *Block) error {
 		fmt.Printf("Previous block hash verified for block %d\n", block.Header.Height)
 	}
 
+	// Snapshot yüksekliğine kadar olan bloklar çalıştırılmaz, state snapshot'tan yüklenir
+	if bc.pendingSnapshot != nil {
+		if err := bc.addSnapshotBlockLocked(block); err != nil {
+			fmt.Printf("Snapshot check failed for block %d: %v\n", block.Header.Height, err)
+			return err
+		}
+		bc.consensus.RecordBlockProduction(block.Header.Timestamp)
+		return nil
+	}
+
+	// Bloktaki işlemleri çalıştır ve başlıktaki state root'u doğrula
+	execution, err := bc.executeBlockLocked(block)
+	if err != nil {
+		fmt.Printf("Failed to execute block %d: %v\n", block.Header.Height, err)
+		return fmt.Errorf("failed to execute block: %w", err)
+	}
+	if err := checkExecution(block, execution); err != nil {
+		fmt.Printf("Execution check failed for block %d: %v\n", block.Header.Height, err)
+		return err
+	}
+
 	// Add block to chain
 	bc.Blocks = append(bc.Blocks, block)
+	bc.applyExecutionLocked(block, execution)
 	fmt.Printf("Block %d successfully added to chain\n", block.Header.Height)
+	if bc.EventEmitter != nil {
+		bc.EventEmitter.Emit(EventNewBlock, map[string]interface{}{"block": block})
+	}
+
+	// Checkpoint yüksekliklerinde snapshot üret
+	if bc.snapshotConfig.Interval > 0 && block.Header.Height%bc.snapshotConfig.Interval == 0 {
+		if _, err := bc.createSnapshotLocked(); err != nil {
+			fmt.Printf("Failed to create snapshot at height %d: %v\n", block.Header.Height, err)
+		}
+	}
 
 	// Record block production
 	bc.consensus.RecordBlockProduction(block.Header.Timestamp)
@@ -234,73 +381,36 @@ func (bc *Blockchain) GetActiveValidatorCount() int {
 	return bc.consensus.GetActiveValidatorCount()
 }
 
-// DeployContract deploys a new smart contract
-func (bc *Blockchain) DeployContract(code []byte, owner common.Address, name, version string) (*contracts.Contract, error) {
-	bc.mu.Lock()
-	defer bc.mu.Unlock()
-
-	// Emit deployment started event
-	bc.EventEmitter.Emit(EventContractDeployStarted, map[string]interface{}{
-		"owner": owner,
-		"name": name,
-		"version": version,
-	})
-
-	// Add 1 minute validation delay
-	time.Sleep(1 * time.Minute)
-
-	contract, err := bc.ContractManager.DeployContract(code, owner, name, version, time.Now().Unix())
+// VerifyContract verifies the submitted source of a deployed contract against its code
+// and stores the verified source. Sources are compiled with bc.Compiler unless the request
+// carries a compiled artifact.
+func (bc *Blockchain) VerifyContract(address common.Address, req *contracts.VerificationRequest) (*contracts.Contract, error) {
+	contract, err := bc.ContractManager.VerifyContract(address, req, bc.Compiler)
 	if err != nil {
-		// Emit deployment failed event
-		bc.EventEmitter.Emit(EventContractDeployFailed, map[string]interface{}{
-			"owner": owner,
-			"name": name,
-			"version": version,
-			"error": err.Error(),
-		})
 		return nil, err
 	}
 
-	// Emit deployment success event
-	bc.EventEmitter.Emit(EventContractDeploySuccess, map[string]interface{}{
-		"address": contract.Address,
-		"owner": owner,
-		"name": name,
-		"version": version,
-	})
-
-	return contract, nil
-}
-
-// VerifyContract verifies a deployed contract
-func (bc *Blockchain) VerifyContract(address common.Address) error {
-	bc.mu.Lock()
-	defer bc.mu.Unlock()
-
-	contract, err := bc.ContractManager.GetContract(address)
-	if err != nil {
-		return err
-	}
-
-	// Perform verification logic here
-	// ...
-
 	// Emit verification event
 	bc.EventEmitter.Emit(EventContractVerified, map[string]interface{}{
 		"address": address,
 		"name": contract.Name,
 		"version": contract.Version,
+		"contract_name": contract.Source.ContractName,
+		"compiler_version": contract.Source.CompilerVersion,
 	})
 
-	return nil
+	return contract, nil
 }
 
-// ExecuteContract executes a smart contract
-func (bc *Blockchain) ExecuteContract(address common.Address, input []byte) ([]byte, error) {
-	bc.mu.RLock()
-	defer bc.mu.RUnlock()
-
-	return bc.ContractManager.ExecuteContract(address, input)
+// ExecuteContract submits a contract call transaction signed by its sender to the mempool
+func (bc *Blockchain) ExecuteContract(tx *Transaction) error {
+	if !common.IsHexAddress(tx.To) {
+		return errors.New("invalid contract address")
+	}
+	if _, err := bc.GetContract(common.HexToAddress(tx.To)); err != nil {
+		return err
+	}
+	return bc.SubmitTransaction(tx)
 }
 
 // GetContract returns a contract by address
@@ -319,6 +429,11 @@ func (bc *Blockchain) ListContracts() []*contracts.Contract {
 	return bc.ContractManager.ListContracts()
 }
 
+// GetStateRoot returns the root hash of the current world state
+func (bc *Blockchain) GetStateRoot() common.Hash {
+	return bc.ContractManager.State().Root()
+}
+
 // GetBlockchainInfo zincir hakkında genel bilgileri döndürür
 func (bc *Blockchain) GetBlockchainInfo() map[string]interface{} {
 	bc.mu.RLock()
@@ -338,5 +453,6 @@ func (bc *Blockchain) GetBlockchainInfo() map[string]interface{} {
 		"currentValidator":  currentValidatorAddr,
 		"blockInterval":     bc.consensus.GetBlockInterval().Seconds(),
 		"contractCount":     len(bc.ContractManager.ListContracts()),
+		"stateRoot":         bc.GetStateRoot().Hex(),
 	}
 } 
\ No newline at end of file
@@ -5,6 +5,8 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/SolidityDevSK/Confirmix/internal/validator"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/SolidityDevSK/Confirmix/pkg/consensus"
 	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
 )
@@ -31,6 +34,23 @@ func createTestValidator(t *testing.T) (*validator.Authority, error) {
 	return validator.NewAuthority(key)
 }
 
+// createTestAccount test için işlem imzalayabilen bir hesap oluşturur
+func createTestAccount(t *testing.T) (*ecdsa.PrivateKey, common.Address) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Hesap anahtarı oluşturulamadı: %v", err)
+	}
+	return key, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+// signTestTransaction işlemi gönderenin anahtarıyla imzalar
+func signTestTransaction(t *testing.T, key *ecdsa.PrivateKey, tx *Transaction) *Transaction {
+	if err := tx.Sign(key); err != nil {
+		t.Fatalf("İşlem imzalanamadı: %v", err)
+	}
+	return tx
+}
+
 func createTestTransaction(nonce uint64, value *big.Int) *Transaction {
 	return &Transaction{
 		Hash:      make([]byte, 32),
@@ -93,9 +113,11 @@ func TestAddBlock(t *testing.T) {
 	t.Logf("Test validator created with address: %s", v.Address)
 
 	bc := &Blockchain{
-		Blocks:     make([]*Block, 0),
-		Validators: make(map[string]*validator.Authority),
-		consensus:  consensus.NewRoundRobin(),
+		Blocks:          make([]*Block, 0),
+		Validators:      make(map[string]*validator.Authority),
+		consensus:       consensus.NewRoundRobin(),
+		ContractManager: contracts.NewManager(),
+		Mempool:         NewMempool(testMempoolSize),
 	}
 
 	t.Log("Adding genesis validator")
@@ -464,70 +486,320 @@ func TestContractOperations(t *testing.T) {
 		t.Fatalf("Validator oluşturulamadı: %v", err)
 	}
 
-	bc := &Blockchain{
-		Blocks:          make([]*Block, 0),
-		Validators:      make(map[string]*validator.Authority),
-		consensus:       consensus.NewRoundRobin(),
-		ContractManager: contracts.NewManager(),
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
 	}
 
-	// Genesis validator'ı ekle
-	bc.AddValidator(v)
+	// Test kontrat kodu: constructor, slot 0'ı bir artırıp yeni değeri döndüren runtime kodu döndürür
+	runtime := common.FromHex("6000546001018060005560005260206000f3")
+	code := append(common.FromHex("6012600c60003960126000f3"), runtime...)
+	key, owner := createTestAccount(t)
+	name := "TestContract"
+	version := "1.0"
 
-	// Genesis bloğu oluştur
-	genesisBlock, err := NewBlock(0, make([]byte, 32), make([]byte, 32), 1000000, v)
-	if err != nil {
-		t.Fatalf("Genesis blok oluşturulamadı: %v", err)
+	// İmzasız deploy işlemi reddedilmeli
+	deployTx := &Transaction{
+		From:            owner.Hex(),
+		Data:            code,
+		GasLimit:        200000,
+		Nonce:           bc.GetNonce(owner.Hex()),
+		ContractName:    name,
+		ContractVersion: version,
+	}
+	if err := bc.SubmitTransaction(deployTx); !errors.Is(err, ErrUnsignedTransaction) {
+		t.Fatalf("İmzasız işlem hatası bekleniyordu: %v", err)
 	}
 
-	bc.Blocks = append(bc.Blocks, genesisBlock)
-	bc.LastBlockTime = time.Now()
+	// Deploy işlemini imzalayıp mempool'a gönder
+	signTestTransaction(t, key, deployTx)
+	if err := bc.SubmitTransaction(deployTx); err != nil {
+		t.Fatalf("Deploy işlemi gönderilemedi: %v", err)
+	}
 
-	// Test kontrat kodu
-	code := []byte("test contract code")
-	owner := common.HexToAddress("0x1234567890")
-	name := "TestContract"
-	version := "1.0"
+	// Kontrat blok işlenene kadar oluşturulmamalı
+	if len(bc.ListContracts()) != 0 {
+		t.Error("Kontrat blok dışında oluşturuldu")
+	}
 
-	// Kontrat deploy et
-	contract, err := bc.DeployContract(code, owner, name, version)
+	block, err := bc.CreateBlock(v)
 	if err != nil {
-		t.Fatalf("Kontrat deploy edilemedi: %v", err)
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Deploy bloğu eklenemedi: %v", err)
+	}
+
+	receipt, exists := bc.GetReceipt(hex.EncodeToString(deployTx.Hash))
+	if !exists {
+		t.Fatal("Deploy receipt'i bulunamadı")
+	}
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Deploy başarısız: %s", receipt.Error)
+	}
+	if bc.Mempool.Count() != 0 {
+		t.Error("Bloğa eklenen işlem mempool'dan silinmedi")
 	}
 
 	// Kontrat bilgilerini kontrol et
+	contract, err := bc.GetContract(common.HexToAddress(receipt.ContractAddress))
+	if err != nil {
+		t.Fatalf("Kontrat bulunamadı: %v", err)
+	}
 	if contract.Name != name {
 		t.Error("Kontrat ismi hatalı")
 	}
 	if contract.Version != version {
 		t.Error("Kontrat versiyonu hatalı")
 	}
+	if !bytes.Equal(contract.Code, runtime) {
+		t.Error("Kontrat runtime kodu hatalı")
+	}
+	if contract.Address != crypto.CreateAddress(owner, 0) {
+		t.Error("Kontrat adresi gönderen ve nonce'tan türetilmedi")
+	}
 
-	// Kontrat listesini kontrol et
-	contracts := bc.ListContracts()
-	if len(contracts) != 1 {
-		t.Error("Kontrat listesi hatalı")
+	// Başka bir hesabın anahtarıyla imzalanmış çağrı reddedilmeli
+	otherKey, _ := createTestAccount(t)
+	forged := signTestTransaction(t, otherKey, &Transaction{From: owner.Hex(), To: contract.Address.Hex(), GasLimit: 100000, Nonce: 1})
+	if err := bc.ExecuteContract(forged); !errors.Is(err, ErrInvalidSender) {
+		t.Errorf("Geçersiz gönderen hatası bekleniyordu: %v", err)
 	}
 
-	// Yeni blok oluştur
-	block, err := createTestBlock(bc.GetLatestBlock().GetHash(), bc.GetLatestBlock().Header.Height+1, v)
+	// Kontrat çağrısı için işlem oluştur
+	callTx := &Transaction{From: owner.Hex(), To: contract.Address.Hex(), GasLimit: 100000, Nonce: bc.GetNonce(owner.Hex())}
+	if err := bc.ExecuteContract(signTestTransaction(t, key, callTx)); err != nil {
+		t.Fatalf("Kontrat çağrısı gönderilemedi: %v", err)
+	}
+	if callTx.Nonce != 1 {
+		t.Errorf("Çağrı nonce'u hatalı: %d", callTx.Nonce)
+	}
+
+	block, err = bc.CreateBlock(v)
 	if err != nil {
 		t.Fatalf("Blok oluşturulamadı: %v", err)
 	}
 
-	// Kontrat çağrısı için işlem oluştur
-	tx := createTestTransaction(1, big.NewInt(0))
-	tx.To = contract.Address.Hex() // Address'i string'e çevir
-	tx.Data = []byte("test input")
-	block.AddTransaction(tx)
-
 	// Bloğu zincire ekle
-	err = bc.AddBlock(block)
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Kontrat çağrısı için blok eklenemedi: %v", err)
+	}
+
+	receipt, _ = bc.GetReceipt(hex.EncodeToString(callTx.Hash))
+	if receipt == nil || receipt.Status != TxSuccess {
+		t.Fatal("Kontrat çağrısı başarısız")
+	}
+	if !bytes.Equal(block.Header.StateRoot, bc.GetStateRoot().Bytes()) {
+		t.Error("Zincir state root'u blok başlığıyla uyuşmuyor")
+	}
+
+	// Storage değişikliği zincir state'ine yazılmalı, salt okunur çağrı sayacı 2 olarak görür
+	result, err := bc.Call(&contracts.Message{From: owner, To: &contract.Address}, nil)
+	if err != nil || result.Failed() {
+		t.Fatalf("Kontrat çağrılamadı: %v", err)
+	}
+	if new(big.Int).SetBytes(result.ReturnData).Int64() != 2 {
+		t.Errorf("Sayaç değeri hatalı: %x", result.ReturnData)
+	}
+
+	// State root'u yanlış olan blok reddedilmeli
+	callTx = &Transaction{From: owner.Hex(), To: contract.Address.Hex(), GasLimit: 100000, Nonce: bc.GetNonce(owner.Hex())}
+	if err := bc.ExecuteContract(signTestTransaction(t, key, callTx)); err != nil {
+		t.Fatalf("Kontrat çağrısı gönderilemedi: %v", err)
+	}
+	block, err = bc.CreateBlock(v)
 	if err != nil {
-		t.Errorf("Kontrat çağrısı için blok eklenemedi: %v", err)
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	block.Header.StateRoot = make([]byte, 32)
+	block.hash = nil
+	if err := bc.AddBlock(block); err == nil {
+		t.Error("Geçersiz state root'lu blok kabul edildi")
 	}
 }
 
+// TestContractGasFees kontrat işlemlerinin ücretinin bloğu üreten validator'a yazıldığını test eder
+func TestContractGasFees(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	key, owner := createTestAccount(t)
+	code := append(common.FromHex("6012600c60003960126000f3"), common.FromHex("6000546001018060005560005260206000f3")...)
+
+	// Bakiyesi olmayan hesap ücretli işlem gönderemez
+	tx := signTestTransaction(t, key, &Transaction{From: owner.Hex(), Data: code, GasLimit: 200000, GasPrice: 5})
+	if err := bc.SubmitTransaction(tx); err == nil {
+		t.Fatal("Bakiyesi yetersiz işlem mempool'a eklendi")
+	}
+	if err := bc.SubmitTransaction(signTestTransaction(t, key, &Transaction{From: owner.Hex(), Data: code})); err == nil {
+		t.Fatal("Gas limit'i olmayan kontrat işlemi mempool'a eklendi")
+	}
+
+	initial := big.NewInt(1e18)
+	bc.ContractManager.State().SetAccount(owner, &contracts.Account{Balance: new(big.Int).Set(initial)})
+	if err := bc.SubmitTransaction(tx); err != nil {
+		t.Fatalf("Deploy işlemi gönderilemedi: %v", err)
+	}
+
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+
+	receipt, _ := bc.GetReceipt(hex.EncodeToString(tx.Hash))
+	if receipt == nil || receipt.Status != TxSuccess {
+		t.Fatal("Deploy başarısız")
+	}
+	fee := new(big.Int).SetUint64(receipt.GasUsed * 5)
+	if receipt.Fee != fee.String() {
+		t.Errorf("Receipt ücreti hatalı: %s", receipt.Fee)
+	}
+
+	state := bc.ContractManager.State()
+	if balance := state.GetBalance(owner); balance.Cmp(new(big.Int).Sub(initial, fee)) != 0 {
+		t.Errorf("Gönderen bakiyesi hatalı: %s", balance)
+	}
+	if balance := state.GetBalance(validatorAccount(v.Address)); balance.Cmp(fee) != 0 {
+		t.Errorf("Validator ücreti almadı: %s", balance)
+	}
+
+	// Validator, gönderenin imzası olmayan bir işlemle onun bakiyesinden ücret alamaz
+	forged := &Transaction{From: owner.Hex(), Data: code, GasLimit: 200000, GasPrice: 1000, Nonce: 1}
+	forged.Hash = forged.CalculateHash()
+	block, err = bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	block.Transactions = append(block.Transactions, forged)
+	if err := bc.AddBlock(block); !errors.Is(err, ErrUnsignedTransaction) {
+		t.Fatalf("İmzasız ücretli işlem içeren blok kabul edildi: %v", err)
+	}
+	if balance := bc.ContractManager.State().GetBalance(owner); balance.Cmp(new(big.Int).Sub(initial, fee)) != 0 {
+		t.Errorf("İmzasız işlem gönderenden ücret aldı: %s", balance)
+	}
+}
+
+// TestNativeTransfer imzalı transferlerin değer taşıdığını ve tekrar oynatılamadığını test eder
+func TestNativeTransfer(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	key, sender := createTestAccount(t)
+	recipient := common.HexToAddress("0xbeef")
+	bc.ContractManager.State().SetAccount(sender, &contracts.Account{Balance: big.NewInt(1e18)})
+
+	tx := signTestTransaction(t, key, &Transaction{From: sender.Hex(), To: recipient.Hex(), Value: big.NewInt(1000), GasLimit: 21000})
+	if err := bc.SubmitTransaction(tx); err != nil {
+		t.Fatalf("Transfer gönderilemedi: %v", err)
+	}
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+
+	receipt, _ := bc.GetReceipt(hex.EncodeToString(tx.Hash))
+	if receipt == nil || receipt.Status != TxSuccess {
+		t.Fatalf("Transfer başarısız: %+v", receipt)
+	}
+	state := bc.ContractManager.State()
+	if balance := state.GetBalance(recipient); balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("Alıcı bakiyesi hatalı: %s", balance)
+	}
+	if nonce := bc.GetNonce(sender.Hex()); nonce != 1 {
+		t.Errorf("Transfer gönderenin nonce'unu artırmalı: %d", nonce)
+	}
+
+	// Aynı imzalı transfer tekrar gönderilemez
+	if err := bc.SubmitTransaction(tx); !errors.Is(err, ErrNonceTooLow) {
+		t.Errorf("Düşük nonce hatası bekleniyordu: %v", err)
+	}
+
+	// Bloğa tekrar eklenen transfer değer taşımaz
+	block, err = bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := block.AddTransaction(tx); err != nil {
+		t.Fatalf("İşlem bloğa eklenemedi: %v", err)
+	}
+	commitTestExecution(t, bc, block)
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+	if balance := bc.ContractManager.State().GetBalance(recipient); balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("Tekrar oynatılan transfer değer taşıdı: %s", balance)
+	}
+	if nonce := bc.GetNonce(sender.Hex()); nonce != 1 {
+		t.Errorf("Tekrar oynatılan transfer nonce'u değiştirdi: %d", nonce)
+	}
+}
+
+// TestCreateBlockDropsUnsignedTransactions imzası doğrulanamayan işlemlerin mempool'dan çıkarılmasını test eder
+func TestCreateBlockDropsUnsignedTransactions(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	// Doğrulama yapılmadan havuza giren imzasız işlem
+	_, sender := createTestAccount(t)
+	tx := &Transaction{From: sender.Hex(), To: common.HexToAddress("0xbeef").Hex(), Value: big.NewInt(0), GasLimit: 21000}
+	tx.Hash = tx.CalculateHash()
+	if err := bc.Mempool.AddTransaction(tx); err != nil {
+		t.Fatalf("İşlem havuza eklenemedi: %v", err)
+	}
+
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if len(block.Transactions) != 0 {
+		t.Error("İmzasız işlem bloğa eklendi")
+	}
+	if bc.Mempool.GetTransaction(tx.Hash) != nil {
+		t.Error("İmzasız işlem mempool'da kalmamalı")
+	}
+}
+
+// commitTestExecution bloğu çalıştırıp state ve receipt root'larını başlığa yazar
+func commitTestExecution(t *testing.T, bc *Blockchain, block *Block) {
+	bc.mu.RLock()
+	execution, err := bc.executeBlockLocked(block)
+	bc.mu.RUnlock()
+	if err != nil {
+		t.Fatalf("Blok çalıştırılamadı: %v", err)
+	}
+	block.Header.StateRoot = execution.stateRoot
+	block.Header.ReceiptRoot = execution.receiptRoot
+	block.Header.LogsBloom = execution.logsBloom
+	block.hash = nil
+}
+
 // TestInvalidTransactions geçersiz işlem durumlarını test eder
 func TestInvalidTransactions(t *testing.T) {
 	v, err := createTestValidator(t)
@@ -890,4 +1162,66 @@ func TestGetLatestBlock(t *testing.T) {
 	if latestBlock.Header.Height != 3 {
 		t.Errorf("Son blok yüksekliği hatalı. Beklenen: 3, Alınan: %d", latestBlock.Header.Height)
 	}
-} 
\ No newline at end of file
+} 
+// TestAddBlockHeightErrors zinciri uzatmayan blokların ayrı hatalarla reddedildiğini test eder
+func TestAddBlockHeightErrors(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	block, err := createTestBlock(bc.GetLatestBlock().GetHash(), 1, v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+
+	// Aynı blok tekrar duyurulduğunda eski blok hatası alınır
+	if err := bc.AddBlock(block); !errors.Is(err, ErrStaleBlock) {
+		t.Errorf("Eski blok hatası bekleniyordu: %v", err)
+	}
+
+	// Bilinmeyen validator'ın eski bloğu da geçersiz değil eski sayılır
+	other, _ := createTestValidator(t)
+	stale, err := createTestBlock(bc.Blocks[0].GetHash(), 1, other)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(stale); !errors.Is(err, ErrStaleBlock) {
+		t.Errorf("Eski blok hatası bekleniyordu: %v", err)
+	}
+
+	// Aradaki bloklar eksikse ileri blok hatası alınır
+	future, err := createTestBlock(block.GetHash(), 3, v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(future); !errors.Is(err, ErrFutureBlock) {
+		t.Errorf("İleri blok hatası bekleniyordu: %v", err)
+	}
+
+	// Bilinen validator adına sahte imzalanan ileri blok senkronizasyon başlatmaz
+	forged, err := createTestBlock(block.GetHash(), 3, other)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	forged.Header.ValidatorAddress = v.Address
+	if err := bc.AddBlock(forged); err == nil || errors.Is(err, ErrFutureBlock) {
+		t.Errorf("Sahte imzalı ileri blok reddedilmeliydi: %v", err)
+	}
+
+	// Bilinmeyen validator'ın ileri bloğu ayrıca işaretlenir
+	unknown, err := createTestBlock(block.GetHash(), 3, other)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(unknown); !errors.Is(err, ErrFutureBlock) || !errors.Is(err, ErrUnknownProducer) {
+		t.Errorf("Bilinmeyen üretici hatası bekleniyordu: %v", err)
+	}
+}
@@ -0,0 +1,295 @@
+package blockchain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DeploymentStatus represents the progress of an asynchronous contract deployment
+type DeploymentStatus string
+
+const (
+	DeploymentPending   DeploymentStatus = "pending"   // Accepted, validation is running
+	DeploymentSubmitted DeploymentStatus = "submitted" // Deployment transaction is in the mempool
+	DeploymentDeployed  DeploymentStatus = "deployed"  // Included in a block, contract created
+	DeploymentFailed    DeploymentStatus = "failed"    // Validation, submission or execution failed
+)
+
+const (
+	// DeploymentTTL is how long a deployed or failed deployment can still be looked up
+	DeploymentTTL = time.Hour
+
+	// PendingDeploymentTTL is how long a pending or submitted deployment is tracked while
+	// its transaction is not included in a block
+	PendingDeploymentTTL = 24 * time.Hour
+
+	// MaxDeployments bounds the number of tracked deployments, the oldest are dropped first
+	MaxDeployments = 10000
+)
+
+// Deployment tracks a contract deployment from request to inclusion in a block
+type Deployment struct {
+	ID              string           `json:"id"`
+	Status          DeploymentStatus `json:"status"`
+	Owner           string           `json:"owner"`
+	Name            string           `json:"name"`
+	Version         string           `json:"version"`
+	TxHash          string           `json:"tx_hash,omitempty"`
+	ContractAddress string           `json:"contract_address,omitempty"`
+	BlockHeight     uint64           `json:"block_height,omitempty"`
+	Error           string           `json:"error,omitempty"`
+	CreatedAt       int64            `json:"created_at"`
+	UpdatedAt       int64            `json:"updated_at"`
+
+	// Kod doğrulaması başarısız olduysa bulunan sorunlar
+	Issues []contracts.ValidationIssue `json:"issues,omitempty"`
+}
+
+// DeployContract starts an asynchronous deployment of a deployment transaction signed by its
+// sender, the owner of the contract, and returns it in pending state. The transaction data is
+// the init code followed by the encoded constructor arguments; a non-empty salt deploys with
+// CREATE2 and a non-empty ABI is stored with the contract. The owner pays gasLimit * gasPrice
+// up front and is refunded the unused gas. Validation and submission run in the background and
+// progress is reported through the CONTRACT_DEPLOY_* events and GetDeployment. Unsigned
+// transactions and owners that are not on the deployer allowlist are rejected immediately.
+func (bc *Blockchain) DeployContract(tx *Transaction) (*Deployment, error) {
+	if !tx.IsContractCreation() || tx.IsProxyOperation() {
+		return nil, errors.New("not a contract deployment transaction")
+	}
+	if len(tx.Hash) == 0 {
+		tx.Hash = tx.CalculateHash()
+	}
+	if err := bc.authenticateSender(tx); err != nil {
+		return nil, err
+	}
+	owner := common.HexToAddress(tx.From)
+	if err := bc.checkDeployer(owner); err != nil {
+		return nil, err
+	}
+	if tx.ContractABI != "" {
+		if _, err := contracts.ParseABI([]byte(tx.ContractABI)); err != nil {
+			return nil, err
+		}
+	}
+
+	id, err := newDeploymentID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	deployment := &Deployment{
+		ID:        id,
+		Status:    DeploymentPending,
+		Owner:     owner.Hex(),
+		Name:      tx.ContractName,
+		Version:   tx.ContractVersion,
+		TxHash:    hex.EncodeToString(tx.Hash),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// Tx hash'i mempool'a eklenmeden önce kaydedilir, böylece hızlı üretilen bir blok sonucu kaçırmaz
+	bc.deploymentsMu.Lock()
+	if bc.deployments == nil {
+		bc.deployments = make(map[string]*Deployment)
+		bc.deploymentsByTx = make(map[string]string)
+	}
+	bc.pruneDeploymentsLocked(now)
+	bc.deployments[id] = deployment
+	bc.deploymentsByTx[deployment.TxHash] = id
+	started := *deployment
+	bc.deploymentsMu.Unlock()
+
+	// Emit deployment started event
+	bc.EventEmitter.Emit(EventContractDeployStarted, map[string]interface{}{
+		"deployment_id": id,
+		"owner":         owner,
+		"name":          tx.ContractName,
+		"version":       tx.ContractVersion,
+	})
+
+	go bc.runDeployment(id, tx)
+
+	return &started, nil
+}
+
+// runDeployment validates the deployment and submits its transaction to the mempool
+func (bc *Blockchain) runDeployment(id string, tx *Transaction) {
+	if err := bc.validateDeployment(tx.Data, tx.Salt, common.HexToAddress(tx.From), tx.GasLimit); err != nil {
+		bc.failDeployment(id, err)
+		return
+	}
+
+	bc.updateDeployment(id, func(d *Deployment) {
+		d.Status = DeploymentSubmitted
+	})
+	if err := bc.SubmitTransaction(tx); err != nil {
+		bc.failDeployment(id, err)
+	}
+}
+
+// validateDeployment checks the init code and the runtime code its constructor returns
+// against the current state. Other execution failures are left to the deployment
+// transaction, as the state may change before it is included.
+func (bc *Blockchain) validateDeployment(code []byte, salt []byte, owner common.Address, gasLimit uint64) error {
+	if err := bc.ContractManager.ValidateContract(code); err != nil {
+		return err
+	}
+
+	msg := &contracts.Message{From: owner, Data: code, GasLimit: gasLimit}
+	if len(salt) > 0 {
+		hash := common.BytesToHash(salt)
+		msg.Salt = &hash
+	}
+	result, err := bc.Call(msg, nil)
+	if err != nil {
+		return err
+	}
+
+	var validationErr *contracts.ValidationError
+	if errors.As(result.Err, &validationErr) {
+		return validationErr
+	}
+	return nil
+}
+
+// failDeployment marks the deployment as failed and emits the failure event
+func (bc *Blockchain) failDeployment(id string, err error) {
+	var validationErr *contracts.ValidationError
+	errors.As(err, &validationErr)
+
+	var deployment Deployment
+	bc.updateDeployment(id, func(d *Deployment) {
+		d.Status = DeploymentFailed
+		d.Error = err.Error()
+		if validationErr != nil {
+			d.Issues = validationErr.Issues
+		}
+		deployment = *d
+	})
+
+	// Emit deployment failed event
+	bc.EventEmitter.Emit(EventContractDeployFailed, map[string]interface{}{
+		"deployment_id": id,
+		"owner":         deployment.Owner,
+		"name":          deployment.Name,
+		"version":       deployment.Version,
+		"tx_hash":       deployment.TxHash,
+		"error":         err.Error(),
+		"issues":        deployment.Issues,
+	})
+}
+
+// pruneDeploymentsLocked drops finished deployments older than the TTL, unfinished ones
+// older than the pending TTL and, above the limit, the oldest deployments to make room
+// for a new one. Caller must hold the deployments lock.
+func (bc *Blockchain) pruneDeploymentsLocked(now int64) {
+	for id, deployment := range bc.deployments {
+		ttl := PendingDeploymentTTL
+		if deployment.Status == DeploymentDeployed || deployment.Status == DeploymentFailed {
+			ttl = DeploymentTTL
+		}
+		if now-deployment.UpdatedAt >= int64(ttl/time.Second) {
+			bc.removeDeploymentLocked(id)
+		}
+	}
+
+	for len(bc.deployments) >= MaxDeployments {
+		var oldest *Deployment
+		for _, deployment := range bc.deployments {
+			if oldest == nil || deployment.CreatedAt < oldest.CreatedAt {
+				oldest = deployment
+			}
+		}
+		bc.removeDeploymentLocked(oldest.ID)
+	}
+}
+
+// removeDeploymentLocked stops tracking a deployment. Caller must hold the deployments lock.
+func (bc *Blockchain) removeDeploymentLocked(id string) {
+	if deployment, exists := bc.deployments[id]; exists {
+		delete(bc.deploymentsByTx, deployment.TxHash)
+		delete(bc.deployments, id)
+	}
+}
+
+// updateDeployment applies the change to the deployment under the deployments lock
+func (bc *Blockchain) updateDeployment(id string, update func(d *Deployment)) {
+	bc.deploymentsMu.Lock()
+	defer bc.deploymentsMu.Unlock()
+
+	if deployment, exists := bc.deployments[id]; exists {
+		update(deployment)
+		deployment.UpdatedAt = time.Now().Unix()
+	}
+}
+
+// GetDeployment returns the current state of a deployment
+func (bc *Blockchain) GetDeployment(id string) (*Deployment, error) {
+	bc.deploymentsMu.RLock()
+	defer bc.deploymentsMu.RUnlock()
+
+	deployment, exists := bc.deployments[id]
+	if !exists {
+		return nil, errors.New("deployment not found")
+	}
+
+	cpy := *deployment
+	return &cpy, nil
+}
+
+// emitDeploymentResult records the outcome of an included deployment transaction and emits it
+func (bc *Blockchain) emitDeploymentResult(tx *Transaction, receipt *Receipt) {
+	data := map[string]interface{}{
+		"owner":   tx.From,
+		"name":    tx.ContractName,
+		"version": tx.ContractVersion,
+		"tx_hash": receipt.TxHash,
+	}
+
+	bc.deploymentsMu.RLock()
+	id, tracked := bc.deploymentsByTx[receipt.TxHash]
+	bc.deploymentsMu.RUnlock()
+
+	if tracked {
+		data["deployment_id"] = id
+		bc.updateDeployment(id, func(d *Deployment) {
+			d.BlockHeight = receipt.BlockHeight
+			if receipt.Status == TxFailed {
+				d.Status = DeploymentFailed
+				d.Error = receipt.Error
+				return
+			}
+			d.Status = DeploymentDeployed
+			d.ContractAddress = receipt.ContractAddress
+		})
+	}
+
+	if bc.EventEmitter == nil {
+		return
+	}
+	if receipt.Status == TxFailed {
+		data["error"] = receipt.Error
+		bc.EventEmitter.Emit(EventContractDeployFailed, data)
+		return
+	}
+
+	data["address"] = receipt.ContractAddress
+	bc.EventEmitter.Emit(EventContractDeploySuccess, data)
+}
+
+// newDeploymentID generates a random deployment ID
+func newDeploymentID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate deployment id: %v", err)
+	}
+	return hex.EncodeToString(id), nil
+}
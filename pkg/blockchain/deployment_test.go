@@ -0,0 +1,215 @@
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// waitForDeployment deploy belirtilen duruma gelene kadar bekler
+func waitForDeployment(t *testing.T, bc *Blockchain, id string, status DeploymentStatus) *Deployment {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		deployment, err := bc.GetDeployment(id)
+		if err != nil {
+			t.Fatalf("Deploy bulunamadı: %v", err)
+		}
+		if deployment.Status == status {
+			return deployment
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Deploy %s durumuna gelmedi", status)
+	return nil
+}
+
+// TestAsyncContractDeployment deploy'un beklemeden döndüğünü ve durumunun blokla güncellendiğini test eder
+func TestAsyncContractDeployment(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	successChan := bc.EventEmitter.Subscribe(EventContractDeploySuccess)
+	defer bc.EventEmitter.Unsubscribe(EventContractDeploySuccess, successChan)
+
+	// Constructor, 0x00 runtime kodunu döndürür
+	code := common.FromHex("6001600c60003960016000f300")
+	key, owner := createTestAccount(t)
+
+	start := time.Now()
+	contractABI := `[{"type":"function","name":"f","inputs":[],"outputs":[]}]`
+	tx := &Transaction{From: owner.Hex(), Data: code, GasLimit: 100000, ContractName: "Async", ContractVersion: "1.0", ContractABI: contractABI}
+	deployment, err := bc.DeployContract(signTestTransaction(t, key, tx))
+	if err != nil {
+		t.Fatalf("Deploy başlatılamadı: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Deploy asenkron dönmedi")
+	}
+	if deployment.Status != DeploymentPending {
+		t.Errorf("Yeni deploy pending olmalı, alınan %s", deployment.Status)
+	}
+
+	submitted := waitForDeployment(t, bc, deployment.ID, DeploymentSubmitted)
+	if submitted.TxHash == "" {
+		t.Fatal("Gönderilen deploy'un tx hash'i yok")
+	}
+
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+
+	deployed := waitForDeployment(t, bc, deployment.ID, DeploymentDeployed)
+	if deployed.ContractAddress == "" || deployed.BlockHeight != block.Header.Height {
+		t.Errorf("Deploy sonucu hatalı: %+v", deployed)
+	}
+	contract, err := bc.GetContract(common.HexToAddress(deployed.ContractAddress))
+	if err != nil || !bytes.Equal(contract.ABI, []byte(contractABI)) {
+		t.Error("Kontrat ABI'si saklanmadı")
+	}
+
+	select {
+	case event := <-successChan:
+		if event.Data["deployment_id"] != deployment.ID {
+			t.Errorf("Olay farklı bir deploy'a ait: %v", event.Data["deployment_id"])
+		}
+	case <-time.After(time.Second):
+		t.Error("Deploy başarı olayı yayınlanmadı")
+	}
+}
+
+// TestAsyncContractDeploymentValidationFailure geçersiz kodun deploy'u başarısız kıldığını test eder
+func TestAsyncContractDeploymentValidationFailure(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	key, owner := createTestAccount(t)
+	tx := &Transaction{From: owner.Hex(), GasLimit: 100000, ContractName: "Empty", ContractVersion: "1.0", ContractABI: "{"}
+	if _, err := bc.DeployContract(signTestTransaction(t, key, tx)); err == nil {
+		t.Error("Geçersiz ABI kabul edildi")
+	}
+
+	tx = &Transaction{From: owner.Hex(), GasLimit: 100000, ContractName: "Empty", ContractVersion: "1.0"}
+	deployment, err := bc.DeployContract(signTestTransaction(t, key, tx))
+	if err != nil {
+		t.Fatalf("Deploy başlatılamadı: %v", err)
+	}
+
+	failed := waitForDeployment(t, bc, deployment.ID, DeploymentFailed)
+	if failed.Error == "" {
+		t.Error("Başarısız deploy hata mesajı içermiyor")
+	}
+	if len(failed.Issues) != 1 || failed.Issues[0].Code != contracts.IssueEmptyCode {
+		t.Errorf("Doğrulama sorunları kaydedilmedi: %+v", failed.Issues)
+	}
+	if bc.Mempool.Count() != 0 {
+		t.Error("Geçersiz deploy mempool'a eklendi")
+	}
+}
+
+// TestDeploymentEviction deploy'ların süre dolunca ve sınır aşılınca silindiğini test eder
+func TestDeploymentEviction(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	key, owner := createTestAccount(t)
+	deploy := func(nonce uint64) *Deployment {
+		tx := &Transaction{From: owner.Hex(), GasLimit: 100000, Nonce: nonce, ContractName: "Empty", ContractVersion: "1.0"}
+		deployment, err := bc.DeployContract(signTestTransaction(t, key, tx))
+		if err != nil {
+			t.Fatalf("Deploy başlatılamadı: %v", err)
+		}
+		return waitForDeployment(t, bc, deployment.ID, DeploymentFailed)
+	}
+
+	// Süresi dolan başarısız deploy bir sonraki deploy'da silinir
+	old := deploy(0)
+	bc.deploymentsMu.Lock()
+	bc.deployments[old.ID].UpdatedAt -= int64(DeploymentTTL / time.Second)
+	bc.deploymentsMu.Unlock()
+
+	recent := deploy(1)
+	if _, err := bc.GetDeployment(old.ID); err == nil {
+		t.Error("Süresi dolan deploy silinmedi")
+	}
+	if _, err := bc.GetDeployment(recent.ID); err != nil {
+		t.Errorf("Yeni deploy silindi: %v", err)
+	}
+	bc.deploymentsMu.RLock()
+	_, tracked := bc.deploymentsByTx[old.TxHash]
+	bc.deploymentsMu.RUnlock()
+	if tracked {
+		t.Error("Silinen deploy'un tx kaydı kaldı")
+	}
+
+	// İşlemi bloğa girmeyen deploy daha uzun süre sonunda silinir
+	bc.deploymentsMu.Lock()
+	bc.deployments[recent.ID].Status = DeploymentSubmitted
+	bc.deployments[recent.ID].UpdatedAt -= int64(DeploymentTTL / time.Second)
+	bc.deploymentsMu.Unlock()
+	deploy(2)
+	if _, err := bc.GetDeployment(recent.ID); err != nil {
+		t.Errorf("Bekleyen deploy erken silindi: %v", err)
+	}
+
+	bc.deploymentsMu.Lock()
+	bc.deployments[recent.ID].UpdatedAt -= int64(PendingDeploymentTTL / time.Second)
+	bc.deploymentsMu.Unlock()
+	deploy(3)
+	if _, err := bc.GetDeployment(recent.ID); err == nil {
+		t.Error("Süresi dolan bekleyen deploy silinmedi")
+	}
+
+	// Sınır aşıldığında en eski deploy silinir
+	recent = deploy(4)
+	bc.deploymentsMu.Lock()
+	bc.deployments[recent.ID].CreatedAt--
+	for i := len(bc.deployments); i < MaxDeployments; i++ {
+		id := fmt.Sprintf("filler-%d", i)
+		bc.deployments[id] = &Deployment{ID: id, Status: DeploymentPending, TxHash: id, CreatedAt: recent.CreatedAt + 1, UpdatedAt: recent.CreatedAt + 1}
+		bc.deploymentsByTx[id] = id
+	}
+	bc.deploymentsMu.Unlock()
+
+	latest := deploy(5)
+	bc.deploymentsMu.RLock()
+	count := len(bc.deployments)
+	bc.deploymentsMu.RUnlock()
+	if count != MaxDeployments {
+		t.Errorf("Deploy sayısı %d olmalı, alınan %d", MaxDeployments, count)
+	}
+	if _, err := bc.GetDeployment(recent.ID); err == nil {
+		t.Error("En eski deploy silinmedi")
+	}
+	if _, err := bc.GetDeployment(latest.ID); err != nil {
+		t.Errorf("Yeni deploy silindi: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrTransactionMismatch is returned for a signed transaction whose fields differ from
+// the transaction it was decoded from
+var ErrTransactionMismatch = errors.New("transaction does not match its signed encoding")
+
+// DecodeEthereumTransaction decodes a signed Ethereum transaction (legacy RLP or an
+// EIP-2718 typed envelope) and recovers its sender. Only replay protected transactions
+// signed for the given chain ID are accepted. Dynamic fee transactions pay the lower of
+// their fee cap and tip cap per gas, as blocks have no base fee.
+func DecodeEthereumTransaction(raw []byte, chainID *big.Int) (*Transaction, error) {
+	ethTx := new(types.Transaction)
+	if err := ethTx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("invalid transaction encoding: %v", err)
+	}
+	if !ethTx.Protected() {
+		return nil, errors.New("only replay protected (EIP-155) transactions are accepted")
+	}
+	if ethTx.ChainId().Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("invalid chain ID: have %s, want %s", ethTx.ChainId(), chainID)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), ethTx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction signature: %v", err)
+	}
+
+	price := ethTx.GasTipCap()
+	if ethTx.GasFeeCapIntCmp(price) < 0 {
+		price = ethTx.GasFeeCap()
+	}
+	if !price.IsUint64() {
+		return nil, errors.New("gas price is too high")
+	}
+
+	tx := &Transaction{
+		Hash:           ethTx.Hash().Bytes(),
+		From:           sender.Hex(),
+		Value:          new(big.Int).Set(ethTx.Value()),
+		Data:           ethTx.Data(),
+		GasPrice:       price.Uint64(),
+		GasLimit:       ethTx.Gas(),
+		Nonce:          ethTx.Nonce(),
+		RawTransaction: common.CopyBytes(raw),
+	}
+	if to := ethTx.To(); to != nil {
+		tx.To = to.Hex()
+	}
+	return tx, nil
+}
+
+// SendRawTransaction decodes a signed Ethereum transaction and adds it to the mempool
+func (bc *Blockchain) SendRawTransaction(raw []byte) (*Transaction, error) {
+	tx, err := DecodeEthereumTransaction(raw, bc.ChainConfig().ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bc.SubmitTransaction(tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// verifyEthereumTransaction checks that the fields of a signed transaction are the ones it
+// was decoded from, so that transactions received in blocks cannot alter a signed payload
+func (bc *Blockchain) verifyEthereumTransaction(tx *Transaction) error {
+	decoded, err := DecodeEthereumTransaction(tx.RawTransaction, bc.ChainConfig().ChainID)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(tx.Hash, decoded.Hash) || tx.From != decoded.From || tx.To != decoded.To ||
+		bigOrZero(tx.Value).Cmp(decoded.Value) != 0 || !bytes.Equal(tx.Data, decoded.Data) ||
+		tx.GasPrice != decoded.GasPrice || tx.GasLimit != decoded.GasLimit || tx.Nonce != decoded.Nonce ||
+		tx.ContractName != "" || len(tx.Salt) > 0 || tx.IsProxyOperation() {
+		return ErrTransactionMismatch
+	}
+	return nil
+}
+
+// GetTransaction returns a transaction by hash together with the block including it and
+// its index in the block. The block is nil for transactions still in the mempool.
+func (bc *Blockchain) GetTransaction(hash []byte) (*Transaction, *Block, int) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	if receipt, exists := bc.receipts[hex.EncodeToString(hash)]; exists && receipt.BlockHeight < uint64(len(bc.Blocks)) {
+		block := bc.Blocks[receipt.BlockHeight]
+		if receipt.TransactionIndex < len(block.Transactions) {
+			return block.Transactions[receipt.TransactionIndex], block, receipt.TransactionIndex
+		}
+	}
+	if tx := bc.Mempool.GetTransaction(hash); tx != nil {
+		return tx, nil, 0
+	}
+	return nil, nil, 0
+}
+
+// Coinbase returns the EVM account credited with the fees of the block
+func (h *Header) Coinbase() common.Address {
+	return validatorAccount(h.ValidatorAddress)
+}
+
+// bigOrZero returns the value, or zero if it is nil
+func bigOrZero(value *big.Int) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	return value
+}
@@ -0,0 +1,108 @@
+package blockchain
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestSendRawTransaction imzalı Ethereum işlemlerinin mempool'a alınıp nonce sırasıyla çalıştırıldığını test eder
+func TestSendRawTransaction(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	bc.ContractManager.State().SetAccount(sender, &contracts.Account{Balance: big.NewInt(1e18)})
+	recipient := common.HexToAddress("0xbeef")
+	signer := types.LatestSignerForChainID(bc.ChainConfig().ChainID)
+
+	sign := func(data types.TxData) []byte {
+		tx, err := types.SignNewTx(key, signer, data)
+		if err != nil {
+			t.Fatalf("İşlem imzalanamadı: %v", err)
+		}
+		raw, _ := tx.MarshalBinary()
+		return raw
+	}
+	transfer := func(nonce uint64) []byte {
+		return sign(&types.DynamicFeeTx{ChainID: bc.ChainConfig().ChainID, Nonce: nonce, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, To: &recipient, Value: big.NewInt(1000)})
+	}
+
+	// Farklı zincir için imzalanmış işlem reddedilmeli
+	other, _ := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1), Gas: 21000, To: &recipient})
+	raw, _ := other.MarshalBinary()
+	if _, err := bc.SendRawTransaction(raw); err == nil {
+		t.Error("Farklı chain ID'li işlem kabul edildi")
+	}
+
+	// Nonce sırası bozuk gönderilen işlemler sırayla çalıştırılmalı
+	second, err := bc.SendRawTransaction(transfer(1))
+	if err != nil {
+		t.Fatalf("İşlem gönderilemedi: %v", err)
+	}
+	first, err := bc.SendRawTransaction(transfer(0))
+	if err != nil {
+		t.Fatalf("İşlem gönderilemedi: %v", err)
+	}
+	if first.From != sender.Hex() || first.GasPrice != 1 {
+		t.Errorf("İşlem alanları hatalı: %+v", first)
+	}
+	if nonce := bc.GetNonce(sender.Hex()); nonce != 2 {
+		t.Errorf("Bekleyen nonce hatalı: %d", nonce)
+	}
+
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if len(block.Transactions) != 2 || block.Transactions[0] != first {
+		t.Fatalf("İşlemler nonce sırasıyla eklenmedi")
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+	for _, tx := range []*Transaction{first, second} {
+		receipt, _ := bc.GetReceipt(receiptHash(tx))
+		if receipt == nil || receipt.Status != TxSuccess || receipt.GasUsed != 21000 {
+			t.Fatalf("Transfer başarısız: %+v", receipt)
+		}
+	}
+	if balance := bc.ContractManager.State().GetBalance(recipient); balance.Int64() != 2000 {
+		t.Errorf("Alıcı bakiyesi hatalı: %s", balance)
+	}
+	if tx, included, index := bc.GetTransaction(second.Hash); tx != second || included != block || index != 1 {
+		t.Error("İşlem hash ile bulunamadı")
+	}
+
+	// Kullanılmış nonce tekrar gönderilemez
+	if _, err := bc.SendRawTransaction(transfer(0)); !errors.Is(err, ErrNonceTooLow) {
+		t.Errorf("Nonce hatası bekleniyordu: %v", err)
+	}
+
+	// İmzalı alanları değiştirilmiş işlem blokta başarısız olur
+	tampered, err := DecodeEthereumTransaction(transfer(2), bc.ChainConfig().ChainID)
+	if err != nil {
+		t.Fatalf("İşlem çözülemedi: %v", err)
+	}
+	tampered.Value = big.NewInt(1e17)
+	if err := bc.SubmitTransaction(tampered); !errors.Is(err, ErrTransactionMismatch) {
+		t.Errorf("Değiştirilmiş işlem kabul edildi: %v", err)
+	}
+}
+
+// receiptHash bir işlemin receipt anahtarını döndürür
+func receiptHash(tx *Transaction) string {
+	return common.Bytes2Hex(tx.Hash)
+}
@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+    "sync"
     "time"
     "github.com/ethereum/go-ethereum/common"
 )
@@ -13,7 +14,13 @@ const (
     EventContractDeployStarted  EventType = "CONTRACT_DEPLOY_STARTED"
     EventContractDeploySuccess  EventType = "CONTRACT_DEPLOY_SUCCESS"
     EventContractDeployFailed   EventType = "CONTRACT_DEPLOY_FAILED"
+    EventContractDeployRejected EventType = "CONTRACT_DEPLOY_REJECTED"
     EventContractVerified       EventType = "CONTRACT_VERIFIED"
+    EventContractLog            EventType = "CONTRACT_LOG"
+    EventContractAction         EventType = "CONTRACT_ACTION"
+
+    // Chain events
+    EventNewBlock EventType = "NEW_BLOCK"
 )
 
 // Event represents a blockchain event
@@ -36,6 +43,7 @@ type ContractEvent struct {
 // EventEmitter handles event emission and subscription
 type EventEmitter struct {
     subscribers map[EventType][]chan Event
+    mu          sync.RWMutex
 }
 
 // NewEventEmitter creates a new event emitter
@@ -47,6 +55,9 @@ func NewEventEmitter() *EventEmitter {
 
 // Subscribe to specific event type
 func (e *EventEmitter) Subscribe(eventType EventType) chan Event {
+    e.mu.Lock()
+    defer e.mu.Unlock()
+
     ch := make(chan Event, 100)
     e.subscribers[eventType] = append(e.subscribers[eventType], ch)
     return ch
@@ -54,6 +65,9 @@ func (e *EventEmitter) Subscribe(eventType EventType) chan Event {
 
 // Unsubscribe from specific event type
 func (e *EventEmitter) Unsubscribe(eventType EventType, ch chan Event) {
+    e.mu.Lock()
+    defer e.mu.Unlock()
+
     if subs, ok := e.subscribers[eventType]; ok {
         for i, sub := range subs {
             if sub == ch {
@@ -73,6 +87,10 @@ func (e *EventEmitter) Emit(eventType EventType, data map[string]interface{}) {
         Data:      data,
     }
 
+    // Deploy olayları arka plandaki goroutine'lerden de yayınlanır
+    e.mu.RLock()
+    defer e.mu.RUnlock()
+
     if subs, ok := e.subscribers[eventType]; ok {
         for _, ch := range subs {
             select {
@@ -0,0 +1,516 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// DefaultMempoolSize is the maximum total size of pending transactions in bytes
+	DefaultMempoolSize = 10 * 1024 * 1024
+
+	// DefaultBlockGasLimit is the gas limit of blocks produced by CreateBlock
+	DefaultBlockGasLimit = 30000000
+)
+
+// blockExecution is the result of executing the transactions of a block
+type blockExecution struct {
+	state       *contracts.WorldState
+	stateRoot   []byte
+	receiptRoot []byte
+	logsBloom   []byte
+	receipts    []*Receipt
+	deployed    []*contracts.Contract
+	upgrades    []proxyUpgrade
+	executed    bool // at least one contract transaction was executed
+
+	// context is the EVM block context the transactions are executed in
+	context *contracts.BlockContext
+}
+
+// proxyUpgrade is a proxy repointed to a new implementation by a block
+type proxyUpgrade struct {
+	proxy   common.Address
+	version contracts.ImplementationVersion
+	code    []byte
+	abi     json.RawMessage
+}
+
+// SubmitTransaction validates a transaction and adds it to the mempool
+func (bc *Blockchain) SubmitTransaction(tx *Transaction) error {
+	if tx.Value == nil {
+		tx.Value = new(big.Int)
+	}
+	if tx.Value.Sign() < 0 {
+		return errors.New("transaction value cannot be negative")
+	}
+	if tx.IsProxyOperation() {
+		if !common.IsHexAddress(tx.Implementation) {
+			return errors.New("invalid implementation address")
+		}
+		if tx.IsContractCreation() && tx.ContractName == "" {
+			return errors.New("proxy requires a contract name")
+		}
+	} else if tx.IsContractCreation() && len(tx.Data) == 0 {
+		return errors.New("contract deployment requires init code")
+	}
+	if len(tx.Salt) > common.HashLength {
+		return errors.New("salt must be at most 32 bytes")
+	}
+
+	// Durumu değiştirebilen işlemler göndericisi tarafından imzalanmış olmalı
+	if len(tx.Hash) == 0 {
+		tx.Hash = tx.CalculateHash()
+	}
+	if err := bc.authenticateSender(tx); err != nil {
+		return err
+	}
+	if tx.RequiresSignature() {
+		if err := checkNonce(contracts.NewStateDB(bc.ContractManager.State()), tx); err != nil && !errors.Is(err, ErrNonceTooHigh) {
+			return err
+		}
+	}
+	isContract := tx.RequiresSignature() || len(tx.Data) > 0
+	if isContract && !common.IsHexAddress(tx.From) {
+		return errors.New("invalid sender address")
+	}
+	if isContract {
+		if err := bc.checkGas(tx); err != nil {
+			return err
+		}
+	}
+	if tx.IsContractCreation() {
+		if err := bc.checkDeployer(common.HexToAddress(tx.From)); err != nil {
+			return err
+		}
+	}
+
+	tx.Status = TxPending
+
+	return bc.Mempool.AddTransaction(tx)
+}
+
+// checkGas verifies that a contract transaction covers its intrinsic gas and that
+// the sender can currently pay for gas * price + value
+func (bc *Blockchain) checkGas(tx *Transaction) error {
+	if tx.GasLimit == 0 {
+		return errors.New("gas limit is required")
+	}
+	if tx.GasLimit > DefaultBlockGasLimit {
+		return fmt.Errorf("gas limit %d exceeds block gas limit %d", tx.GasLimit, DefaultBlockGasLimit)
+	}
+
+	bc.mu.RLock()
+	context := bc.pendingBlockContextLocked(uint64(len(bc.Blocks) - 1))
+	bc.mu.RUnlock()
+
+	intrinsicGas, err := bc.ContractManager.IntrinsicGas(tx.Data, tx.IsContractCreation(), context)
+	if err != nil {
+		return err
+	}
+	if tx.GasLimit < intrinsicGas {
+		return fmt.Errorf("%w: have %d, want %d", contracts.ErrIntrinsicGas, tx.GasLimit, intrinsicGas)
+	}
+	if err := checkGasPrice(contracts.NewStateDB(bc.ContractManager.State()), tx); err != nil {
+		return err
+	}
+
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(tx.GasLimit), new(big.Int).SetUint64(tx.GasPrice))
+	cost.Add(cost, tx.Value)
+	if bc.ContractManager.State().GetBalance(common.HexToAddress(tx.From)).Cmp(cost) < 0 {
+		return contracts.ErrInsufficientFunds
+	}
+	return nil
+}
+
+// checkDeployer verifies that the account is allowed to deploy contracts by the deployer
+// allowlist, emitting a CONTRACT_DEPLOY_REJECTED event if it is not
+func (bc *Blockchain) checkDeployer(account common.Address) error {
+	if contracts.DeployerAllowed(contracts.NewStateDB(bc.ContractManager.State()), account) {
+		return nil
+	}
+
+	err := fmt.Errorf("%w: %s", contracts.ErrDeployerNotAllowed, account.Hex())
+	if bc.EventEmitter != nil {
+		bc.EventEmitter.Emit(EventContractDeployRejected, map[string]interface{}{
+			"owner": account.Hex(),
+			"error": err.Error(),
+		})
+	}
+	return err
+}
+
+// checkGasPrice verifies that the transaction pays at least the minimum gas price set in
+// the fee parameters system contract
+func checkGasPrice(statedb *contracts.StateDB, tx *Transaction) error {
+	price := new(big.Int).SetUint64(tx.GasPrice)
+	if minimum := contracts.MinGasPrice(statedb); price.Cmp(minimum) < 0 {
+		return fmt.Errorf("%w: have %s, want %s", contracts.ErrGasPriceTooLow, price, minimum)
+	}
+	return nil
+}
+
+// GetNonce returns the next nonce to use for the given address, including pending transactions
+func (bc *Blockchain) GetNonce(address string) uint64 {
+	nonce := uint64(0)
+	if common.IsHexAddress(address) {
+		if account := bc.ContractManager.State().GetAccount(common.HexToAddress(address)); account != nil {
+			nonce = account.Nonce
+		}
+	}
+
+	if pending := bc.Mempool.GetPendingNonce(address); pending > nonce {
+		nonce = pending
+	}
+	return nonce
+}
+
+// GetReceipt returns the receipt of an included transaction
+func (bc *Blockchain) GetReceipt(hash string) (*Receipt, bool) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	receipt, exists := bc.receipts[hash]
+	return receipt, exists
+}
+
+// CreateBlock builds and signs the next block from the pending transactions in the mempool
+func (bc *Blockchain) CreateBlock(v *validator.Authority) (*Block, error) {
+	prevBlock := bc.GetLatestBlock()
+	if prevBlock == nil {
+		return nil, errors.New("chain is empty")
+	}
+
+	block, err := NewBlock(prevBlock.Header.Height+1, prevBlock.GetHash(), prevBlock.Header.StateRoot, DefaultBlockGasLimit, v)
+	if err != nil {
+		return nil, err
+	}
+
+	// İmzası doğrulanamayan işlemler hiçbir blokta geçerli olmayacağından mempool'dan çıkarılır,
+	// imzalı işlemler nonce sırasıyla eklenir
+	var selected []*Transaction
+	for _, tx := range bc.Mempool.GetBestTransactions(block.Header.GasLimit) {
+		if err := bc.authenticateSender(tx); err != nil {
+			fmt.Printf("Dropping transaction %x: %v\n", tx.Hash, err)
+			bc.Mempool.RemoveTransaction(tx.Hash)
+			continue
+		}
+		selected = append(selected, tx)
+	}
+	pending := orderSignedTransactions(selected, contracts.NewStateDB(bc.ContractManager.State()))
+	for _, tx := range pending {
+		if err := block.AddTransaction(tx); err != nil {
+			fmt.Printf("Skipping transaction %x: %v\n", tx.Hash, err)
+		}
+	}
+
+	bc.mu.RLock()
+	execution, err := bc.executeBlockLocked(block)
+	bc.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Kontrat işlemleri varsa state ve receipt root'ları başlığa yazılır
+	if execution.executed {
+		block.Header.StateRoot = execution.stateRoot
+		block.Header.ReceiptRoot = execution.receiptRoot
+		block.Header.LogsBloom = execution.logsBloom
+		block.hash = nil
+	}
+
+	return block, nil
+}
+
+// executeBlockLocked executes the transactions of a block on a copy of the current
+// world state. A block with a transaction not signed by its sender is invalid, so that a
+// validator cannot act on behalf of other accounts. Caller must hold the lock.
+func (bc *Blockchain) executeBlockLocked(block *Block) (*blockExecution, error) {
+	execution := &blockExecution{
+		state:    bc.ContractManager.State().Copy(),
+		receipts: make([]*Receipt, 0, len(block.Transactions)),
+		context:  bc.blockContextLocked(block.Header),
+	}
+	statedb := contracts.NewStateDB(execution.state)
+
+	for i, tx := range block.Transactions {
+		if err := bc.authenticateSender(tx); err != nil {
+			return nil, fmt.Errorf("transaction %x: %w", tx.Hash, err)
+		}
+
+		receipt := &Receipt{
+			TxHash:           hex.EncodeToString(tx.Hash),
+			BlockHeight:      block.Header.Height,
+			TransactionIndex: i,
+			From:             tx.From,
+			To:               tx.To,
+			Status:           TxSuccess,
+			GasUsed:          tx.GasUsed,
+		}
+
+		if bc.isContractTransaction(tx) {
+			execution.executed = true
+			bc.applyContractTransaction(statedb, block, i, tx, receipt, execution)
+			statedb.Finalise()
+		}
+
+		execution.receipts = append(execution.receipts, receipt)
+	}
+
+	root := statedb.Commit()
+	execution.stateRoot = root.Bytes()
+
+	receiptRoot, err := calculateReceiptRoot(execution.receipts)
+	if err != nil {
+		return nil, err
+	}
+	execution.receiptRoot = receiptRoot
+	if execution.executed {
+		execution.logsBloom = logsBloom(execution.receipts).Bytes()
+	}
+
+	return execution, nil
+}
+
+// isContractTransaction reports whether the transaction is executed on the world state: it
+// deploys or calls a contract, or transfers value to an account. Transfers are executed like
+// calls, so that they move value and use up the sender's nonce and cannot be replayed.
+func (bc *Blockchain) isContractTransaction(tx *Transaction) bool {
+	return tx.IsEthereumTransaction() || tx.RequiresSignature()
+}
+
+// applyContractTransaction executes a contract transaction and fills in its receipt. The
+// sender was authenticated by executeBlockLocked, so gas is only charged to accounts that
+// signed the transaction.
+func (bc *Blockchain) applyContractTransaction(statedb *contracts.StateDB, block *Block, index int, tx *Transaction, receipt *Receipt, execution *blockExecution) {
+	if err := checkNonce(statedb, tx); err != nil {
+		receipt.Status = TxFailed
+		receipt.Error = err.Error()
+		return
+	}
+
+	if err := checkGasPrice(statedb, tx); err != nil {
+		receipt.Status = TxFailed
+		receipt.Error = err.Error()
+		return
+	}
+
+	msg := contractMessage(block, tx, execution.context)
+	statedb.SetTxContext(common.BytesToHash(tx.Hash), index)
+	logStart := len(statedb.Logs())
+	result := bc.ContractManager.ApplyMessage(statedb, msg)
+
+	// Başarısız işlemlerin log'ları revert ile silinmiştir
+	for _, log := range statedb.Logs()[logStart:] {
+		log.BlockNumber = block.Header.Height
+		receipt.Logs = append(receipt.Logs, log)
+	}
+
+	receipt.GasUsed = result.GasUsed
+	receipt.GasRefunded = result.RefundedGas
+	receipt.Fee = result.Fee.String()
+	if !msg.IsDeployment() {
+		receipt.ReturnData = hex.EncodeToString(result.ReturnData)
+	}
+	if result.Failed() {
+		receipt.Status = TxFailed
+		receipt.Error = result.Err.Error()
+		return
+	}
+
+	if tx.IsProxyOperation() {
+		bc.recordProxyOperation(statedb, block, tx, msg, result, receipt, execution)
+		return
+	}
+
+	if msg.IsDeployment() {
+		receipt.ContractAddress = result.ContractAddress.Hex()
+		contract := &contracts.Contract{
+			Address:   result.ContractAddress,
+			Code:      statedb.GetCode(result.ContractAddress),
+			Owner:     msg.From,
+			Name:      tx.ContractName,
+			Version:   tx.ContractVersion,
+			Timestamp: msg.Timestamp,
+			IsEnabled: true,
+			ABI:       contractABI(tx.ContractABI),
+		}
+		contracts.SetContractMetadata(statedb, contract)
+		execution.deployed = append(execution.deployed, contract)
+	}
+}
+
+// executionContract returns the record of a contract as left by the transactions of the
+// block executed so far, nil if the contract has none
+func (bc *Blockchain) executionContract(execution *blockExecution, address common.Address) *contracts.Contract {
+	var contract *contracts.Contract
+	for _, deployed := range execution.deployed {
+		if deployed.Address == address {
+			contract = deployed
+		}
+	}
+	if contract == nil {
+		existing, err := bc.ContractManager.GetContract(address)
+		if err != nil {
+			return nil
+		}
+		contract = existing
+	}
+
+	for _, upgrade := range execution.upgrades {
+		if upgrade.proxy == address {
+			contract = contract.WithUpgrade(upgrade.version, upgrade.code, upgrade.abi)
+		}
+	}
+	return contract
+}
+
+// contractMessage builds the message applying a contract transaction included in the block,
+// executed in the block's EVM context
+func contractMessage(block *Block, tx *Transaction, context *contracts.BlockContext) *contracts.Message {
+	msg := &contracts.Message{
+		From:      common.HexToAddress(tx.From),
+		Value:     tx.Value,
+		Data:      tx.Data,
+		GasLimit:  tx.GasLimit,
+		GasPrice:  new(big.Int).SetUint64(tx.GasPrice),
+		Coinbase:  validatorAccount(block.Header.ValidatorAddress),
+		Timestamp: block.Header.Timestamp.Unix(),
+		Block:     context,
+	}
+	if !tx.IsContractCreation() {
+		to := common.HexToAddress(tx.To)
+		msg.To = &to
+	} else if len(tx.Salt) > 0 {
+		salt := common.BytesToHash(tx.Salt)
+		msg.Salt = &salt
+	}
+	if tx.IsProxyOperation() {
+		implementation := common.HexToAddress(tx.Implementation)
+		msg.Implementation = &implementation
+		if tx.IsContractCreation() {
+			msg.Name = tx.ContractName
+		}
+	}
+	return msg
+}
+
+// contractABI returns the ABI of a deployment transaction, nil if it has none
+func contractABI(data string) json.RawMessage {
+	if data == "" {
+		return nil
+	}
+	return json.RawMessage(data)
+}
+
+// validatorAccount returns the EVM account credited with the fees of the blocks a validator produces
+func validatorAccount(address string) common.Address {
+	return common.HexToAddress(address)
+}
+
+// checkExecution verifies the state and receipt roots committed in the block header.
+// Blocks without contract transactions leave the state unchanged and are not checked.
+func checkExecution(block *Block, execution *blockExecution) error {
+	if !execution.executed {
+		return nil
+	}
+	if !bytes.Equal(block.Header.StateRoot, execution.stateRoot) {
+		return errors.New("invalid state root")
+	}
+	if !bytes.Equal(block.Header.ReceiptRoot, execution.receiptRoot) {
+		return errors.New("invalid receipt root")
+	}
+	if !bytes.Equal(block.Header.LogsBloom, execution.logsBloom) {
+		return errors.New("invalid logs bloom")
+	}
+	return nil
+}
+
+// applyExecutionLocked makes the executed block state current. Caller must hold the lock.
+func (bc *Blockchain) applyExecutionLocked(block *Block, execution *blockExecution) {
+	if execution.executed {
+		// Yalnızca blokta değişen hesaplar güncel state'e yazılır ve geçmiş için saklanır
+		current := bc.ContractManager.State()
+		bc.recordStateLocked(block.Header.Height, execution.state.Diff(current))
+		current.Apply(current.Diff(execution.state))
+	} else {
+		bc.recordStateLocked(block.Header.Height, nil)
+	}
+	for _, contract := range execution.deployed {
+		bc.ContractManager.RegisterContract(contract)
+	}
+	for _, upgrade := range execution.upgrades {
+		if err := bc.ContractManager.RecordUpgrade(upgrade.proxy, upgrade.version, upgrade.code, upgrade.abi); err != nil {
+			fmt.Printf("Proxy upgrade could not be recorded: %v\n", err)
+		}
+	}
+	bc.applyValidatorSetChangesLocked(execution.receipts)
+	bc.applyContractAccessChangesLocked(execution.receipts)
+
+	if bc.receipts == nil {
+		bc.receipts = make(map[string]*Receipt)
+	}
+	blockHash := block.GetHashString()
+	for i, receipt := range execution.receipts {
+		receipt.BlockHash = blockHash
+		bc.receipts[receipt.TxHash] = receipt
+		for _, log := range receipt.Logs {
+			log.BlockHash = common.BytesToHash(block.GetHash())
+		}
+
+		tx := block.Transactions[i]
+		bc.Mempool.RemoveTransaction(tx.Hash)
+
+		if tx.IsContractCreation() {
+			bc.emitDeploymentResult(tx, receipt)
+		}
+	}
+
+	// Log'lar /ws aboneliklerine blok sırasıyla yayınlanır
+	if bc.EventEmitter != nil {
+		for _, receipt := range execution.receipts {
+			for _, log := range receipt.Logs {
+				data := map[string]interface{}{"log": log}
+				if event, ok := bc.decodeLog(log, block.Header.Timestamp.Unix()); ok {
+					data["event"] = event
+					bc.EventEmitter.EmitContractEvent(*event)
+				}
+				bc.EventEmitter.Emit(EventContractLog, data)
+			}
+		}
+	}
+}
+
+// decodeLog decodes a log with the ABI of the contract that emitted it
+func (bc *Blockchain) decodeLog(log *types.Log, timestamp int64) (*ContractEvent, bool) {
+	contract, err := bc.ContractManager.GetContract(log.Address)
+	if err != nil || len(contract.ABI) == 0 {
+		return nil, false
+	}
+	contractABI, err := contract.ParsedABI()
+	if err != nil {
+		return nil, false
+	}
+	name, args, err := contracts.DecodeLog(contractABI, log)
+	if err != nil {
+		return nil, false
+	}
+
+	return &ContractEvent{
+		Address:     log.Address,
+		Name:        name,
+		Args:        args,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		Timestamp:   timestamp,
+	}, true
+}
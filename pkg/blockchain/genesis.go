@@ -0,0 +1,217 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Genesis is the configuration a chain starts with. It must be the same on every node.
+type Genesis struct {
+	// Config is the chain ID and fork activation of the EVM, in go-ethereum's chain config format
+	Config *params.ChainConfig `json:"config"`
+	// Timestamp is the time of the genesis block in Unix seconds
+	Timestamp int64 `json:"timestamp"`
+	// Validators are the hex encoded uncompressed public keys of the validators the chain
+	// starts with. A chain without them is run by the local validator alone; its genesis
+	// state then holds the local validator, so only nodes with the same key share it.
+	Validators []hexutil.Bytes `json:"validators,omitempty"`
+	// MaxCodeSize is the maximum runtime code size of contracts, the EIP-170 limit if 0
+	MaxCodeSize int `json:"maxCodeSize,omitempty"`
+	// DeniedOpcodes are the names of opcodes rejected in contract code, e.g. SELFDESTRUCT
+	DeniedOpcodes []string `json:"deniedOpcodes,omitempty"`
+	// Alloc are the accounts the genesis state starts with
+	Alloc map[common.Address]GenesisAccount `json:"alloc,omitempty"`
+}
+
+// GenesisAccount is an account of the genesis state, in go-ethereum's genesis alloc format
+type GenesisAccount struct {
+	Balance *math.HexOrDecimal256       `json:"balance"`
+	Nonce   uint64                      `json:"nonce,omitempty"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// DefaultGenesis returns the genesis of chains started without one
+func DefaultGenesis() *Genesis {
+	return &Genesis{Config: contracts.DefaultChainConfig()}
+}
+
+// LoadGenesis reads a genesis file. A file without a config uses the default chain config.
+func LoadGenesis(path string) (*Genesis, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("genesis file could not be read: %v", err)
+	}
+
+	genesis := &Genesis{}
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis file: %v", err)
+	}
+	if genesis.Config == nil {
+		genesis.Config = contracts.DefaultChainConfig()
+	}
+	if err := contracts.ValidateChainConfig(genesis.Config); err != nil {
+		return nil, fmt.Errorf("invalid genesis config: %v", err)
+	}
+	if _, err := genesis.authorities(); err != nil {
+		return nil, err
+	}
+	if _, err := genesis.validationConfig(); err != nil {
+		return nil, err
+	}
+	if err := genesis.validateAlloc(); err != nil {
+		return nil, err
+	}
+	return genesis, nil
+}
+
+// validateAlloc checks the genesis accounts. System contract accounts are created by the
+// chain itself and cannot be allocated.
+func (g *Genesis) validateAlloc() error {
+	for address, account := range g.Alloc {
+		if contracts.IsSystemContract(address) {
+			return fmt.Errorf("invalid genesis alloc: %s is a system contract", address.Hex())
+		}
+		if account.Balance != nil && (*big.Int)(account.Balance).Sign() < 0 {
+			return fmt.Errorf("invalid genesis alloc: negative balance of %s", address.Hex())
+		}
+	}
+	return nil
+}
+
+// initState writes the genesis state: the system contracts with the validator set of the
+// public keys and the allocated accounts. It returns the state root.
+func (g *Genesis) initState(statedb *contracts.StateDB, publicKeys [][]byte) (common.Hash, error) {
+	if err := g.validateAlloc(); err != nil {
+		return common.Hash{}, err
+	}
+	if err := contracts.InitSystemState(statedb, publicKeys); err != nil {
+		return common.Hash{}, err
+	}
+	for address, account := range g.Alloc {
+		statedb.CreateAccount(address)
+		if account.Balance != nil {
+			statedb.AddBalance(address, (*big.Int)(account.Balance))
+		}
+		statedb.SetNonce(address, account.Nonce)
+		if len(account.Code) > 0 {
+			statedb.SetCode(address, account.Code)
+		}
+		for key, value := range account.Storage {
+			statedb.SetState(address, key, value)
+		}
+	}
+	return statedb.Commit(), nil
+}
+
+// validationConfig returns the checks run on contract code on deploy. They are part of
+// block execution, so they come from the genesis rather than the node's settings.
+func (g *Genesis) validationConfig() (contracts.ValidationConfig, error) {
+	config := contracts.DefaultValidationConfig()
+	if g.MaxCodeSize < 0 {
+		return config, fmt.Errorf("invalid genesis max code size %d", g.MaxCodeSize)
+	}
+	if g.MaxCodeSize > 0 {
+		config.MaxCodeSize = g.MaxCodeSize
+	}
+
+	denied, err := contracts.ParseOpcodes(g.DeniedOpcodes)
+	if err != nil {
+		return config, fmt.Errorf("invalid genesis denied opcodes: %v", err)
+	}
+	config.DeniedOpcodes = denied
+	return config, nil
+}
+
+// authorities returns the validators listed in the genesis
+func (g *Genesis) authorities() ([]*validator.Authority, error) {
+	authorities := make([]*validator.Authority, 0, len(g.Validators))
+	for i, publicKey := range g.Validators {
+		authority, err := validator.NewAuthorityFromPublicKey(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid genesis validator %d: %v", i, err)
+		}
+		authorities = append(authorities, authority)
+	}
+	return authorities, nil
+}
+
+// genesisBlock returns the block at height 0 with the root of the genesis state. It holds
+// only data of the genesis, so every node started with the same genesis has the same
+// genesis hash; it has no producer and no signature.
+func genesisBlock(genesis *Genesis, stateRoot common.Hash) (*Block, error) {
+	block := &Block{
+		Header: &Header{
+			Version:   1,
+			Timestamp: time.Unix(genesis.Timestamp, 0).UTC(),
+			PrevHash:  make([]byte, 32),
+			StateRoot: stateRoot.Bytes(),
+			GasLimit:  DefaultBlockGasLimit,
+		},
+		Transactions: make([]*Transaction, 0),
+	}
+
+	txRoot, err := block.calculateTransactionRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate transaction root: %v", err)
+	}
+	block.Header.TransactionRoot = txRoot
+	return block, nil
+}
+
+// ChainConfig returns the chain ID and fork configuration contracts run with
+func (bc *Blockchain) ChainConfig() *params.ChainConfig {
+	return bc.ContractManager.ChainConfig()
+}
+
+// blockContextLocked returns the EVM block context of the block with the header. The
+// block's ancestors must be in the chain. Caller must hold the lock.
+func (bc *Blockchain) blockContextLocked(header *Header) *contracts.BlockContext {
+	// BLOCKHASH yalnızca son 256 bloğa erişebilir
+	first := uint64(0)
+	if header.Height > 256 {
+		first = header.Height - 256
+	}
+	last := header.Height
+	if last > uint64(len(bc.Blocks)) {
+		last = uint64(len(bc.Blocks))
+	}
+	var ancestors []*Block
+	if first < last {
+		ancestors = append(ancestors, bc.Blocks[first:last]...)
+	}
+
+	return &contracts.BlockContext{
+		Number:   header.Height,
+		Time:     uint64(header.Timestamp.Unix()),
+		Coinbase: validatorAccount(header.ValidatorAddress),
+		GasLimit: header.GasLimit,
+		GetHash: func(number uint64) common.Hash {
+			if number < first || number-first >= uint64(len(ancestors)) {
+				return common.Hash{}
+			}
+			return common.BytesToHash(ancestors[number-first].GetHash())
+		},
+	}
+}
+
+// pendingBlockContextLocked returns the EVM block context of a block built on the block
+// at the given height, used for simulations and checks against that block's state.
+// Caller must hold the lock.
+func (bc *Blockchain) pendingBlockContextLocked(height uint64) *contracts.BlockContext {
+	return bc.blockContextLocked(&Header{
+		Height:    height + 1,
+		Timestamp: time.Now().UTC(),
+		GasLimit:  DefaultBlockGasLimit,
+	})
+}
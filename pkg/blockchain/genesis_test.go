@@ -0,0 +1,234 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TestGenesisBlockContext kontratların genesis chain config'i ve gerçek blok bilgileriyle çalıştığını test eder
+func TestGenesisBlockContext(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	config := `{"config": {"chainId": 4242, "homesteadBlock": 0, "eip150Block": 0, "eip155Block": 0, "eip158Block": 0,
+		"byzantiumBlock": 0, "constantinopleBlock": 0, "petersburgBlock": 0, "istanbulBlock": 0, "berlinBlock": 0, "londonBlock": 0}}`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("Genesis dosyası yazılamadı: %v", err)
+	}
+	genesis, err := LoadGenesis(path)
+	if err != nil {
+		t.Fatalf("Genesis yüklenemedi: %v", err)
+	}
+
+	bc, err := NewBlockchainWithGenesis(v, genesis)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+	if bc.ChainConfig().ChainID.Int64() != 4242 {
+		t.Errorf("Chain ID genesis'ten alınmadı: %s", bc.ChainConfig().ChainID)
+	}
+
+	// Çağrıldığında NUMBER, BLOCKHASH(NUMBER-1), CHAINID ve TIMESTAMP değerlerini slot 0-3'e yazan kontrat
+	key, owner := createTestAccount(t)
+	code := append(common.FromHex("6015600c60003960156000f3"), common.FromHex("436000556001430340600155466002554260035500")...)
+	receipt := addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{From: owner.Hex(), Data: code, GasLimit: 200000}))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Deploy başarısız: %s", receipt.Error)
+	}
+	address := common.HexToAddress(receipt.ContractAddress)
+	receipt = addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{From: owner.Hex(), To: address.Hex(), Data: []byte{0x01}, GasLimit: 200000, Nonce: 1}))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Çağrı başarısız: %s", receipt.Error)
+	}
+
+	block := bc.GetBlockByHeight(receipt.BlockHeight)
+	slot := func(i int64) common.Hash {
+		value, err := bc.GetStorageAt(address, common.BigToHash(big.NewInt(i)), nil)
+		if err != nil {
+			t.Fatalf("Storage okunamadı: %v", err)
+		}
+		return value
+	}
+	if number := slot(0).Big().Uint64(); number != block.Header.Height {
+		t.Errorf("NUMBER blok yüksekliği olmalı: %d", number)
+	}
+	if hash := slot(1); hash != common.BytesToHash(bc.GetBlockByHeight(block.Header.Height-1).GetHash()) {
+		t.Errorf("BLOCKHASH önceki bloğun hash'i olmalı: %s", hash.Hex())
+	}
+	if chainID := slot(2).Big().Int64(); chainID != 4242 {
+		t.Errorf("CHAINID hatalı: %d", chainID)
+	}
+	if timestamp := slot(3).Big().Int64(); timestamp != block.Header.Timestamp.Unix() {
+		t.Errorf("TIMESTAMP blok zamanı olmalı: %d", timestamp)
+	}
+
+	// Sırasız fork'lar içeren genesis reddedilir
+	if err := os.WriteFile(path, []byte(`{"config": {"chainId": 1, "londonBlock": 0}}`), 0644); err != nil {
+		t.Fatalf("Genesis dosyası yazılamadı: %v", err)
+	}
+	if _, err := LoadGenesis(path); err == nil {
+		t.Error("Geçersiz genesis kabul edildi")
+	}
+}
+
+// TestDeterministicGenesis aynı genesis ile başlayan node'ların aynı genesis hash'ine ve validator setine sahip olduğunu test eder
+func TestDeterministicGenesis(t *testing.T) {
+	v1, _ := createTestValidator(t)
+	v2, _ := createTestValidator(t)
+	observer, _ := createTestValidator(t)
+
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	config := fmt.Sprintf(`{"timestamp": 1700000000, "validators": ["%s", "%s"]}`,
+		hexutil.Encode(v1.PublicKeyBytes()), hexutil.Encode(v2.PublicKeyBytes()))
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("Genesis dosyası yazılamadı: %v", err)
+	}
+	genesis, err := LoadGenesis(path)
+	if err != nil {
+		t.Fatalf("Genesis yüklenemedi: %v", err)
+	}
+
+	// Farklı yerel validator'larla çalışan node'lar
+	var chains []*Blockchain
+	for _, local := range []*validator.Authority{v1, v2, observer} {
+		bc, err := NewBlockchainWithGenesis(local, genesis)
+		if err != nil {
+			t.Fatalf("Blockchain oluşturulamadı: %v", err)
+		}
+		chains = append(chains, bc)
+	}
+
+	hash := chains[0].GetBlockByHeight(0).GetHashString()
+	for i, bc := range chains {
+		block := bc.GetBlockByHeight(0)
+		if block.GetHashString() != hash {
+			t.Errorf("Node %d genesis hash'i farklı: %s != %s", i, block.GetHashString(), hash)
+		}
+		if block.Header.Timestamp.Unix() != 1700000000 {
+			t.Errorf("Genesis zamanı dosyadan alınmadı: %d", block.Header.Timestamp.Unix())
+		}
+		if len(bc.Validators) != 2 || bc.GetValidator(v1.Address) == nil || bc.GetValidator(v2.Address) == nil {
+			t.Errorf("Node %d validator seti genesis'ten alınmadı: %d validator", i, len(bc.Validators))
+		}
+	}
+
+	// Genesis'te listelenen yerel validator blok üretebilir
+	if _, err := chains[1].CreateBlock(v2); err != nil {
+		t.Errorf("Genesis validator'ı blok üretemedi: %v", err)
+	}
+
+	// Geçersiz public key içeren genesis reddedilir
+	if err := os.WriteFile(path, []byte(`{"validators": ["0x0102"]}`), 0644); err != nil {
+		t.Fatalf("Genesis dosyası yazılamadı: %v", err)
+	}
+	if _, err := LoadGenesis(path); err == nil {
+		t.Error("Geçersiz validator içeren genesis kabul edildi")
+	}
+}
+
+// TestGenesisValidationConfig kontrat kodu sınırlarının genesis'ten alındığını test eder
+func TestGenesisValidationConfig(t *testing.T) {
+	v, _ := createTestValidator(t)
+
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	if err := os.WriteFile(path, []byte(`{"maxCodeSize": 4, "deniedOpcodes": ["selfdestruct"]}`), 0644); err != nil {
+		t.Fatalf("Genesis dosyası yazılamadı: %v", err)
+	}
+	genesis, err := LoadGenesis(path)
+	if err != nil {
+		t.Fatalf("Genesis yüklenemedi: %v", err)
+	}
+	bc, err := NewBlockchainWithGenesis(v, genesis)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	if err := bc.ContractManager.ValidateRuntimeCode(common.FromHex("6000ff")); err == nil {
+		t.Error("Genesis'te yasaklanan opcode kabul edildi")
+	}
+	if err := bc.ContractManager.ValidateRuntimeCode(common.FromHex("6000600000")); err == nil {
+		t.Error("Genesis'teki kod boyutu sınırı uygulanmadı")
+	}
+	if err := bc.ContractManager.ValidateRuntimeCode(common.FromHex("600000")); err != nil {
+		t.Errorf("Geçerli kod reddedildi: %v", err)
+	}
+
+	// Bilinmeyen opcode içeren genesis reddedilir
+	if err := os.WriteFile(path, []byte(`{"deniedOpcodes": ["NOPE"]}`), 0644); err != nil {
+		t.Fatalf("Genesis dosyası yazılamadı: %v", err)
+	}
+	if _, err := LoadGenesis(path); err == nil {
+		t.Error("Geçersiz opcode listesi kabul edildi")
+	}
+}
+
+// TestGenesisAlloc genesis hesaplarının state'e yazıldığını ve genesis state root'unun başlıkta olduğunu test eder
+func TestGenesisAlloc(t *testing.T) {
+	v1, _ := createTestValidator(t)
+	observer, _ := createTestValidator(t)
+
+	funded := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	contract := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	config := fmt.Sprintf(`{"validators": ["%s"], "alloc": {
+		"%s": {"balance": "1000000000000000000"},
+		"%s": {"balance": "0x10", "nonce": 1, "code": "0x602a60005260206000f3", "storage": {"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000007"}}
+	}}`, hexutil.Encode(v1.PublicKeyBytes()), funded.Hex(), contract.Hex())
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("Genesis dosyası yazılamadı: %v", err)
+	}
+	genesis, err := LoadGenesis(path)
+	if err != nil {
+		t.Fatalf("Genesis yüklenemedi: %v", err)
+	}
+
+	var chains []*Blockchain
+	for _, local := range []*validator.Authority{v1, observer} {
+		bc, err := NewBlockchainWithGenesis(local, genesis)
+		if err != nil {
+			t.Fatalf("Blockchain oluşturulamadı: %v", err)
+		}
+		chains = append(chains, bc)
+	}
+
+	bc := chains[0]
+	state := bc.ContractManager.State()
+	if balance := state.GetBalance(funded); balance.Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("Genesis bakiyesi yazılmadı: %s", balance)
+	}
+	statedb := contracts.NewStateDB(state)
+	if statedb.GetBalance(contract).Int64() != 16 || statedb.GetNonce(contract) != 1 || len(statedb.GetCode(contract)) == 0 {
+		t.Error("Genesis kontrat hesabı yazılmadı")
+	}
+	if value := statedb.GetState(contract, common.BigToHash(big.NewInt(1))); value != common.BigToHash(big.NewInt(7)) {
+		t.Errorf("Genesis storage'ı yazılmadı: %s", value.Hex())
+	}
+
+	// Genesis başlığı gerçek state root'unu taşır ve tüm node'larda aynıdır
+	root := common.BytesToHash(bc.GetBlockByHeight(0).Header.StateRoot)
+	if root == (common.Hash{}) || root != bc.GetStateRoot() {
+		t.Errorf("Genesis state root'u hatalı: %s", root.Hex())
+	}
+	if chains[1].GetStateRoot() != root || chains[1].GetBlockByHeight(0).GetHashString() != bc.GetBlockByHeight(0).GetHashString() {
+		t.Error("Aynı genesis ile başlayan node'ların genesis state'i aynı olmalı")
+	}
+
+	// Sistem kontratı adresine hesap ayrılamaz
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(`{"alloc": {"%s": {"balance": "1"}}}`, contracts.ValidatorSetAddress.Hex())), 0644); err != nil {
+		t.Fatalf("Genesis dosyası yazılamadı: %v", err)
+	}
+	if _, err := LoadGenesis(path); err == nil {
+		t.Error("Sistem kontratına ayrılan genesis hesabı kabul edildi")
+	}
+}
@@ -0,0 +1,148 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// MaxLogBlockRange is the maximum number of blocks a single log query may scan
+const MaxLogBlockRange = 10000
+
+// LogFilter selects contract logs by block range, emitting address and topics.
+// A log matches if it was emitted by one of the addresses (any if empty) and each
+// of its topics matches one of the hashes at the same position (any if empty).
+type LogFilter struct {
+	FromBlock *uint64          `json:"from_block,omitempty"` // Earliest block if nil
+	ToBlock   *uint64          `json:"to_block,omitempty"`   // Latest block if nil
+	Addresses []common.Address `json:"address,omitempty"`
+	Topics    [][]common.Hash  `json:"topics,omitempty"`
+}
+
+// Matches reports whether the log matches the address and topic criteria of the filter
+func (f *LogFilter) Matches(log *types.Log) bool {
+	if len(f.Addresses) > 0 && !containsAddress(f.Addresses, log.Address) {
+		return false
+	}
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, sub := range f.Topics {
+		if len(sub) > 0 && !containsHash(sub, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomMatches reports whether a block with the given bloom may contain matching logs
+func (f *LogFilter) bloomMatches(bloom types.Bloom) bool {
+	if len(f.Addresses) > 0 {
+		found := false
+		for _, address := range f.Addresses {
+			if types.BloomLookup(bloom, address) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, sub := range f.Topics {
+		if len(sub) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range sub {
+			if types.BloomLookup(bloom, topic) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// GetLogs returns the logs of the blocks in the filter range that match the filter.
+// Blocks whose logs bloom rules out a match are skipped without reading their receipts.
+func (bc *Blockchain) GetLogs(filter *LogFilter) ([]*types.Log, error) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	latest := uint64(len(bc.Blocks) - 1)
+	from, to := uint64(0), latest
+	if filter.FromBlock != nil {
+		from = *filter.FromBlock
+	}
+	if filter.ToBlock != nil && *filter.ToBlock < latest {
+		to = *filter.ToBlock
+	}
+	if from > to {
+		return []*types.Log{}, nil
+	}
+	if to-from >= MaxLogBlockRange {
+		return nil, fmt.Errorf("block range exceeds the limit of %d blocks", MaxLogBlockRange)
+	}
+
+	logs := make([]*types.Log, 0)
+	for height := from; height <= to; height++ {
+		block := bc.Blocks[height]
+		if len(block.Header.LogsBloom) == 0 || !filter.bloomMatches(types.BytesToBloom(block.Header.LogsBloom)) {
+			continue
+		}
+
+		for _, tx := range block.Transactions {
+			receipt, exists := bc.receipts[hex.EncodeToString(tx.Hash)]
+			if !exists {
+				continue
+			}
+			for _, log := range receipt.Logs {
+				if filter.Matches(log) {
+					logs = append(logs, log)
+				}
+			}
+		}
+	}
+	return logs, nil
+}
+
+// logsBloom builds the bloom filter over the addresses and topics of the logs
+func logsBloom(receipts []*Receipt) types.Bloom {
+	var bloom types.Bloom
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			bloom.Add(log.Address.Bytes())
+			for _, topic := range log.Topics {
+				bloom.Add(topic.Bytes())
+			}
+		}
+	}
+	return bloom
+}
+
+// containsAddress reports whether the address is in the list
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// containsHash reports whether the hash is in the list
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestContractLogs LOG çıktılarının receipt'lere, blok bloom'una ve log sorgularına yansıdığını test eder
+func TestContractLogs(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	logChan := bc.EventEmitter.Subscribe(EventContractLog)
+	defer bc.EventEmitter.Unsubscribe(EventContractLog, logChan)
+
+	// Runtime kodu 42 değerini 1 topic'i ile LOG1 olarak yayınlar
+	key, owner := createTestAccount(t)
+	code := append(common.FromHex("600d600c600039600d6000f3"), common.FromHex("602a600052600160206000a100")...)
+	addBlock := func(tx *Transaction) *Receipt {
+		if err := bc.SubmitTransaction(signTestTransaction(t, key, tx)); err != nil {
+			t.Fatalf("İşlem gönderilemedi: %v", err)
+		}
+		block, err := bc.CreateBlock(v)
+		if err != nil {
+			t.Fatalf("Blok oluşturulamadı: %v", err)
+		}
+		if err := bc.AddBlock(block); err != nil {
+			t.Fatalf("Blok eklenemedi: %v", err)
+		}
+		receipt, _ := bc.GetReceipt(hex.EncodeToString(tx.Hash))
+		if receipt == nil || receipt.Status != TxSuccess {
+			t.Fatal("İşlem başarısız")
+		}
+		return receipt
+	}
+
+	address := common.HexToAddress(addBlock(&Transaction{From: owner.Hex(), Data: code, GasLimit: 200000}).ContractAddress)
+	receipt := addBlock(&Transaction{From: owner.Hex(), To: address.Hex(), GasLimit: 100000, Nonce: 1})
+
+	topic := common.BigToHash(big.NewInt(1))
+	if len(receipt.Logs) != 1 {
+		t.Fatalf("Receipt'te 1 log bekleniyordu, alınan %d", len(receipt.Logs))
+	}
+	log := receipt.Logs[0]
+	if log.Address != address || len(log.Topics) != 1 || log.Topics[0] != topic {
+		t.Errorf("Log adresi veya topic'leri hatalı: %+v", log)
+	}
+	if new(big.Int).SetBytes(log.Data).Int64() != 42 || log.BlockNumber != 2 {
+		t.Errorf("Log verisi hatalı: %+v", log)
+	}
+
+	block := bc.GetBlockByHeight(2)
+	if log.BlockHash != common.BytesToHash(block.GetHash()) {
+		t.Error("Log blok hash'i hatalı")
+	}
+	bloom := types.BytesToBloom(block.Header.LogsBloom)
+	if !types.BloomLookup(bloom, address) || !types.BloomLookup(bloom, topic) {
+		t.Error("Blok bloom'u log adresini ve topic'ini içermiyor")
+	}
+
+	query := func(filter *LogFilter) int {
+		logs, err := bc.GetLogs(filter)
+		if err != nil {
+			t.Fatalf("Log sorgusu başarısız: %v", err)
+		}
+		return len(logs)
+	}
+	from := uint64(3)
+	if n := query(&LogFilter{Addresses: []common.Address{address}, Topics: [][]common.Hash{{topic}}}); n != 1 {
+		t.Errorf("Adres ve topic filtresi 1 log döndürmeli, alınan %d", n)
+	}
+	if n := query(&LogFilter{Topics: [][]common.Hash{{common.BigToHash(big.NewInt(2))}}}); n != 0 {
+		t.Errorf("Eşleşmeyen topic filtresi log döndürdü: %d", n)
+	}
+	if n := query(&LogFilter{Addresses: []common.Address{owner}}); n != 0 {
+		t.Errorf("Eşleşmeyen adres filtresi log döndürdü: %d", n)
+	}
+	if n := query(&LogFilter{FromBlock: &from}); n != 0 {
+		t.Errorf("Blok aralığı dışındaki log döndürüldü: %d", n)
+	}
+
+	select {
+	case event := <-logChan:
+		if event.Data["log"] != log {
+			t.Error("Yayınlanan log receipt'teki log değil")
+		}
+	case <-time.After(time.Second):
+		t.Error("Log olayı yayınlanmadı")
+	}
+}
@@ -207,6 +207,13 @@ func (mp *Mempool) GetSize() uint64 {
 	return mp.currentSize
 }
 
+// Count havuzdaki işlem sayısını döndürür
+func (mp *Mempool) Count() int {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	return len(mp.transactions)
+}
+
 // calculatePriority işlem önceliğini hesaplar
 func calculatePriority(tx *Transaction) *big.Int {
 	// Öncelik sadece gasPrice'a göre belirlenir
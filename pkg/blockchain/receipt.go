@@ -0,0 +1,53 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Receipt records the outcome of a transaction included in a block
+type Receipt struct {
+	TxHash           string       `json:"tx_hash"`
+	BlockHeight      uint64       `json:"block_height"`
+	BlockHash        string       `json:"block_hash,omitempty"`
+	TransactionIndex int          `json:"transaction_index"`
+	From             string       `json:"from"`
+	To               string       `json:"to,omitempty"`
+	ContractAddress  string       `json:"contract_address,omitempty"`
+	Status           TxStatus     `json:"status"`
+	GasUsed          uint64       `json:"gas_used"`
+	GasRefunded      uint64       `json:"gas_refunded,omitempty"`
+	Fee              string       `json:"fee,omitempty"` // GasUsed * GasPrice in wei, credited to the validator
+	ReturnData       string       `json:"return_data,omitempty"`
+	Logs             []*types.Log `json:"logs,omitempty"`
+	Error            string       `json:"error,omitempty"`
+}
+
+// calculateReceiptRoot calculates the merkle root of the receipts of a block.
+// The block hash is not known while the block is built, so it is left out.
+func calculateReceiptRoot(receipts []*Receipt) ([]byte, error) {
+	hashes := make([][]byte, 0, len(receipts))
+	for _, receipt := range receipts {
+		r := *receipt
+		r.BlockHash = ""
+		r.Logs = make([]*types.Log, len(receipt.Logs))
+		for i, log := range receipt.Logs {
+			l := *log
+			l.BlockHash = common.Hash{}
+			r.Logs[i] = &l
+		}
+
+		data, err := json.Marshal(&r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal receipt: %v", err)
+		}
+		hash := sha256.Sum256(data)
+		hashes = append(hashes, hash[:])
+	}
+
+	return merkleRoot(hashes), nil
+}
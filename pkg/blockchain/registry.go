@@ -0,0 +1,114 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CreateProxy submits a signed transaction creating an upgradeable proxy registered under
+// the transaction's contract name, owned by its sender. The proxy runs the code of the
+// implementation with its own storage; its address stays the same across upgrades and is
+// reported in the transaction receipt. An empty version takes the version of the
+// implementation contract.
+func (bc *Blockchain) CreateProxy(tx *Transaction) error {
+	if tx.ContractName == "" {
+		return errors.New("contract name is required")
+	}
+	if !tx.IsContractCreation() || !tx.IsProxyOperation() {
+		return errors.New("not a proxy creation transaction")
+	}
+	if contracts.ProxyAddress(contracts.NewStateDB(bc.ContractManager.State()), tx.ContractName) != (common.Address{}) {
+		return errors.New("contract name is already registered")
+	}
+	return bc.submitProxyTransaction(tx)
+}
+
+// UpgradeContract submits a signed transaction repointing the proxy registered under the
+// name to a new implementation; the transaction must be sent to the proxy. Only the owner
+// of the proxy can upgrade it.
+func (bc *Blockchain) UpgradeContract(name string, tx *Transaction) error {
+	proxy, err := bc.ContractManager.GetProxy(name)
+	if err != nil {
+		return err
+	}
+	if !tx.IsProxyOperation() {
+		return errors.New("not a proxy upgrade transaction")
+	}
+	if !common.IsHexAddress(tx.To) || common.HexToAddress(tx.To) != proxy.Address {
+		return errors.New("upgrade transaction must be sent to the proxy")
+	}
+	if contracts.ContractOwner(contracts.NewStateDB(bc.ContractManager.State()), proxy.Address) != common.HexToAddress(tx.From) {
+		return errors.New("only the contract owner can upgrade it")
+	}
+	return bc.submitProxyTransaction(tx)
+}
+
+// GetContractVersions returns the proxy registered under the name with its version history
+func (bc *Blockchain) GetContractVersions(name string) (*contracts.Contract, error) {
+	return bc.ContractManager.GetProxy(name)
+}
+
+// submitProxyTransaction checks the implementation of a proxy transaction and adds it to
+// the mempool
+func (bc *Blockchain) submitProxyTransaction(tx *Transaction) error {
+	if !common.IsHexAddress(tx.Implementation) {
+		return errors.New("invalid implementation address")
+	}
+	if _, err := bc.GetContract(common.HexToAddress(tx.Implementation)); err != nil {
+		return err
+	}
+	return bc.SubmitTransaction(tx)
+}
+
+// recordProxyOperation records the version created by a successful proxy transaction. The
+// proxy takes over the ABI of the implementation.
+func (bc *Blockchain) recordProxyOperation(statedb *contracts.StateDB, block *Block, tx *Transaction, msg *contracts.Message, result *contracts.ExecutionResult, receipt *Receipt, execution *blockExecution) {
+	version := contracts.ImplementationVersion{
+		Version:        tx.ContractVersion,
+		Implementation: *msg.Implementation,
+		BlockHeight:    block.Header.Height,
+		TxHash:         hex.EncodeToString(tx.Hash),
+		Timestamp:      msg.Timestamp,
+	}
+
+	var implementationABI []byte
+	if implementation, err := bc.ContractManager.GetContract(*msg.Implementation); err == nil {
+		if version.Version == "" {
+			version.Version = implementation.Version
+		}
+		implementationABI = implementation.ABI
+	}
+	code := statedb.GetCode(result.ContractAddress)
+
+	if msg.IsDeployment() {
+		receipt.ContractAddress = result.ContractAddress.Hex()
+		contract := &contracts.Contract{
+			Address:   result.ContractAddress,
+			Code:      code,
+			Owner:     msg.From,
+			Name:      tx.ContractName,
+			Version:   version.Version,
+			Timestamp: msg.Timestamp,
+			IsEnabled: true,
+			ABI:       implementationABI,
+			Versions:  []contracts.ImplementationVersion{version},
+		}
+		contracts.SetContractMetadata(statedb, contract)
+		execution.deployed = append(execution.deployed, contract)
+		return
+	}
+
+	// Yükseltilmiş kaydın metadata'sı da state'e bağlanır
+	if proxy := bc.executionContract(execution, result.ContractAddress); proxy != nil {
+		contracts.SetContractMetadata(statedb, proxy.WithUpgrade(version, code, implementationABI))
+	}
+	execution.upgrades = append(execution.upgrades, proxyUpgrade{
+		proxy:   result.ContractAddress,
+		version: version,
+		code:    code,
+		abi:     implementationABI,
+	})
+}
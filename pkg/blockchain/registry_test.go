@@ -0,0 +1,126 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+)
+
+// TestContractUpgrade proxy'nin adresini ve storage'ını koruyarak yeni implementasyona geçtiğini test eder
+func TestContractUpgrade(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	key, owner := createTestAccount(t)
+	include := func(tx *Transaction) *Receipt {
+		block, err := bc.CreateBlock(v)
+		if err != nil {
+			t.Fatalf("Blok oluşturulamadı: %v", err)
+		}
+		if err := bc.AddBlock(block); err != nil {
+			t.Fatalf("Blok eklenemedi: %v", err)
+		}
+		receipt, _ := bc.GetReceipt(hex.EncodeToString(tx.Hash))
+		if receipt == nil || receipt.Status != TxSuccess {
+			t.Fatalf("İşlem başarısız: %+v", receipt)
+		}
+		return receipt
+	}
+	submit := func(tx *Transaction) *Receipt {
+		tx.Nonce = bc.GetNonce(tx.From)
+		if err := bc.SubmitTransaction(signTestTransaction(t, key, tx)); err != nil {
+			t.Fatalf("İşlem gönderilemedi: %v", err)
+		}
+		return include(tx)
+	}
+
+	// Sayaç slot 0'ı v1'de 1, v2'de 2 artırır
+	deploy := func(runtime, version string) common.Address {
+		code := append(common.FromHex("6012600c60003960126000f3"), common.FromHex(runtime)...)
+		receipt := submit(&Transaction{From: owner.Hex(), Data: code, GasLimit: 200000, ContractName: "Counter", ContractVersion: version})
+		return common.HexToAddress(receipt.ContractAddress)
+	}
+	v1 := deploy("6000546001018060005560005260206000f3", "1.0")
+	v2 := deploy("6000546002018060005560005260206000f3", "2.0")
+
+	// Aynı blokta aynı isimle oluşturulan ikinci proxy başarısız olur
+	tx := &Transaction{From: owner.Hex(), GasLimit: 200000, Nonce: bc.GetNonce(owner.Hex()), ContractName: "counter", Implementation: v1.Hex()}
+	if err := bc.CreateProxy(signTestTransaction(t, key, tx)); err != nil {
+		t.Fatalf("Proxy oluşturulamadı: %v", err)
+	}
+	second := &Transaction{From: owner.Hex(), GasLimit: 200000, Nonce: tx.Nonce + 1, ContractName: "counter", Implementation: v2.Hex()}
+	if err := bc.CreateProxy(signTestTransaction(t, key, second)); err != nil {
+		t.Fatalf("İkinci proxy işlemi gönderilemedi: %v", err)
+	}
+	proxy := common.HexToAddress(include(tx).ContractAddress)
+	if receipt, _ := bc.GetReceipt(hex.EncodeToString(second.Hash)); receipt == nil || receipt.Status != TxFailed {
+		t.Fatalf("Aynı isimli ikinci proxy oluşturuldu: %+v", receipt)
+	}
+
+	call := func() int64 {
+		receipt := submit(&Transaction{From: owner.Hex(), To: proxy.Hex(), Data: []byte{0}, GasLimit: 100000})
+		data, _ := hex.DecodeString(receipt.ReturnData)
+		return new(big.Int).SetBytes(data).Int64()
+	}
+	if got := call(); got != 1 {
+		t.Fatalf("v1 sonucu hatalı: %d", got)
+	}
+
+	// Sahibi olmayan hesap yükseltme yapamaz, sahibin adına imzasız yükseltme de reddedilir,
+	// isim tekrar kaydedilemez
+	otherKey, other := createTestAccount(t)
+	upgrade := func(from common.Address) *Transaction {
+		return &Transaction{From: from.Hex(), To: proxy.Hex(), GasLimit: 200000, Nonce: bc.GetNonce(from.Hex()), Implementation: v2.Hex()}
+	}
+	if err := bc.UpgradeContract("counter", signTestTransaction(t, otherKey, upgrade(other))); err == nil {
+		t.Error("Sahibi olmayan hesabın yükseltmesi kabul edildi")
+	}
+	if err := bc.UpgradeContract("counter", signTestTransaction(t, otherKey, upgrade(owner))); err == nil {
+		t.Error("Sahip adına başka anahtarla imzalanmış yükseltme kabul edildi")
+	}
+	if err := bc.UpgradeContract("counter", upgrade(owner)); err == nil {
+		t.Error("İmzasız yükseltme kabul edildi")
+	}
+	duplicate := &Transaction{From: owner.Hex(), GasLimit: 200000, Nonce: bc.GetNonce(owner.Hex()), ContractName: "counter", Implementation: v2.Hex()}
+	if err := bc.CreateProxy(signTestTransaction(t, key, duplicate)); err == nil {
+		t.Error("Aynı isimle ikinci proxy kabul edildi")
+	}
+
+	tx = upgrade(owner)
+	if err := bc.UpgradeContract("counter", signTestTransaction(t, key, tx)); err != nil {
+		t.Fatalf("Kontrat yükseltilemedi: %v", err)
+	}
+	include(tx)
+
+	// Storage korunur, yeni kod çalışır
+	if got := call(); got != 3 {
+		t.Errorf("v2 sonucu hatalı: %d", got)
+	}
+
+	contract, err := bc.GetContractVersions("counter")
+	if err != nil {
+		t.Fatalf("Versiyon geçmişi alınamadı: %v", err)
+	}
+	if contract.Address != proxy || contract.Version != "2.0" || len(contract.Versions) != 2 {
+		t.Fatalf("Proxy kaydı hatalı: %+v", contract)
+	}
+	if contract.Versions[0].Implementation != v1 || contract.Versions[0].Version != "1.0" || contract.Versions[1].Implementation != v2 {
+		t.Errorf("Versiyon geçmişi hatalı: %+v", contract.Versions)
+	}
+
+	// Yükseltilmiş kaydın metadata'sı state'e bağlıdır
+	if _, err := contracts.VerifyContractRecord(contracts.NewStateDB(bc.ContractManager.State()), contract); err != nil {
+		t.Errorf("Proxy kaydı state ile eşleşmiyor: %v", err)
+	}
+}
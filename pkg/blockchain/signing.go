@@ -0,0 +1,110 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrUnsignedTransaction is returned for a transaction reaching the world state without
+	// the signature of its sender
+	ErrUnsignedTransaction = errors.New("transaction must be signed by its sender")
+
+	// ErrInvalidSender is returned when the signature of a transaction was not made by its sender
+	ErrInvalidSender = errors.New("transaction signature does not match its sender")
+
+	// ErrNonceTooLow is returned for a signed transaction whose nonce was already used
+	ErrNonceTooLow = errors.New("nonce too low")
+
+	// ErrNonceTooHigh is returned when a signed transaction is executed before the
+	// transactions of its sender with lower nonces
+	ErrNonceTooHigh = errors.New("nonce too high")
+)
+
+// authenticateSender verifies that a transaction reaching the world state was signed by its
+// sender: Ethereum transactions by their signed encoding, other transactions by a signature
+// over their hash. Transactions that cannot reach the state need no signature.
+func (bc *Blockchain) authenticateSender(tx *Transaction) error {
+	if tx.IsEthereumTransaction() {
+		return bc.verifyEthereumTransaction(tx)
+	}
+	if !tx.RequiresSignature() {
+		return nil
+	}
+	if !common.IsHexAddress(tx.From) {
+		return errors.New("invalid sender address")
+	}
+	if len(tx.Signature) == 0 {
+		return ErrUnsignedTransaction
+	}
+	if !bytes.Equal(tx.Hash, tx.CalculateHash()) {
+		return errors.New("transaction hash does not match its fields")
+	}
+
+	sender, err := tx.Sender()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSender, err)
+	}
+	if sender != common.HexToAddress(tx.From) {
+		return fmt.Errorf("%w: signed by %s", ErrInvalidSender, sender.Hex())
+	}
+	return nil
+}
+
+// checkNonce verifies that a signed transaction is the next transaction of its sender
+func checkNonce(statedb *contracts.StateDB, tx *Transaction) error {
+	nonce := statedb.GetNonce(common.HexToAddress(tx.From))
+	if tx.Nonce < nonce {
+		return fmt.Errorf("%w: have %d, want %d", ErrNonceTooLow, tx.Nonce, nonce)
+	}
+	if tx.Nonce > nonce {
+		return fmt.Errorf("%w: have %d, want %d", ErrNonceTooHigh, tx.Nonce, nonce)
+	}
+	return nil
+}
+
+// orderSignedTransactions puts the signed transactions of each sender in nonce order,
+// keeping the positions the senders' transactions were selected at. Transactions that do
+// not continue their sender's nonce sequence are left out and stay in the mempool.
+func orderSignedTransactions(txs []*Transaction, statedb *contracts.StateDB) []*Transaction {
+	bySender := make(map[common.Address][]*Transaction)
+	for _, tx := range txs {
+		if tx.RequiresSignature() {
+			sender := common.HexToAddress(tx.From)
+			bySender[sender] = append(bySender[sender], tx)
+		}
+	}
+
+	// Her göndericinin işlemleri state'teki nonce'tan başlayarak ardışık olmalı
+	for sender, senderTxs := range bySender {
+		sort.Slice(senderTxs, func(i, j int) bool { return senderTxs[i].Nonce < senderTxs[j].Nonce })
+		next := statedb.GetNonce(sender)
+		valid := senderTxs[:0]
+		for _, tx := range senderTxs {
+			if tx.Nonce == next {
+				valid = append(valid, tx)
+				next++
+			}
+		}
+		bySender[sender] = valid
+	}
+
+	result := make([]*Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if !tx.RequiresSignature() {
+			result = append(result, tx)
+			continue
+		}
+		sender := common.HexToAddress(tx.From)
+		if queue := bySender[sender]; len(queue) > 0 {
+			result = append(result, queue[0])
+			bySender[sender] = queue[1:]
+		}
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StateHistoryLimit is the number of recent blocks whose state is kept for simulations
+const StateHistoryLimit = 128
+
+// recordStateLocked records the accounts the block at the given height changed, as they
+// were before the block, and drops the changes not needed to rebuild the states after the
+// last StateHistoryLimit blocks. Caller must hold the lock.
+func (bc *Blockchain) recordStateLocked(height uint64, changes map[common.Address]*contracts.Account) {
+	if bc.stateChanges == nil {
+		bc.stateChanges = make(map[uint64]map[common.Address]*contracts.Account)
+	}
+	bc.stateChanges[height] = changes
+	bc.stateHeight = height
+
+	for h := range bc.stateChanges {
+		if h+StateHistoryLimit <= height+1 {
+			delete(bc.stateChanges, h)
+		}
+	}
+}
+
+// resetStateHistoryLocked drops the recorded changes after the current state is replaced
+// by the state after the block at the given height. Caller must hold the lock.
+func (bc *Blockchain) resetStateHistoryLocked(height uint64) {
+	bc.stateChanges = nil
+	bc.stateHeight = height
+}
+
+// stateAtLocked rebuilds the state after the block at the given height by reverting the
+// changes of the later blocks on a copy of the current state. Caller must hold the lock.
+func (bc *Blockchain) stateAtLocked(height uint64) (*contracts.WorldState, error) {
+	if height > bc.stateHeight {
+		return nil, fmt.Errorf("state for block %d is not available", height)
+	}
+	for h := height + 1; h <= bc.stateHeight; h++ {
+		if _, exists := bc.stateChanges[h]; !exists {
+			return nil, fmt.Errorf("state for block %d is not available", height)
+		}
+	}
+
+	state := bc.ContractManager.State().Copy()
+	for h := bc.stateHeight; h > height; h-- {
+		state.Apply(bc.stateChanges[h])
+	}
+	return state, nil
+}
+
+// StateAt returns the state after the block at the given height, or the current
+// state if height is nil. The returned state must not be modified.
+func (bc *Blockchain) StateAt(height *uint64) (*contracts.WorldState, error) {
+	if height == nil {
+		return bc.ContractManager.State(), nil
+	}
+
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	return bc.stateAtLocked(*height)
+}
+
+// simulationAt returns the state at the given height (the current state if nil) and the
+// message to simulate on it. Unless the message sets its block, it runs in a block built
+// on the block at that height.
+func (bc *Blockchain) simulationAt(msg *contracts.Message, height *uint64) (*contracts.WorldState, *contracts.Message, error) {
+	state, err := bc.StateAt(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	if msg.Block != nil {
+		return state, msg, nil
+	}
+
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	parent := uint64(len(bc.Blocks) - 1)
+	if height != nil {
+		parent = *height
+	}
+	cpy := *msg
+	cpy.Block = bc.pendingBlockContextLocked(parent)
+	return state, &cpy, nil
+}
+
+// Call simulates a deployment or call against the state at the given height (the
+// current state if nil) without committing anything
+func (bc *Blockchain) Call(msg *contracts.Message, height *uint64) (*contracts.ExecutionResult, error) {
+	state, msg, err := bc.simulationAt(msg, height)
+	if err != nil {
+		return nil, err
+	}
+	return bc.ContractManager.Simulate(state, msg), nil
+}
+
+// EstimateGas returns the lowest gas limit the message succeeds with against the
+// state at the given height (the current state if nil)
+func (bc *Blockchain) EstimateGas(msg *contracts.Message, height *uint64) (uint64, error) {
+	state, msg, err := bc.simulationAt(msg, height)
+	if err != nil {
+		return 0, err
+	}
+	return bc.ContractManager.EstimateGas(state, msg)
+}
@@ -0,0 +1,181 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestCallAtHeight simülasyonun seçilen bloğun state'i üzerinde çalıştığını test eder
+func TestCallAtHeight(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	key, owner := createTestAccount(t)
+	code := append(common.FromHex("6012600c60003960126000f3"), common.FromHex("6000546001018060005560005260206000f3")...)
+	addBlock := func(tx *Transaction) {
+		if err := bc.SubmitTransaction(signTestTransaction(t, key, tx)); err != nil {
+			t.Fatalf("İşlem gönderilemedi: %v", err)
+		}
+		block, err := bc.CreateBlock(v)
+		if err != nil {
+			t.Fatalf("Blok oluşturulamadı: %v", err)
+		}
+		if err := bc.AddBlock(block); err != nil {
+			t.Fatalf("Blok eklenemedi: %v", err)
+		}
+	}
+
+	deployTx := &Transaction{From: owner.Hex(), Data: code, GasLimit: 200000}
+	addBlock(deployTx)
+	receipt, _ := bc.GetReceipt(hex.EncodeToString(deployTx.Hash))
+	if receipt == nil || receipt.Status != TxSuccess {
+		t.Fatal("Deploy başarısız")
+	}
+	address := common.HexToAddress(receipt.ContractAddress)
+	addBlock(&Transaction{From: owner.Hex(), To: address.Hex(), GasLimit: 100000, Nonce: 1})
+
+	call := func(height *uint64) int64 {
+		result, err := bc.Call(&contracts.Message{From: owner, To: &address}, height)
+		if err != nil {
+			t.Fatalf("Simülasyon yapılamadı: %v", err)
+		}
+		if result.Failed() {
+			t.Fatalf("Simülasyon başarısız: %v", result.Err)
+		}
+		return new(big.Int).SetBytes(result.ReturnData).Int64()
+	}
+
+	// Deploy bloğunda sayaç 0, çağrı bloğundan sonra 1'dir
+	deployHeight := uint64(1)
+	if got := call(&deployHeight); got != 1 {
+		t.Errorf("Deploy bloğundaki simülasyon sonucu hatalı: %d", got)
+	}
+	if got := call(nil); got != 2 {
+		t.Errorf("Son state'teki simülasyon sonucu hatalı: %d", got)
+	}
+
+	missing := uint64(100)
+	if _, err := bc.Call(&contracts.Message{From: owner, To: &address}, &missing); err == nil {
+		t.Error("Olmayan blok için hata bekleniyordu")
+	}
+
+	gas, err := bc.EstimateGas(&contracts.Message{From: owner, To: &address}, nil)
+	if err != nil || gas == 0 {
+		t.Errorf("Gas tahmin edilemedi: %d %v", gas, err)
+	}
+}
+
+// TestStorageAtHeight storage ve kodun seçilen bloğun state'inden okunduğunu test eder
+func TestStorageAtHeight(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	key, owner := createTestAccount(t)
+	runtime := common.FromHex("6000546001018060005560005260206000f3")
+	code := append(common.FromHex("6012600c60003960126000f3"), runtime...)
+	deployTx := &Transaction{From: owner.Hex(), Data: code, GasLimit: 200000}
+	receipt := addTransactionBlock(t, bc, v, signTestTransaction(t, key, deployTx))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Deploy başarısız: %s", receipt.Error)
+	}
+	address := common.HexToAddress(receipt.ContractAddress)
+	addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{From: owner.Hex(), To: address.Hex(), GasLimit: 100000, Nonce: 1}))
+
+	// Deploy bloğunda slot 0 boş, çağrıdan sonra 1'dir
+	deployHeight := uint64(1)
+	if value, err := bc.GetStorageAt(address, common.Hash{}, &deployHeight); err != nil || value != (common.Hash{}) {
+		t.Errorf("Deploy bloğundaki storage hatalı: %s %v", value.Hex(), err)
+	}
+	if value, err := bc.GetStorageAt(address, common.Hash{}, nil); err != nil || value != common.BigToHash(big.NewInt(1)) {
+		t.Errorf("Son state'teki storage hatalı: %s %v", value.Hex(), err)
+	}
+
+	storage, err := bc.StorageRangeAt(address, common.Hash{}, 0, nil)
+	if err != nil || len(storage.Storage) != 1 || storage.NextSlot != nil {
+		t.Errorf("Storage aralığı hatalı: %+v %v", storage, err)
+	}
+
+	if code, err := bc.GetCodeAt(address, &deployHeight); err != nil || string(code) != string(runtime) {
+		t.Errorf("Kontrat kodu hatalı: %x %v", code, err)
+	}
+
+	missing := uint64(100)
+	if _, err := bc.GetCodeAt(address, &missing); err == nil {
+		t.Error("Olmayan blok için hata bekleniyordu")
+	}
+}
+
+// TestStateHistoryLimit son StateHistoryLimit bloğun state'inin blokların değişikliklerinden oluşturulduğunu test eder
+func TestStateHistoryLimit(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	key, owner := createTestAccount(t)
+	code := append(common.FromHex("6012600c60003960126000f3"), common.FromHex("6000546001018060005560005260206000f3")...)
+	receipt := addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{From: owner.Hex(), Data: code, GasLimit: 200000}))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Deploy başarısız: %s", receipt.Error)
+	}
+	address := common.HexToAddress(receipt.ContractAddress)
+
+	// Her çağrı bloğu sayacı bir artırır, h yüksekliğinde slot 0 h-1'dir
+	for nonce := uint64(1); nonce <= StateHistoryLimit; nonce++ {
+		addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{From: owner.Hex(), To: address.Hex(), GasLimit: 100000, Nonce: nonce}))
+	}
+	latest := uint64(StateHistoryLimit + 1)
+
+	// Yalnızca son bloklarda değişen hesaplar saklanır
+	if len(bc.stateChanges) != StateHistoryLimit-1 {
+		t.Errorf("Saklanan değişiklik sayısı hatalı: %d", len(bc.stateChanges))
+	}
+	for _, changes := range bc.stateChanges {
+		if len(changes) > 3 {
+			t.Errorf("Blok değişmeyen hesapları da saklıyor: %d hesap", len(changes))
+		}
+	}
+
+	for _, height := range []uint64{latest - StateHistoryLimit + 1, latest / 2, latest} {
+		value, err := bc.GetStorageAt(address, common.Hash{}, &height)
+		if err != nil || value != common.BigToHash(new(big.Int).SetUint64(height-1)) {
+			t.Errorf("%d yüksekliğindeki storage hatalı: %s %v", height, value.Hex(), err)
+		}
+	}
+	if value, err := bc.GetStorageAt(address, common.Hash{}, nil); err != nil || value != common.BigToHash(new(big.Int).SetUint64(latest-1)) {
+		t.Errorf("Son state'teki storage hatalı: %s %v", value.Hex(), err)
+	}
+
+	// Geçmiş state'in oluşturulması güncel state'i değiştirmemeli
+	if root := bc.GetStateRoot(); root != common.BytesToHash(bc.GetLatestBlock().Header.StateRoot) {
+		t.Errorf("Güncel state root'u değişti: %s", root.Hex())
+	}
+
+	pruned := latest - StateHistoryLimit
+	if _, err := bc.StateAt(&pruned); err == nil {
+		t.Error("Budanmış blok için hata bekleniyordu")
+	}
+}
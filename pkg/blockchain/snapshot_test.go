@@ -0,0 +1,231 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestSnapshotCreateAndRestore snapshot üretip yeni bir zincire yüklemeyi test eder
+func TestSnapshotCreateAndRestore(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+	bc.EnableSnapshots(SnapshotConfig{Interval: 2, Keep: 1})
+
+	// Checkpoint yüksekliğine kadar blok ekle; kontrat ilk blokta deploy edilir
+	key, owner := createTestAccount(t)
+	tx := signTestTransaction(t, key, &Transaction{From: owner.Hex(), Data: common.FromHex("600a600c600039600a6000f3602a60005260206000f3"), GasLimit: 200000})
+	receipt := addTransactionBlock(t, bc, v, tx)
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Deploy başarısız: %s", receipt.Error)
+	}
+	contractAddress := common.HexToAddress(receipt.ContractAddress)
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+
+	snapshots := bc.ListSnapshots()
+	if len(snapshots) != 1 || snapshots[0].Height != 2 {
+		t.Fatalf("Checkpoint yüksekliğinde snapshot üretilmedi: %+v", snapshots)
+	}
+	snapshot := snapshots[0]
+
+	chunks := make([][]byte, len(snapshot.ChunkHashes))
+	for i := range chunks {
+		chunks[i], err = bc.GetSnapshotChunk(snapshot.Height, i)
+		if err != nil {
+			t.Fatalf("Snapshot parçası alınamadı: %v", err)
+		}
+	}
+
+	// Bozulmuş parça reddedilmeli
+	tampered := append([]byte{}, chunks[0]...)
+	tampered[0] ^= 0xff
+	if err := VerifySnapshotChunk(snapshot, 0, tampered); err == nil {
+		t.Error("Bozulmuş snapshot parçası kabul edildi")
+	}
+
+	// Aynı genesis ile başlayan yeni zincir snapshot yüksekliğine kadar blokları çalıştırmadan ekler
+	fresh, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+	if err := fresh.BeginSnapshotSync(snapshot); err != nil {
+		t.Fatalf("Snapshot senkronizasyonu başlatılamadı: %v", err)
+	}
+	if err := fresh.RestoreSnapshot(snapshot, chunks); err == nil {
+		t.Error("Snapshot yüksekliğindeki blok olmadan state yüklendi")
+	}
+	if err := fresh.AddBlock(bc.Blocks[1]); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+	if _, exists := fresh.GetReceipt(receipt.TxHash); exists {
+		t.Error("Snapshot yüksekliğine kadar olan blok çalıştırıldı")
+	}
+
+	// Snapshot yüksekliğindeki blok snapshot ile eşleşmeli
+	other, err := createTestBlock(bc.Blocks[1].GetHash(), 2, v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := fresh.AddBlock(other); err == nil {
+		t.Error("Snapshot ile eşleşmeyen blok kabul edildi")
+	}
+	if err := fresh.AddBlock(bc.Blocks[2]); err != nil {
+		t.Fatalf("Snapshot ile eşleşen blok reddedildi: %v", err)
+	}
+
+	// Başlıktaki state root ile eşleşmeyen state reddedilir
+	forged, forgedChunks := forgeSnapshot(t, snapshot, &SnapshotState{Accounts: map[common.Address]*contracts.Account{owner: {Nonce: 7, Balance: big.NewInt(1)}}})
+	if err := fresh.RestoreSnapshot(forged, forgedChunks); err == nil {
+		t.Error("State root'u blokla eşleşmeyen snapshot yüklendi")
+	}
+
+	// State'e bağlı olmayan kontrat metadata'sı reddedilir
+	var state SnapshotState
+	if err := json.Unmarshal(bytes.Join(chunks, nil), &state); err != nil {
+		t.Fatalf("Snapshot state'i çözülemedi: %v", err)
+	}
+	var record *contracts.Contract
+	for _, contract := range state.Contracts {
+		if contract.Address == contractAddress {
+			record = contract
+		}
+	}
+	if record == nil {
+		t.Fatal("Kontrat snapshot'ta bulunamadı")
+	}
+	record.ABI = json.RawMessage(`[]`)
+	forged, forgedChunks = forgeSnapshot(t, snapshot, &state)
+	if err := fresh.RestoreSnapshot(forged, forgedChunks); err == nil {
+		t.Error("ABI'si değiştirilmiş kontrat kaydı yüklendi")
+	}
+
+	// Sahip ve etkinlik bilgisi peer'dan değil state'ten alınır
+	record.ABI = nil
+	record.Owner = common.HexToAddress("0xbad")
+	record.IsEnabled = false
+	forged, forgedChunks = forgeSnapshot(t, snapshot, &state)
+	if err := fresh.RestoreSnapshot(forged, forgedChunks); err != nil {
+		t.Fatalf("Snapshot yüklenemedi: %v", err)
+	}
+	restored, err := fresh.GetContract(contractAddress)
+	if err != nil {
+		t.Fatalf("Snapshot'taki kontrat yüklenmedi: %v", err)
+	}
+	if restored.Owner != owner || !restored.IsEnabled {
+		t.Errorf("Kontrat sahibi ve durumu state'ten alınmalı: %s %v", restored.Owner.Hex(), restored.IsEnabled)
+	}
+	if fresh.GetStateRoot() != bc.GetStateRoot() {
+		t.Error("Yüklenen state root kaynak zincirle aynı olmalı")
+	}
+
+	// Snapshot'tan sonraki bloklar çalıştırılır
+	tx = signTestTransaction(t, key, &Transaction{From: owner.Hex(), To: contractAddress.Hex(), GasLimit: 100000, Nonce: 1})
+	receipt = addTransactionBlock(t, bc, v, tx)
+	if err := fresh.AddBlock(bc.Blocks[3]); err != nil {
+		t.Fatalf("Snapshot sonrası blok eklenemedi: %v", err)
+	}
+	if _, exists := fresh.GetReceipt(receipt.TxHash); !exists {
+		t.Error("Snapshot sonrası blok çalıştırılmadı")
+	}
+}
+
+// TestSnapshotRestoresValidatorSet snapshot ile yüklenen zincirin validator kümesini state'ten kurmasını test eder
+func TestSnapshotRestoresValidatorSet(t *testing.T) {
+	v, key := createSigningValidator(t)
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+	bc.EnableSnapshots(SnapshotConfig{Interval: 2, Keep: 1})
+
+	// Tek validator'lı zincirde yeni validator tek oyla eklenir
+	candidate, _ := createSigningValidator(t)
+	receipt := addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{
+		From:     validatorAccount(v.Address).Hex(),
+		To:       contracts.ValidatorSetAddress.Hex(),
+		Data:     packSystemCall(t, contracts.ValidatorSetABI, "addValidator", candidate.PublicKeyBytes()),
+		GasLimit: 100000,
+	}))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Validator ekleme başarısız: %s", receipt.Error)
+	}
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+
+	snapshot := bc.ListSnapshots()[0]
+	chunks := make([][]byte, len(snapshot.ChunkHashes))
+	for i := range chunks {
+		chunks[i], err = bc.GetSnapshotChunk(snapshot.Height, i)
+		if err != nil {
+			t.Fatalf("Snapshot parçası alınamadı: %v", err)
+		}
+	}
+
+	fresh, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+	if err := fresh.BeginSnapshotSync(snapshot); err != nil {
+		t.Fatalf("Snapshot senkronizasyonu başlatılamadı: %v", err)
+	}
+	for _, block := range bc.Blocks[1:] {
+		if err := fresh.AddBlock(block); err != nil {
+			t.Fatalf("Blok eklenemedi: %v", err)
+		}
+	}
+	if fresh.GetValidator(candidate.Address) != nil {
+		t.Fatal("Snapshot öncesi bloklar validator kümesini değiştirmemeli")
+	}
+	if err := fresh.RestoreSnapshot(snapshot, chunks); err != nil {
+		t.Fatalf("Snapshot yüklenemedi: %v", err)
+	}
+
+	if fresh.GetValidator(candidate.Address) == nil {
+		t.Error("Zincir üzerinde eklenen validator snapshot'tan yüklenmedi")
+	}
+	if fresh.GetValidator(v.Address) != v {
+		t.Error("Yerel validator'ın imzalayabilen authority'si korunmalı")
+	}
+	if fresh.GetValidatorCount() != 2 {
+		t.Errorf("Beklenen validator sayısı 2, alınan: %d", fresh.GetValidatorCount())
+	}
+}
+
+// forgeSnapshot snapshot'ın verisini verilen state ile değiştirir
+func forgeSnapshot(t *testing.T, snapshot *Snapshot, state *SnapshotState) (*Snapshot, [][]byte) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("State kodlanamadı: %v", err)
+	}
+	hash := sha256.Sum256(data)
+	forged := *snapshot
+	forged.ChunkHashes = []string{hex.EncodeToString(hash[:])}
+	forged.StateHash = forged.ChunkHashes[0]
+	forged.Size = uint64(len(data))
+	return &forged, [][]byte{data}
+}
@@ -0,0 +1,43 @@
+package blockchain
+
+import (
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MaxStorageRangeLimit is the largest number of storage slots returned by StorageRangeAt
+const MaxStorageRangeLimit = 1024
+
+// GetStorageAt returns a storage slot of the account in the state after the block at the
+// given height (the current state if nil)
+func (bc *Blockchain) GetStorageAt(address common.Address, slot common.Hash, height *uint64) (common.Hash, error) {
+	state, err := bc.StateAt(height)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return state.GetStorage(address, slot), nil
+}
+
+// StorageRangeAt returns up to limit storage slots of the account starting at the given
+// slot in the state after the block at the given height (the current state if nil).
+// The limit is capped at MaxStorageRangeLimit.
+func (bc *Blockchain) StorageRangeAt(address common.Address, start common.Hash, limit int, height *uint64) (*contracts.StorageRange, error) {
+	state, err := bc.StateAt(height)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > MaxStorageRangeLimit {
+		limit = MaxStorageRangeLimit
+	}
+	return state.StorageRange(address, start, limit), nil
+}
+
+// GetCodeAt returns the code of the account in the state after the block at the given
+// height (the current state if nil)
+func (bc *Blockchain) GetCodeAt(address common.Address, height *uint64) ([]byte, error) {
+	state, err := bc.StateAt(height)
+	if err != nil {
+		return nil, err
+	}
+	return state.GetCode(address), nil
+}
@@ -0,0 +1,108 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+)
+
+// applyValidatorSetChangesLocked applies the validator set changes made through the
+// validator set system contract by the transactions of a block. Caller must hold the lock.
+func (bc *Blockchain) applyValidatorSetChangesLocked(receipts []*Receipt) {
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			change, ok := contracts.DecodeValidatorSetChange(log)
+			if !ok {
+				continue
+			}
+
+			if change.Added {
+				authority, err := validator.NewAuthorityFromPublicKey(change.PublicKey)
+				if err != nil {
+					fmt.Printf("Validator could not be added: %v\n", err)
+					continue
+				}
+				bc.addValidatorLocked(authority)
+				continue
+			}
+
+			for address := range bc.Validators {
+				if validatorAccount(address) == change.Account {
+					bc.removeValidatorLocked(address)
+				}
+			}
+		}
+	}
+}
+
+// stateValidatorsLocked returns the authorities of the validator set stored in the state.
+// Known validators keep their authority, so the local validator can still sign blocks.
+// Caller must hold the lock.
+func (bc *Blockchain) stateValidatorsLocked(world *contracts.WorldState) ([]*validator.Authority, error) {
+	known := make(map[string]*validator.Authority, len(bc.Validators))
+	for address, authority := range bc.Validators {
+		known[validatorAccount(address).Hex()] = authority
+	}
+
+	statedb := contracts.NewStateDB(world)
+	accounts := contracts.ValidatorAccounts(statedb)
+	if len(accounts) == 0 {
+		return nil, errors.New("state has no validators")
+	}
+	authorities := make([]*validator.Authority, 0, len(accounts))
+	for _, account := range accounts {
+		if authority, exists := known[account.Hex()]; exists {
+			authorities = append(authorities, authority)
+			continue
+		}
+
+		authority, err := validator.NewAuthorityFromPublicKey(contracts.ValidatorPublicKey(statedb, account))
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of validator %s: %v", account.Hex(), err)
+		}
+		if validatorAccount(authority.Address) != account {
+			return nil, fmt.Errorf("public key of validator %s does not match its account", account.Hex())
+		}
+		authorities = append(authorities, authority)
+	}
+	return authorities, nil
+}
+
+// replaceValidatorsLocked replaces the consensus set with the authorities. Caller must hold the lock.
+func (bc *Blockchain) replaceValidatorsLocked(authorities []*validator.Authority) {
+	for address := range bc.Validators {
+		bc.removeValidatorLocked(address)
+	}
+	for _, authority := range authorities {
+		bc.addValidatorLocked(authority)
+	}
+}
+
+// applyContractAccessChangesLocked updates the contract records with the management actions
+// applied through the contract access system contract by the transactions of a block and
+// emits them as CONTRACT_ACTION events. Caller must hold the lock.
+func (bc *Blockchain) applyContractAccessChangesLocked(receipts []*Receipt) {
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			change, ok := contracts.DecodeAccessChange(log)
+			if !ok {
+				continue
+			}
+			if err := bc.ContractManager.RecordAccessChange(change); err != nil {
+				fmt.Printf("Contract action could not be recorded: %v\n", err)
+			}
+
+			if bc.EventEmitter != nil {
+				bc.EventEmitter.Emit(EventContractAction, map[string]interface{}{
+					"address": change.Contract,
+					"action":  change.Action,
+					"account": change.Account,
+					"role":    change.Role,
+					"tx_hash": receipt.TxHash,
+				})
+			}
+		}
+	}
+}
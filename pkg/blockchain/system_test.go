@@ -0,0 +1,346 @@
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// packSystemCall bir sistem kontratı çağrısını ABI ile kodlar
+func packSystemCall(t *testing.T, definition, method string, args ...interface{}) []byte {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatalf("ABI çözülemedi: %v", err)
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		t.Fatalf("%s kodlanamadı: %v", method, err)
+	}
+	return data
+}
+
+// createSigningValidator işlem imzalayabilen secp256k1 anahtarlı bir validator oluşturur
+func createSigningValidator(t *testing.T) (*validator.Authority, *ecdsa.PrivateKey) {
+	key, _ := createTestAccount(t)
+	v, err := validator.NewAuthority(key)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	return v, key
+}
+
+// addTransactionBlock işlemi tek başına bir bloğa ekler ve receipt'ini döndürür
+func addTransactionBlock(t *testing.T, bc *Blockchain, v *validator.Authority, tx *Transaction) *Receipt {
+	if err := bc.SubmitTransaction(tx); err != nil {
+		t.Fatalf("İşlem gönderilemedi: %v", err)
+	}
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+	receipt, _ := bc.GetReceipt(hex.EncodeToString(tx.Hash))
+	if receipt == nil {
+		t.Fatal("Receipt bulunamadı")
+	}
+	return receipt
+}
+
+// TestSystemContractGovernance validator kümesi ve ücret parametrelerinin işlemlerle değiştirildiğini test eder
+func TestSystemContractGovernance(t *testing.T) {
+	v, key := createSigningValidator(t)
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	authority := validatorAccount(v.Address)
+	candidate, candidateKey := createSigningValidator(t)
+
+	// Authority adına imzasız ya da başka anahtarla imzalanmış işlemler reddedilir
+	forged := &Transaction{
+		From:     authority.Hex(),
+		To:       contracts.ValidatorSetAddress.Hex(),
+		Data:     packSystemCall(t, contracts.ValidatorSetABI, "addValidator", candidate.PublicKeyBytes()),
+		GasLimit: 100000,
+	}
+	if err := bc.SubmitTransaction(forged); !errors.Is(err, ErrUnsignedTransaction) {
+		t.Errorf("İmzasız işlem hatası bekleniyordu: %v", err)
+	}
+	otherKey, _ := createTestAccount(t)
+	if err := bc.SubmitTransaction(signTestTransaction(t, otherKey, forged)); !errors.Is(err, ErrInvalidSender) {
+		t.Errorf("Geçersiz gönderen hatası bekleniyordu: %v", err)
+	}
+
+	// Sahte işlem içeren blok da reddedilir
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	block.Transactions = append(block.Transactions, forged)
+	if err := bc.AddBlock(block); !errors.Is(err, ErrInvalidSender) {
+		t.Fatalf("Sahte işlem içeren blok kabul edildi: %v", err)
+	}
+	if bc.GetValidator(candidate.Address) != nil {
+		t.Fatal("Sahte işlem validator ekledi")
+	}
+
+	// Kendi anahtarıyla imzalayan ama validator olmayan hesap parametre değiştiremez
+	outsider := signTestTransaction(t, otherKey, &Transaction{
+		From:     crypto.PubkeyToAddress(otherKey.PublicKey).Hex(),
+		To:       contracts.FeeParamsAddress.Hex(),
+		Data:     packSystemCall(t, contracts.FeeParamsABI, "setFeeRecipient", common.HexToAddress("0xbeef")),
+		GasLimit: 100000,
+	})
+	if receipt := addTransactionBlock(t, bc, v, outsider); receipt.Status != TxFailed {
+		t.Fatal("Validator olmayan hesap ücret alıcısını değiştirdi")
+	}
+
+	// Validator ekleme işlemi blok eklendikten sonra uygulanır
+	receipt := addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{
+		From:     authority.Hex(),
+		To:       contracts.ValidatorSetAddress.Hex(),
+		Data:     packSystemCall(t, contracts.ValidatorSetABI, "addValidator", candidate.PublicKeyBytes()),
+		GasLimit: 100000,
+	}))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Validator ekleme başarısız: %s", receipt.Error)
+	}
+	if bc.GetValidator(candidate.Address) == nil {
+		t.Error("Yeni validator kümeye eklenmedi")
+	}
+
+	// İki validator'lı zincirde değişiklikler iki validator'ın da oyuyla uygulanır
+	setPrice := packSystemCall(t, contracts.FeeParamsABI, "setMinGasPrice", big.NewInt(5))
+	receipt = addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{
+		From:     authority.Hex(),
+		To:       contracts.FeeParamsAddress.Hex(),
+		Data:     setPrice,
+		GasLimit: 100000,
+		Nonce:    1,
+	}))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Oy verilemedi: %s", receipt.Error)
+	}
+	if price := contracts.MinGasPrice(contracts.NewStateDB(bc.ContractManager.State())); price.Sign() != 0 {
+		t.Fatalf("Minimum gas fiyatı tek oyla değişti: %s", price)
+	}
+	receipt = addTransactionBlock(t, bc, v, signTestTransaction(t, candidateKey, &Transaction{
+		From:     validatorAccount(candidate.Address).Hex(),
+		To:       contracts.FeeParamsAddress.Hex(),
+		Data:     setPrice,
+		GasLimit: 100000,
+	}))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Minimum gas fiyatı değiştirilemedi: %s", receipt.Error)
+	}
+
+	// Minimum gas fiyatının altındaki işlemler reddedilir
+	low := &Transaction{
+		From:     authority.Hex(),
+		To:       contracts.FeeParamsAddress.Hex(),
+		Data:     packSystemCall(t, contracts.FeeParamsABI, "minGasPrice"),
+		GasLimit: 100000,
+		GasPrice: 1,
+		Nonce:    2,
+	}
+	if err := bc.SubmitTransaction(signTestTransaction(t, key, low)); !errors.Is(err, contracts.ErrGasPriceTooLow) {
+		t.Errorf("Düşük gas fiyatı hatası bekleniyordu: %v", err)
+	}
+}
+
+// TestDeployerAllowlistEnforcement listede olmayan hesapların deploy'larının reddedildiğini test eder
+func TestDeployerAllowlistEnforcement(t *testing.T) {
+	v, key := createSigningValidator(t)
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	rejectedChan := bc.EventEmitter.Subscribe(EventContractDeployRejected)
+	defer bc.EventEmitter.Unsubscribe(EventContractDeployRejected, rejectedChan)
+
+	authority := validatorAccount(v.Address)
+	receipt := addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{
+		From:     authority.Hex(),
+		To:       contracts.DeployerAllowlistAddress.Hex(),
+		Data:     packSystemCall(t, contracts.DeployerAllowlistABI, "setEnabled", true),
+		GasLimit: 100000,
+	}))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Deployer listesi açılamadı: %s", receipt.Error)
+	}
+
+	// Listede olmayan hesabın hem API deploy'u hem de deploy işlemi reddedilir
+	ownerKey, owner := createTestAccount(t)
+	code := common.FromHex("6001600c60003960016000f300")
+	denied := &Transaction{From: owner.Hex(), Data: code, GasLimit: 100000, ContractName: "Denied", ContractVersion: "1.0"}
+	if _, err := bc.DeployContract(signTestTransaction(t, ownerKey, denied)); !errors.Is(err, contracts.ErrDeployerNotAllowed) {
+		t.Errorf("Deployer hatası bekleniyordu: %v", err)
+	}
+	tx := signTestTransaction(t, ownerKey, &Transaction{From: owner.Hex(), Data: code, GasLimit: 100000})
+	if err := bc.SubmitTransaction(tx); !errors.Is(err, contracts.ErrDeployerNotAllowed) {
+		t.Errorf("Deployer hatası bekleniyordu: %v", err)
+	}
+
+	select {
+	case event := <-rejectedChan:
+		if event.Data["owner"] != owner.Hex() {
+			t.Errorf("Red event'i hatalı: %v", event.Data)
+		}
+	case <-time.After(time.Second):
+		t.Error("CONTRACT_DEPLOY_REJECTED event'i alınmadı")
+	}
+
+	// Listeye eklenen hesap deploy edebilir
+	receipt = addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{
+		From:     authority.Hex(),
+		To:       contracts.DeployerAllowlistAddress.Hex(),
+		Data:     packSystemCall(t, contracts.DeployerAllowlistABI, "allow", owner),
+		GasLimit: 100000,
+		Nonce:    1,
+	}))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Hesap listeye eklenemedi: %s", receipt.Error)
+	}
+	if receipt := addTransactionBlock(t, bc, v, tx); receipt.Status != TxSuccess {
+		t.Errorf("Listedeki hesap deploy edemedi: %s", receipt.Error)
+	}
+
+	// Listedeki hesabın adına başka bir anahtarla deploy yapılamaz, ücreti de ona yazılamaz
+	intruderKey, _ := createTestAccount(t)
+	forged := &Transaction{From: owner.Hex(), Data: code, GasLimit: 100000, Nonce: 1, ContractName: "Forged", ContractVersion: "1.0"}
+	if _, err := bc.DeployContract(signTestTransaction(t, intruderKey, forged)); !errors.Is(err, ErrInvalidSender) {
+		t.Errorf("Geçersiz gönderen hatası bekleniyordu: %v", err)
+	}
+}
+
+// TestSecp256k1Authority secp256k1 anahtarlı bir authority'nin aynı hesapla blok ürettiğini ve işlem imzaladığını test eder
+func TestSecp256k1Authority(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	v, err := validator.NewAuthority(key)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	if v.Address != account.Hex() || v.Account() != account {
+		t.Fatalf("Validator adresi Ethereum adresi değil: %s", v.Address)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	// Authority kendi Ethereum anahtarıyla sistem kontratını çağırır
+	candidate, _ := crypto.GenerateKey()
+	signed, err := types.SignNewTx(key, types.LatestSignerForChainID(bc.ChainConfig().ChainID), &types.DynamicFeeTx{
+		ChainID: bc.ChainConfig().ChainID,
+		Gas:     100000,
+		To:      &contracts.ValidatorSetAddress,
+		Data:    packSystemCall(t, contracts.ValidatorSetABI, "addValidator", crypto.FromECDSAPub(&candidate.PublicKey)),
+	})
+	if err != nil {
+		t.Fatalf("İşlem imzalanamadı: %v", err)
+	}
+	raw, _ := signed.MarshalBinary()
+	tx, err := bc.SendRawTransaction(raw)
+	if err != nil {
+		t.Fatalf("İşlem gönderilemedi: %v", err)
+	}
+
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if !block.Verify(v) || block.Header.Coinbase() != account {
+		t.Fatal("secp256k1 imzalı blok doğrulanamadı")
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+	receipt, _ := bc.GetReceipt(receiptHash(tx))
+	if receipt == nil || receipt.Status != TxSuccess {
+		t.Fatalf("Validator ekleme başarısız: %+v", receipt)
+	}
+
+	added := bc.GetValidator(crypto.PubkeyToAddress(candidate.PublicKey).Hex())
+	if added == nil || added.KeyType() != validator.KeyTypeSecp256k1 {
+		t.Error("secp256k1 validator kümeye eklenmedi")
+	}
+}
+
+// TestContractAccessTransactions yönetim işlemlerinin bloklarda çalıştığını ve kaydı güncellediğini test eder
+func TestContractAccessTransactions(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	actionChan := bc.EventEmitter.Subscribe(EventContractAction)
+	defer bc.EventEmitter.Unsubscribe(EventContractAction, actionChan)
+
+	key, owner := createTestAccount(t)
+	otherKey, other := createTestAccount(t)
+	code := append(common.FromHex("6012600c60003960126000f3"), common.FromHex("6000546001018060005560005260206000f3")...)
+	receipt := addTransactionBlock(t, bc, v, signTestTransaction(t, key, &Transaction{From: owner.Hex(), Data: code, GasLimit: 200000}))
+	if receipt.Status != TxSuccess {
+		t.Fatalf("Deploy başarısız: %s", receipt.Error)
+	}
+	address := common.HexToAddress(receipt.ContractAddress)
+
+	disable := func(key *ecdsa.PrivateKey, from common.Address) *Receipt {
+		data, err := contracts.PackContractAction(contracts.ActionDisable, address, common.Address{}, "")
+		if err != nil {
+			t.Fatalf("İşlem kodlanamadı: %v", err)
+		}
+		tx := &Transaction{From: from.Hex(), To: contracts.ContractAccessAddress.Hex(), Data: data, GasLimit: 100000, Nonce: bc.GetNonce(from.Hex())}
+		return addTransactionBlock(t, bc, v, signTestTransaction(t, key, tx))
+	}
+
+	// Sahibi olmayan hesabın işlemi bloğa girer ama başarısız olur
+	if receipt := disable(otherKey, other); receipt.Status != TxFailed {
+		t.Fatal("Sahibi olmayan hesap kontratı devre dışı bıraktı")
+	}
+	if receipt := disable(key, owner); receipt.Status != TxSuccess {
+		t.Fatalf("Kontrat devre dışı bırakılamadı: %s", receipt.Error)
+	}
+
+	contract, _ := bc.GetContract(address)
+	if contract.IsEnabled {
+		t.Error("Kontrat kaydı güncellenmedi")
+	}
+	call := signTestTransaction(t, key, &Transaction{From: owner.Hex(), To: address.Hex(), GasLimit: 100000, Nonce: bc.GetNonce(owner.Hex())})
+	if receipt := addTransactionBlock(t, bc, v, call); receipt.Status != TxFailed || receipt.Error != contracts.ErrContractDisabled.Error() {
+		t.Errorf("Devre dışı kontrat çağrıldı: %+v", receipt)
+	}
+
+	select {
+	case event := <-actionChan:
+		if event.Data["action"] != contracts.ActionDisable || event.Data["address"] != address {
+			t.Errorf("CONTRACT_ACTION event'i hatalı: %v", event.Data)
+		}
+	case <-time.After(time.Second):
+		t.Error("CONTRACT_ACTION event'i alınmadı")
+	}
+}
@@ -0,0 +1,60 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+)
+
+// TraceTransaction re-executes an included contract transaction with the tracer selected
+// by the config and returns its trace. The transactions before it in the block are replayed
+// on the state of the previous block, which must still be in the state history.
+func (bc *Blockchain) TraceTransaction(hash string, config *contracts.TraceConfig) (interface{}, error) {
+	receipt, exists := bc.GetReceipt(hash)
+	if !exists {
+		return nil, errors.New("transaction not found")
+	}
+	// Ücret yalnızca EVM'e ulaşan işlemlerin receipt'ine yazılır
+	if receipt.Fee == "" {
+		return nil, errors.New("transaction did not execute contract code")
+	}
+
+	block := bc.GetBlockByHeight(receipt.BlockHeight)
+	if block == nil || receipt.BlockHeight == 0 {
+		return nil, fmt.Errorf("block %d not found", receipt.BlockHeight)
+	}
+
+	bc.mu.RLock()
+	parent, err := bc.stateAtLocked(receipt.BlockHeight - 1)
+	context := bc.blockContextLocked(block.Header)
+	bc.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Bloktaki önceki işlemler aynı sırayla yeniden uygulanır
+	statedb := contracts.NewStateDB(parent)
+	for _, tx := range block.Transactions[:receipt.TransactionIndex] {
+		if previous, exists := bc.GetReceipt(hex.EncodeToString(tx.Hash)); !exists || previous.Fee == "" {
+			continue
+		}
+		bc.ContractManager.ApplyMessage(statedb, contractMessage(block, tx, context))
+		statedb.Finalise()
+	}
+
+	tx := block.Transactions[receipt.TransactionIndex]
+	_, trace, err := bc.ContractManager.TraceMessage(statedb, contractMessage(block, tx, context), config)
+	return trace, err
+}
+
+// TraceCall simulates a deployment or call against the state at the given height (the
+// current state if nil) and returns its trace
+func (bc *Blockchain) TraceCall(msg *contracts.Message, height *uint64, config *contracts.TraceConfig) (*contracts.ExecutionResult, interface{}, error) {
+	state, msg, err := bc.simulationAt(msg, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bc.ContractManager.Trace(state, msg, config)
+}
@@ -0,0 +1,80 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestTraceTransaction bloktaki işlemin önceki işlemler yeniden uygulanarak izlendiğini test eder
+func TestTraceTransaction(t *testing.T) {
+	v, err := createTestValidator(t)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	bc, err := NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	key, owner := createTestAccount(t)
+	code := append(common.FromHex("6012600c60003960126000f3"), common.FromHex("6000546001018060005560005260206000f3")...)
+	addBlock := func(txs ...*Transaction) {
+		for _, tx := range txs {
+			if err := bc.SubmitTransaction(signTestTransaction(t, key, tx)); err != nil {
+				t.Fatalf("İşlem gönderilemedi: %v", err)
+			}
+		}
+		block, err := bc.CreateBlock(v)
+		if err != nil {
+			t.Fatalf("Blok oluşturulamadı: %v", err)
+		}
+		if err := bc.AddBlock(block); err != nil {
+			t.Fatalf("Blok eklenemedi: %v", err)
+		}
+	}
+
+	deployTx := &Transaction{From: owner.Hex(), Data: code, GasLimit: 200000}
+	addBlock(deployTx)
+	receipt, _ := bc.GetReceipt(hex.EncodeToString(deployTx.Hash))
+	if receipt == nil || receipt.Status != TxSuccess {
+		t.Fatal("Deploy başarısız")
+	}
+	address := common.HexToAddress(receipt.ContractAddress)
+
+	// Aynı blokta iki çağrı; ikincisi birincinin yazdığı değeri okur
+	first := &Transaction{From: owner.Hex(), To: address.Hex(), GasLimit: 100000, Nonce: 1}
+	second := &Transaction{From: owner.Hex(), To: address.Hex(), GasLimit: 100000, Nonce: 2}
+	addBlock(first, second)
+	hash := hex.EncodeToString(second.Hash)
+	receipt, _ = bc.GetReceipt(hash)
+	if receipt == nil || receipt.TransactionIndex != 1 {
+		t.Fatalf("İkinci çağrı bloğa eklenmedi: %+v", receipt)
+	}
+
+	trace, err := bc.TraceTransaction(hash, nil)
+	if err != nil {
+		t.Fatalf("İşlem izlenemedi: %v", err)
+	}
+	logs := trace.(*contracts.StructLogResult)
+	if logs.ReturnValue != receipt.ReturnData || logs.Gas != receipt.GasUsed {
+		t.Errorf("İzleme sonucu receipt ile uyuşmuyor: %+v", logs)
+	}
+
+	trace, err = bc.TraceTransaction(hash, &contracts.TraceConfig{Tracer: contracts.TracerPrestate})
+	if err != nil {
+		t.Fatalf("İşlem izlenemedi: %v", err)
+	}
+	account := trace.(map[common.Address]*contracts.PrestateAccount)[address]
+	if account == nil || account.Storage[common.Hash{}] != common.BigToHash(big.NewInt(1)) {
+		t.Errorf("Önceki işlemin yazdığı değer prestate'te olmalı: %+v", account)
+	}
+
+	if _, err := bc.TraceTransaction("00", nil); err == nil {
+		t.Error("Olmayan işlem için hata bekleniyordu")
+	}
+}
@@ -1,7 +1,15 @@
 package blockchain
 
 import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/json"
 	"math/big"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // Transaction represents a blockchain transaction
@@ -17,6 +25,16 @@ type Transaction struct {
 	Nonce     uint64
 	Signature []byte
 	Status    TxStatus
+
+	// Kontrat deploy işlemleri için kayıt bilgileri
+	ContractName    string `json:",omitempty"`
+	ContractVersion string `json:",omitempty"`
+	Salt            []byte `json:",omitempty"` // CREATE2 salt
+	ContractABI     string `json:",omitempty"` // ABI JSON stored with the contract
+	Implementation  string `json:",omitempty"` // Creates or upgrades a proxy to this implementation
+
+	// Ethereum işlemleri için imzalı orijinal kodlama (eth_sendRawTransaction)
+	RawTransaction []byte `json:",omitempty"` // Signed Ethereum transaction the fields were decoded from
 }
 
 // TxStatus represents the status of a transaction
@@ -28,6 +46,71 @@ const (
 	TxFailed
 )
 
+// IsContractCreation reports whether the transaction deploys a contract
+func (tx *Transaction) IsContractCreation() bool {
+	return tx.To == ""
+}
+
+// IsProxyOperation reports whether the transaction creates or upgrades a proxy
+func (tx *Transaction) IsProxyOperation() bool {
+	return tx.Implementation != ""
+}
+
+// IsEthereumTransaction reports whether the transaction is a signed Ethereum transaction
+func (tx *Transaction) IsEthereumTransaction() bool {
+	return len(tx.RawTransaction) > 0
+}
+
+// RequiresSignature reports whether the transaction can reach the world state: it deploys a
+// contract, creates or upgrades a proxy, or is sent to an account. Such transactions must be
+// signed by their sender; signed Ethereum transactions carry their own signature.
+func (tx *Transaction) RequiresSignature() bool {
+	return tx.IsContractCreation() || tx.IsProxyOperation() || common.IsHexAddress(tx.To)
+}
+
+// SigningHash returns the hash the sender signs: the transaction hash with the Ethereum
+// signed message prefix, as produced by personal_sign over the hash
+func (tx *Transaction) SigningHash() []byte {
+	return accounts.TextHash(tx.CalculateHash())
+}
+
+// Sign sets the hash of the transaction and signs it with the sender's secp256k1 key
+func (tx *Transaction) Sign(key *ecdsa.PrivateKey) error {
+	signature, err := crypto.Sign(tx.SigningHash(), key)
+	if err != nil {
+		return err
+	}
+	tx.Hash = tx.CalculateHash()
+	tx.Signature = signature
+	return nil
+}
+
+// Sender recovers the account that signed the transaction
+func (tx *Transaction) Sender() (common.Address, error) {
+	return contracts.RecoverSigner(tx.SigningHash(), tx.Signature)
+}
+
+// CalculateHash calculates the transaction hash over its signed fields
+func (tx *Transaction) CalculateHash() []byte {
+	data, _ := json.Marshal(struct {
+		From            string
+		To              string
+		Value           *big.Int
+		Data            []byte
+		GasPrice        uint64
+		GasLimit        uint64
+		Nonce           uint64
+		ContractName    string
+		ContractVersion string
+		Salt            []byte
+		ContractABI     string `json:",omitempty"`
+		Implementation  string `json:",omitempty"`
+	}{tx.From, tx.To, bigOrZero(tx.Value), tx.Data, tx.GasPrice, tx.GasLimit, tx.Nonce, tx.ContractName, tx.ContractVersion, tx.Salt, tx.ContractABI, tx.Implementation})
+
+	hash := sha256.Sum256(data)
+	return hash[:]
+}
+
 // GetSize işlemin yaklaşık boyutunu hesaplar
 func (tx *Transaction) GetSize() uint64 {
 	size := uint64(0)
@@ -43,6 +126,7 @@ func (tx *Transaction) GetSize() uint64 {
 	size += 8                          // Nonce
 	size += uint64(len(tx.Signature))  // İmza boyutu
 	size += uint64(len(tx.Data))       // Data boyutu
+	size += uint64(len(tx.RawTransaction)) // İmzalı Ethereum işlemi
 	
 	return size
 } 
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
     "net/http"
     "sync"
 
+    "github.com/ethereum/go-ethereum/core/types"
     "github.com/gorilla/websocket"
 )
 
@@ -49,9 +50,39 @@ func (s *WebSocketServer) HandleWebSocket(w http.ResponseWriter, r *http.Request
     eventChan := s.blockchain.EventEmitter.Subscribe(EventContractDeployStarted)
     successChan := s.blockchain.EventEmitter.Subscribe(EventContractDeploySuccess)
     failedChan := s.blockchain.EventEmitter.Subscribe(EventContractDeployFailed)
+    rejectedChan := s.blockchain.EventEmitter.Subscribe(EventContractDeployRejected)
     verifiedChan := s.blockchain.EventEmitter.Subscribe(EventContractVerified)
+    actionChan := s.blockchain.EventEmitter.Subscribe(EventContractAction)
+    logChan := s.blockchain.EventEmitter.Subscribe(EventContractLog)
+
+    // Client log filtresini değiştirebilir, filtre yoksa tüm log'lar gönderilir
+    var filterLock sync.RWMutex
+    filter := &LogFilter{}
+    done := make(chan struct{})
 
     // Handle client messages
+    go func() {
+        defer close(done)
+        for {
+            _, message, err := conn.ReadMessage()
+            if err != nil {
+                return
+            }
+
+            var request struct {
+                Type   string    `json:"type"`
+                Filter LogFilter `json:"filter"`
+            }
+            if err := json.Unmarshal(message, &request); err != nil || request.Type != "subscribe_logs" {
+                continue
+            }
+            filterLock.Lock()
+            filter = &request.Filter
+            filterLock.Unlock()
+        }
+    }()
+
+    // Forward events to the client
     go func() {
         defer func() {
             conn.Close()
@@ -63,19 +94,36 @@ func (s *WebSocketServer) HandleWebSocket(w http.ResponseWriter, r *http.Request
             s.blockchain.EventEmitter.Unsubscribe(EventContractDeployStarted, eventChan)
             s.blockchain.EventEmitter.Unsubscribe(EventContractDeploySuccess, successChan)
             s.blockchain.EventEmitter.Unsubscribe(EventContractDeployFailed, failedChan)
+            s.blockchain.EventEmitter.Unsubscribe(EventContractDeployRejected, rejectedChan)
             s.blockchain.EventEmitter.Unsubscribe(EventContractVerified, verifiedChan)
+            s.blockchain.EventEmitter.Unsubscribe(EventContractAction, actionChan)
+            s.blockchain.EventEmitter.Unsubscribe(EventContractLog, logChan)
         }()
 
         for {
             select {
+            case <-done:
+                return
             case event := <-eventChan:
                 s.broadcastEvent(conn, event)
             case event := <-successChan:
                 s.broadcastEvent(conn, event)
             case event := <-failedChan:
                 s.broadcastEvent(conn, event)
+            case event := <-rejectedChan:
+                s.broadcastEvent(conn, event)
             case event := <-verifiedChan:
                 s.broadcastEvent(conn, event)
+            case event := <-actionChan:
+                s.broadcastEvent(conn, event)
+            case event := <-logChan:
+                log, ok := event.Data["log"].(*types.Log)
+                filterLock.RLock()
+                matches := ok && filter.Matches(log)
+                filterLock.RUnlock()
+                if matches {
+                    s.broadcastEvent(conn, event)
+                }
             }
         }
     }()
@@ -0,0 +1,349 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNoABI is returned when an ABI operation is requested for a contract deployed without an ABI
+var ErrNoABI = errors.New("contract has no ABI")
+
+// ParseABI parses and validates a contract ABI JSON definition
+func ParseABI(data []byte) (*abi.ABI, error) {
+	parsed, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI: %v", err)
+	}
+	return &parsed, nil
+}
+
+// ParsedABI returns the parsed ABI the contract was deployed with, or the ABI of its
+// verified source if it was deployed without one
+func (c *Contract) ParsedABI() (*abi.ABI, error) {
+	if len(c.ABI) == 0 {
+		if c.Source == nil || len(c.Source.ABI) == 0 {
+			return nil, ErrNoABI
+		}
+		return ParseABI(c.Source.ABI)
+	}
+	return ParseABI(c.ABI)
+}
+
+// EncodeCall encodes a method call with JSON arguments into calldata
+func EncodeCall(contractABI *abi.ABI, method string, args []json.RawMessage) ([]byte, error) {
+	m, exists := contractABI.Methods[method]
+	if !exists {
+		return nil, fmt.Errorf("method %s not found in ABI", method)
+	}
+
+	packed, err := packArguments(m.Inputs, args)
+	if err != nil {
+		return nil, fmt.Errorf("method %s: %v", method, err)
+	}
+	return append(common.CopyBytes(m.ID), packed...), nil
+}
+
+// EncodeConstructor encodes JSON constructor arguments, appended to the init code on deploy
+func EncodeConstructor(contractABI *abi.ABI, args []json.RawMessage) ([]byte, error) {
+	packed, err := packArguments(contractABI.Constructor.Inputs, args)
+	if err != nil {
+		return nil, fmt.Errorf("constructor: %v", err)
+	}
+	return packed, nil
+}
+
+// DecodeOutput decodes the return data of a method into JSON friendly values
+func DecodeOutput(contractABI *abi.ABI, method string, data []byte) ([]interface{}, error) {
+	m, exists := contractABI.Methods[method]
+	if !exists {
+		return nil, fmt.Errorf("method %s not found in ABI", method)
+	}
+
+	values, err := m.Outputs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s output: %v", method, err)
+	}
+
+	outputs := make([]interface{}, len(values))
+	for i, value := range values {
+		outputs[i] = formatValue(m.Outputs[i].Type, value)
+	}
+	return outputs, nil
+}
+
+// DecodeLog decodes a log emitted by the contract into the event name and its arguments
+func DecodeLog(contractABI *abi.ABI, log *types.Log) (string, map[string]interface{}, error) {
+	if len(log.Topics) == 0 {
+		return "", nil, errors.New("anonymous logs cannot be decoded")
+	}
+	event, err := contractABI.EventByID(log.Topics[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	values := make(map[string]interface{})
+	if len(log.Data) > 0 {
+		if err := event.Inputs.NonIndexed().UnpackIntoMap(values, log.Data); err != nil {
+			return "", nil, fmt.Errorf("failed to decode %s data: %v", event.Name, err)
+		}
+	}
+
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return "", nil, fmt.Errorf("failed to decode %s topics: %v", event.Name, err)
+	}
+
+	args := make(map[string]interface{}, len(values))
+	for _, input := range event.Inputs {
+		if value, exists := values[input.Name]; exists {
+			args[input.Name] = formatValue(input.Type, value)
+		}
+	}
+	return event.Name, args, nil
+}
+
+// packArguments converts JSON arguments to the Go types of the ABI arguments and packs them
+func packArguments(arguments abi.Arguments, args []json.RawMessage) ([]byte, error) {
+	if len(args) != len(arguments) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(arguments), len(args))
+	}
+
+	values := make([]interface{}, len(args))
+	for i, argument := range arguments {
+		value, err := convertArgument(argument.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %v", i, argument.Type.String(), err)
+		}
+		values[i] = value
+	}
+	return arguments.Pack(values...)
+}
+
+// convertArgument converts a JSON value to the Go type the abi package expects for the type.
+// Integers may be given as JSON numbers or decimal/0x-prefixed strings, bytes as hex strings,
+// tuples as objects keyed by component name or as arrays.
+func convertArgument(t abi.Type, raw json.RawMessage) (interface{}, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		return convertInteger(t, raw)
+
+	case abi.BoolTy:
+		var value bool
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, errors.New("expected a boolean")
+		}
+		return value, nil
+
+	case abi.StringTy:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, errors.New("expected a string")
+		}
+		return value, nil
+
+	case abi.AddressTy:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil || !common.IsHexAddress(value) {
+			return nil, errors.New("expected an address")
+		}
+		return common.HexToAddress(value), nil
+
+	case abi.BytesTy:
+		return decodeHexArgument(raw)
+
+	case abi.FixedBytesTy:
+		data, err := decodeHexArgument(raw)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) != t.Size {
+			return nil, fmt.Errorf("expected %d bytes, got %d", t.Size, len(data))
+		}
+		value := reflect.New(t.GetType()).Elem()
+		reflect.Copy(value, reflect.ValueOf(data))
+		return value.Interface(), nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		var elements []json.RawMessage
+		if err := json.Unmarshal(raw, &elements); err != nil {
+			return nil, errors.New("expected an array")
+		}
+
+		var value reflect.Value
+		if t.T == abi.ArrayTy {
+			if len(elements) != t.Size {
+				return nil, fmt.Errorf("expected %d elements, got %d", t.Size, len(elements))
+			}
+			value = reflect.New(t.GetType()).Elem()
+		} else {
+			value = reflect.MakeSlice(t.GetType(), len(elements), len(elements))
+		}
+		for i, element := range elements {
+			converted, err := convertArgument(*t.Elem, element)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %v", i, err)
+			}
+			value.Index(i).Set(reflect.ValueOf(converted))
+		}
+		return value.Interface(), nil
+
+	case abi.TupleTy:
+		components, err := tupleComponents(t, raw)
+		if err != nil {
+			return nil, err
+		}
+
+		value := reflect.New(t.GetType()).Elem()
+		for i, elem := range t.TupleElems {
+			converted, err := convertArgument(*elem, components[i])
+			if err != nil {
+				return nil, fmt.Errorf("component %s: %v", t.TupleRawNames[i], err)
+			}
+			value.Field(i).Set(reflect.ValueOf(converted))
+		}
+		return value.Interface(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t.String())
+}
+
+// convertInteger converts a JSON number or string to the integer type of the given size
+func convertInteger(t abi.Type, raw json.RawMessage) (interface{}, error) {
+	text := strings.Trim(string(raw), `"`)
+	value, ok := new(big.Int), false
+	if strings.HasPrefix(text, "0x") {
+		value, ok = value.SetString(text[2:], 16)
+	} else {
+		value, ok = value.SetString(text, 10)
+	}
+	if !ok {
+		return nil, errors.New("expected an integer")
+	}
+
+	if t.T == abi.UintTy && value.Sign() < 0 {
+		return nil, errors.New("negative value for unsigned integer")
+	}
+	bits := value.BitLen()
+	if t.T == abi.IntTy {
+		// İşaret biti; en küçük negatif değer (-2^(n-1)) n bit'e sığar
+		if value.Sign() < 0 {
+			bits = new(big.Int).Add(value, big.NewInt(1)).BitLen()
+		}
+		bits++
+	}
+	if bits > t.Size {
+		return nil, fmt.Errorf("value does not fit in %s", t.String())
+	}
+
+	// 64 bit'e kadar olan tipler Go'nun sabit boyutlu tamsayılarıyla paketlenir
+	if t.Size > 64 {
+		return value, nil
+	}
+	converted := reflect.New(t.GetType()).Elem()
+	if t.T == abi.UintTy {
+		converted.SetUint(value.Uint64())
+	} else {
+		converted.SetInt(value.Int64())
+	}
+	return converted.Interface(), nil
+}
+
+// tupleComponents returns the JSON values of the tuple components, given as an object or array
+func tupleComponents(t abi.Type, raw json.RawMessage) ([]json.RawMessage, error) {
+	var components []json.RawMessage
+	if err := json.Unmarshal(raw, &components); err == nil {
+		if len(components) != len(t.TupleElems) {
+			return nil, fmt.Errorf("expected %d components, got %d", len(t.TupleElems), len(components))
+		}
+		return components, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, errors.New("expected an object or array")
+	}
+	components = make([]json.RawMessage, len(t.TupleElems))
+	for i, name := range t.TupleRawNames {
+		value, exists := fields[name]
+		if !exists {
+			return nil, fmt.Errorf("missing component %s", name)
+		}
+		components[i] = value
+	}
+	return components, nil
+}
+
+// decodeHexArgument decodes a hex string argument, the 0x prefix is optional
+func decodeHexArgument(raw json.RawMessage) ([]byte, error) {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, errors.New("expected a hex string")
+	}
+	if !strings.HasPrefix(value, "0x") {
+		value = "0x" + value
+	}
+	data, err := hexutil.Decode(value)
+	if err != nil && value != "0x" {
+		return nil, fmt.Errorf("invalid hex: %v", err)
+	}
+	return data, nil
+}
+
+// formatValue converts a decoded ABI value to a JSON friendly value: integers become
+// decimal strings, bytes and addresses hex strings and tuples objects keyed by component name
+func formatValue(t abi.Type, value interface{}) interface{} {
+	// Indexed dinamik tipler topic'te yalnızca hash olarak bulunur
+	if hash, ok := value.(common.Hash); ok && t.T != abi.FixedBytesTy {
+		return hash.Hex()
+	}
+
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		return fmt.Sprint(value)
+
+	case abi.AddressTy:
+		return value.(common.Address).Hex()
+
+	case abi.BytesTy:
+		return hexutil.Encode(value.([]byte))
+
+	case abi.FixedBytesTy:
+		array := reflect.ValueOf(value)
+		data := make([]byte, array.Len())
+		reflect.Copy(reflect.ValueOf(data), array)
+		return hexutil.Encode(data)
+
+	case abi.SliceTy, abi.ArrayTy:
+		list := reflect.ValueOf(value)
+		elements := make([]interface{}, list.Len())
+		for i := range elements {
+			elements[i] = formatValue(*t.Elem, list.Index(i).Interface())
+		}
+		return elements
+
+	case abi.TupleTy:
+		tuple := reflect.ValueOf(value)
+		fields := make(map[string]interface{}, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			fields[t.TupleRawNames[i]] = formatValue(*elem, tuple.Field(i).Interface())
+		}
+		return fields
+	}
+
+	return value
+}
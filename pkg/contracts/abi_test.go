@@ -0,0 +1,106 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"mixed","inputs":[
+		{"name":"small","type":"uint8"},
+		{"name":"signed","type":"int16"},
+		{"name":"id","type":"bytes32"},
+		{"name":"label","type":"string"},
+		{"name":"values","type":"uint256[]"},
+		{"name":"pair","type":"tuple","components":[{"name":"a","type":"uint64"},{"name":"b","type":"address"}]}
+	],"outputs":[]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+// rawArgs JSON argüman listesini ayrıştırır
+func rawArgs(t *testing.T, data string) []json.RawMessage {
+	var args []json.RawMessage
+	if err := json.Unmarshal([]byte(data), &args); err != nil {
+		t.Fatalf("Argümanlar ayrıştırılamadı: %v", err)
+	}
+	return args
+}
+
+// TestEncodeCall JSON argümanların abi paketiyle aynı şekilde kodlandığını test eder
+func TestEncodeCall(t *testing.T) {
+	contractABI, err := ParseABI([]byte(testABI))
+	if err != nil {
+		t.Fatalf("ABI ayrıştırılamadı: %v", err)
+	}
+
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	input, err := EncodeCall(contractABI, "transfer", rawArgs(t, `["0x00000000000000000000000000000000000000aa", "1000000000000000000000"]`))
+	if err != nil {
+		t.Fatalf("Çağrı kodlanamadı: %v", err)
+	}
+	amount, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	expected, _ := contractABI.Pack("transfer", to, amount)
+	if !bytes.Equal(input, expected) {
+		t.Errorf("Kodlanan çağrı hatalı:\n%x\n%x", input, expected)
+	}
+
+	_, err = EncodeCall(contractABI, "mixed", rawArgs(t, `[255, -32768, "0x`+common.Bytes2Hex(common.LeftPadBytes([]byte{1}, 32))+`", "hello", [1, "0x02"], {"a": 7, "b": "0x00000000000000000000000000000000000000bb"}]`))
+	if err != nil {
+		t.Errorf("Karışık tipler kodlanamadı: %v", err)
+	}
+
+	invalid := []string{
+		`[256, 0, "0x00", "", [], [0, "0x00000000000000000000000000000000000000bb"]]`,
+		`[1, 32768, "0x00", "", [], [0, "0x00000000000000000000000000000000000000bb"]]`,
+	}
+	for _, args := range invalid {
+		if _, err := EncodeCall(contractABI, "mixed", rawArgs(t, args)); err == nil {
+			t.Errorf("Sınır dışı argüman kabul edildi: %s", args)
+		}
+	}
+	if _, err := EncodeCall(contractABI, "missing", nil); err == nil {
+		t.Error("ABI'de olmayan metot kabul edildi")
+	}
+}
+
+// TestDecodeOutputAndLog dönüş değerlerinin ve event'lerin JSON'a çözüldüğünü test eder
+func TestDecodeOutputAndLog(t *testing.T) {
+	contractABI, err := ParseABI([]byte(testABI))
+	if err != nil {
+		t.Fatalf("ABI ayrıştırılamadı: %v", err)
+	}
+
+	outputs, err := DecodeOutput(contractABI, "transfer", common.LeftPadBytes([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("Çıktı çözülemedi: %v", err)
+	}
+	if len(outputs) != 1 || outputs[0] != true {
+		t.Errorf("Çıktı hatalı: %v", outputs)
+	}
+
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	log := &types.Log{
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data: common.BigToHash(big.NewInt(42)).Bytes(),
+	}
+
+	name, args, err := DecodeLog(contractABI, log)
+	if err != nil {
+		t.Fatalf("Log çözülemedi: %v", err)
+	}
+	if name != "Transfer" || args["from"] != from.Hex() || args["to"] != to.Hex() || args["value"] != "42" {
+		t.Errorf("Event hatalı: %s %v", name, args)
+	}
+}
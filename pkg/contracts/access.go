@@ -0,0 +1,316 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Role is an access role granted on a contract
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"    // Grants and revokes operators, enables and disables the contract
+	RoleOperator Role = "operator" // Enables and disables the contract
+)
+
+// Contract management actions
+const (
+	ActionDisable           = "disable"
+	ActionEnable            = "enable"
+	ActionTransferOwnership = "transfer_ownership"
+	ActionGrantRole         = "grant_role"
+	ActionRevokeRole        = "revoke_role"
+)
+
+// ContractAccessAddress is the address of the system contract holding the owner, the
+// enabled state and the roles of the contracts
+var ContractAccessAddress = common.HexToAddress("0x0000000000000000000000000000000000001004")
+
+// ContractAccessABI is the ABI of the contract access system contract. Contracts are owned by
+// the account that deployed them; management actions are transactions sent to it by the
+// owner or a role holder.
+const ContractAccessABI = `[
+	{"type":"function","name":"owner","stateMutability":"view","inputs":[{"name":"target","type":"address"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"enabled","stateMutability":"view","inputs":[{"name":"target","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"roleOf","stateMutability":"view","inputs":[{"name":"target","type":"address"},{"name":"account","type":"address"}],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"disable","stateMutability":"nonpayable","inputs":[{"name":"target","type":"address"}],"outputs":[]},
+	{"type":"function","name":"enable","stateMutability":"nonpayable","inputs":[{"name":"target","type":"address"}],"outputs":[]},
+	{"type":"function","name":"transferOwnership","stateMutability":"nonpayable","inputs":[{"name":"target","type":"address"},{"name":"account","type":"address"}],"outputs":[]},
+	{"type":"function","name":"grantRole","stateMutability":"nonpayable","inputs":[{"name":"target","type":"address"},{"name":"account","type":"address"},{"name":"role","type":"string"}],"outputs":[]},
+	{"type":"function","name":"revokeRole","stateMutability":"nonpayable","inputs":[{"name":"target","type":"address"},{"name":"account","type":"address"},{"name":"role","type":"string"}],"outputs":[]},
+	{"type":"event","name":"ContractDisabled","inputs":[{"name":"target","type":"address","indexed":true}]},
+	{"type":"event","name":"ContractEnabled","inputs":[{"name":"target","type":"address","indexed":true}]},
+	{"type":"event","name":"OwnershipTransferred","inputs":[{"name":"target","type":"address","indexed":true},{"name":"account","type":"address","indexed":true}]},
+	{"type":"event","name":"RoleGranted","inputs":[{"name":"target","type":"address","indexed":true},{"name":"account","type":"address","indexed":true},{"name":"role","type":"string","indexed":false}]},
+	{"type":"event","name":"RoleRevoked","inputs":[{"name":"target","type":"address","indexed":true},{"name":"account","type":"address","indexed":true},{"name":"role","type":"string","indexed":false}]}
+]`
+
+var (
+	// ErrUnauthorized is returned when the sender of an action is not allowed to perform it
+	ErrUnauthorized = errors.New("sender is not authorized for this action")
+
+	// ErrContractDisabled is returned when a transaction calls a disabled contract
+	ErrContractDisabled = errors.New("contract is disabled")
+)
+
+// Contract access storage: mapping(target => owner) at slot 0, mapping(target => disabled)
+// at slot 1 and mapping(target => mapping(account => role)) at slot 2. Slots 3 and 4 hold
+// the proxy registry and slot 5 the contract metadata hashes.
+const (
+	ownerMappingSlot    = 0
+	disabledMappingSlot = 1
+	roleMappingSlot     = 2
+)
+
+// Stored role values
+var roleCodes = map[Role]int64{RoleAdmin: 1, RoleOperator: 2}
+
+// actionMethods are the contract access methods of the management actions
+var actionMethods = map[string]string{
+	ActionDisable:           "disable",
+	ActionEnable:            "enable",
+	ActionTransferOwnership: "transferOwnership",
+	ActionGrantRole:         "grantRole",
+	ActionRevokeRole:        "revokeRole",
+}
+
+// accessEvents are the management actions of the contract access events
+var accessEvents = map[string]string{
+	"ContractDisabled":     ActionDisable,
+	"ContractEnabled":      ActionEnable,
+	"OwnershipTransferred": ActionTransferOwnership,
+	"RoleGranted":          ActionGrantRole,
+	"RoleRevoked":          ActionRevokeRole,
+}
+
+// PackContractAction encodes a management action on the target contract as the data of a
+// transaction to the contract access system contract. The account is the new owner, or the
+// account a role is granted to or revoked from.
+func PackContractAction(action string, target, account common.Address, role Role) ([]byte, error) {
+	method, exists := actionMethods[action]
+	if !exists {
+		return nil, fmt.Errorf("unknown action %s", action)
+	}
+	contractABI := systemContracts[ContractAccessAddress].abi
+	switch action {
+	case ActionTransferOwnership:
+		return contractABI.Pack(method, target, account)
+	case ActionGrantRole, ActionRevokeRole:
+		return contractABI.Pack(method, target, account, string(role))
+	}
+	return contractABI.Pack(method, target)
+}
+
+// ContractOwner returns the owner of a contract, the zero address if it has none
+func ContractOwner(statedb vm.StateDB, target common.Address) common.Address {
+	return common.BytesToAddress(statedb.GetState(ContractAccessAddress, mappingSlot(target, ownerMappingSlot)).Bytes())
+}
+
+// ContractEnabled reports whether transactions may call the contract
+func ContractEnabled(statedb vm.StateDB, target common.Address) bool {
+	return statedb.GetState(ContractAccessAddress, mappingSlot(target, disabledMappingSlot)) == (common.Hash{})
+}
+
+// ContractRole returns the role of the account on the contract, empty if it has none
+func ContractRole(statedb vm.StateDB, target, account common.Address) Role {
+	code := statedb.GetState(ContractAccessAddress, roleSlot(target, account)).Big().Int64()
+	for role, value := range roleCodes {
+		if value == code {
+			return role
+		}
+	}
+	return ""
+}
+
+// setContractOwner records the owner of a contract
+func setContractOwner(statedb vm.StateDB, target, owner common.Address) {
+	statedb.SetState(ContractAccessAddress, mappingSlot(target, ownerMappingSlot), common.BytesToHash(owner.Bytes()))
+}
+
+// roleSlot returns the storage slot of the account's role on the contract
+func roleSlot(target, account common.Address) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(account.Bytes(), 32), mappingSlot(target, roleMappingSlot).Bytes())
+}
+
+// canPerform reports whether the account may perform the action on the contract
+func canPerform(statedb vm.StateDB, target, account common.Address, action string, subject common.Address, role Role) bool {
+	if account == ContractOwner(statedb, target) {
+		return true
+	}
+
+	switch action {
+	case ActionDisable, ActionEnable:
+		held := ContractRole(statedb, target, account)
+		return held == RoleAdmin || held == RoleOperator
+	case ActionGrantRole, ActionRevokeRole:
+		// Admin'ler yalnızca admin olmayan hesapların operator rolünü yönetebilir
+		return ContractRole(statedb, target, account) == RoleAdmin && role == RoleOperator && ContractRole(statedb, target, subject) != RoleAdmin
+	}
+	return false
+}
+
+// runContractAccess implements the contract access system contract
+func runContractAccess(ctx *systemContext, method string, args []interface{}) ([]interface{}, error) {
+	target := args[0].(common.Address)
+	switch method {
+	case "owner":
+		return []interface{}{ContractOwner(ctx.statedb, target)}, nil
+
+	case "enabled":
+		return []interface{}{ContractEnabled(ctx.statedb, target)}, nil
+
+	case "roleOf":
+		return []interface{}{string(ContractRole(ctx.statedb, target, args[1].(common.Address)))}, nil
+	}
+
+	var action string
+	for name, actionMethod := range actionMethods {
+		if actionMethod == method {
+			action = name
+		}
+	}
+	if action == "" {
+		return nil, fmt.Errorf("unknown method %s", method)
+	}
+	if ContractOwner(ctx.statedb, target) == (common.Address{}) {
+		return nil, errors.New("contract not found")
+	}
+
+	var account common.Address
+	var role Role
+	if len(args) > 1 {
+		account = args[1].(common.Address)
+	}
+	if len(args) > 2 {
+		role = Role(args[2].(string))
+	}
+	if !canPerform(ctx.statedb, target, ctx.caller, action, account, role) {
+		return nil, ErrUnauthorized
+	}
+
+	switch action {
+	case ActionDisable:
+		ctx.statedb.SetState(ContractAccessAddress, mappingSlot(target, disabledMappingSlot), boolHash(true))
+		return nil, ctx.emit("ContractDisabled", target)
+
+	case ActionEnable:
+		ctx.statedb.SetState(ContractAccessAddress, mappingSlot(target, disabledMappingSlot), boolHash(false))
+		return nil, ctx.emit("ContractEnabled", target)
+
+	case ActionTransferOwnership:
+		if account == (common.Address{}) {
+			return nil, errors.New("new owner is required")
+		}
+		setContractOwner(ctx.statedb, target, account)
+		return nil, ctx.emit("OwnershipTransferred", target, account)
+
+	case ActionGrantRole:
+		code, known := roleCodes[role]
+		if !known {
+			return nil, fmt.Errorf("unknown role %s", role)
+		}
+		if account == (common.Address{}) {
+			return nil, errors.New("account is required")
+		}
+		ctx.statedb.SetState(ContractAccessAddress, roleSlot(target, account), common.BigToHash(big.NewInt(code)))
+		return nil, ctx.emit("RoleGranted", target, account, string(role))
+
+	default:
+		if role == "" || ContractRole(ctx.statedb, target, account) != role {
+			return nil, fmt.Errorf("account does not hold the %s role", role)
+		}
+		ctx.statedb.SetState(ContractAccessAddress, roleSlot(target, account), common.Hash{})
+		return nil, ctx.emit("RoleRevoked", target, account, string(role))
+	}
+}
+
+// AccessChange is a management action applied by a transaction
+type AccessChange struct {
+	Contract common.Address
+	Action   string
+	Account  common.Address // New owner, or the account a role was granted to or revoked from
+	Role     Role
+}
+
+// DecodeAccessChange decodes a log of the contract access system contract
+func DecodeAccessChange(log *types.Log) (*AccessChange, bool) {
+	if log.Address != ContractAccessAddress || len(log.Topics) < 2 {
+		return nil, false
+	}
+	contractABI := systemContracts[ContractAccessAddress].abi
+	event, err := contractABI.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, false
+	}
+
+	change := &AccessChange{Contract: common.BytesToAddress(log.Topics[1].Bytes()), Action: accessEvents[event.Name]}
+	if len(log.Topics) > 2 {
+		change.Account = common.BytesToAddress(log.Topics[2].Bytes())
+	}
+	if change.Action == ActionGrantRole || change.Action == ActionRevokeRole {
+		values, err := event.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil || len(values) != 1 {
+			return nil, false
+		}
+		change.Role = Role(values[0].(string))
+	}
+	return change, true
+}
+
+// HasRole reports whether the account holds the role on the contract
+func (c *Contract) HasRole(account common.Address, role Role) bool {
+	return role != "" && c.Roles[account] == role
+}
+
+// RecordAccessChange updates the contract record with a management action applied by a
+// block. The record is informational; execution reads the contract access state.
+func (m *Manager) RecordAccessChange(change *AccessChange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	contract, exists := m.contracts[change.Contract]
+	if !exists {
+		return errors.New("contract not found")
+	}
+
+	switch change.Action {
+	case ActionDisable:
+		contract.IsEnabled = false
+	case ActionEnable:
+		contract.IsEnabled = true
+	case ActionTransferOwnership:
+		contract.Owner = change.Account
+	case ActionGrantRole:
+		if contract.Roles == nil {
+			contract.Roles = make(map[common.Address]Role)
+		}
+		contract.Roles[change.Account] = change.Role
+	case ActionRevokeRole:
+		delete(contract.Roles, change.Account)
+	}
+	return nil
+}
+
+// RecoverSigner recovers the address that signed the hash. Signatures are 65 bytes
+// [R || S || V], V may be 0/1 or 27/28.
+func RecoverSigner(hash, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, errors.New("signature must be 65 bytes")
+	}
+	sig := common.CopyBytes(signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %v", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
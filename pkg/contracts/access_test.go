@@ -0,0 +1,84 @@
+package contracts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestContractActions yönetim işlemlerinin ve rollerin state'te tutulduğunu test eder
+func TestContractActions(t *testing.T) {
+	manager := NewManager()
+	owner := common.HexToAddress("0x01")
+	admin := common.HexToAddress("0x02")
+	operator := common.HexToAddress("0x03")
+	newOwner := common.HexToAddress("0x04")
+	address := deployCounter(t, manager, owner)
+
+	apply := func(from common.Address, action string, account common.Address, role Role) error {
+		data, err := PackContractAction(action, address, account, role)
+		if err != nil {
+			t.Fatalf("İşlem kodlanamadı: %v", err)
+		}
+		statedb := NewStateDB(manager.State())
+		result := manager.ApplyMessage(statedb, &Message{From: from, To: &ContractAccessAddress, Data: data, GasLimit: 100000})
+		statedb.Commit()
+		return result.Err
+	}
+	statedb := func() *StateDB { return NewStateDB(manager.State()) }
+
+	// Deploy eden hesap kontratın sahibidir
+	if ContractOwner(statedb(), address) != owner {
+		t.Fatalf("Kontrat sahibi kaydedilmedi: %s", ContractOwner(statedb(), address).Hex())
+	}
+
+	// Yalnızca sahibi veya rol sahipleri işlem yapabilir
+	if err := apply(admin, ActionDisable, common.Address{}, ""); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Yetkisiz işlem kabul edildi: %v", err)
+	}
+	if err := apply(owner, ActionGrantRole, admin, RoleAdmin); err != nil {
+		t.Fatalf("Admin rolü verilemedi: %v", err)
+	}
+
+	// Admin operator atayabilir ama admin atayamaz
+	if err := apply(admin, ActionGrantRole, operator, RoleAdmin); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Admin başka bir admin atayabildi: %v", err)
+	}
+	if err := apply(admin, ActionGrantRole, operator, RoleOperator); err != nil {
+		t.Fatalf("Operator rolü verilemedi: %v", err)
+	}
+
+	// Devre dışı kontrat çağrılamaz
+	if err := apply(operator, ActionDisable, common.Address{}, ""); err != nil || ContractEnabled(statedb(), address) {
+		t.Fatalf("Operator kontratı devre dışı bırakamadı: %v", err)
+	}
+	result := manager.ApplyMessage(statedb(), &Message{From: owner, To: &address, GasLimit: 100000})
+	if !errors.Is(result.Err, ErrContractDisabled) {
+		t.Errorf("Devre dışı kontrat hatası bekleniyordu: %v", result.Err)
+	}
+	if err := apply(operator, ActionTransferOwnership, operator, ""); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Operator sahipliği devralabildi: %v", err)
+	}
+
+	// Sahiplik devri sonrası eski sahip yetkisini kaybeder
+	if err := apply(owner, ActionTransferOwnership, newOwner, ""); err != nil || ContractOwner(statedb(), address) != newOwner {
+		t.Fatalf("Sahiplik devredilemedi: %v", err)
+	}
+	if err := apply(owner, ActionEnable, common.Address{}, ""); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Eski sahip işlem yapabildi: %v", err)
+	}
+	if err := apply(newOwner, ActionRevokeRole, operator, RoleOperator); err != nil || ContractRole(statedb(), address, operator) != "" {
+		t.Errorf("Operator rolü geri alınamadı: %v", err)
+	}
+	if ContractRole(statedb(), address, admin) != RoleAdmin {
+		t.Error("Admin rolü state'te yok")
+	}
+
+	// Kayıtlı olmayan kontratlar yönetilemez
+	data, _ := PackContractAction(ActionDisable, common.HexToAddress("0xdead"), common.Address{}, "")
+	result = manager.ApplyMessage(statedb(), &Message{From: owner, To: &ContractAccessAddress, Data: data, GasLimit: 100000})
+	if result.Err == nil {
+		t.Error("Sahibi olmayan adres devre dışı bırakıldı")
+	}
+}
@@ -0,0 +1,65 @@
+package contracts
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// DefaultChainID is the chain ID of chains whose genesis does not set one
+var DefaultChainID = big.NewInt(1337)
+
+// DefaultChainConfig returns the EVM configuration of chains whose genesis does not set
+// one: every fork up to and including Shanghai is active from the genesis block.
+func DefaultChainConfig() *params.ChainConfig {
+	zero := uint64(0)
+	return &params.ChainConfig{
+		ChainID:                       new(big.Int).Set(DefaultChainID),
+		HomesteadBlock:                new(big.Int),
+		EIP150Block:                   new(big.Int),
+		EIP155Block:                   new(big.Int),
+		EIP158Block:                   new(big.Int),
+		ByzantiumBlock:                new(big.Int),
+		ConstantinopleBlock:           new(big.Int),
+		PetersburgBlock:               new(big.Int),
+		IstanbulBlock:                 new(big.Int),
+		MuirGlacierBlock:              new(big.Int),
+		BerlinBlock:                   new(big.Int),
+		LondonBlock:                   new(big.Int),
+		TerminalTotalDifficulty:       new(big.Int),
+		TerminalTotalDifficultyPassed: true,
+		ShanghaiTime:                  &zero,
+	}
+}
+
+// ValidateChainConfig checks that the configuration has a chain ID and that its forks
+// are activated in order
+func ValidateChainConfig(config *params.ChainConfig) error {
+	if config == nil {
+		return errors.New("chain config is required")
+	}
+	if config.ChainID == nil || config.ChainID.Sign() <= 0 {
+		return errors.New("chain config requires a positive chain ID")
+	}
+	return config.CheckConfigForkOrder()
+}
+
+// BlockContext is the block a message is executed in, read by the block opcodes
+// (NUMBER, TIMESTAMP, COINBASE, GASLIMIT, BLOCKHASH)
+type BlockContext struct {
+	Number   uint64
+	Time     uint64
+	Coinbase common.Address // Block producer
+	GasLimit uint64
+
+	// GetHash returns the hash of an ancestor block by number, zero if it is not known
+	GetHash func(number uint64) common.Hash
+}
+
+// isMerge reports whether the chain runs with post-merge rules; DIFFICULTY then returns
+// PREVRANDAO
+func isMerge(config *params.ChainConfig) bool {
+	return config.TerminalTotalDifficultyPassed
+}
@@ -0,0 +1,109 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestBlockContext blok opcode'larının mesajın bloğundan okunduğunu test eder
+func TestBlockContext(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+
+	// NUMBER, TIMESTAMP, COINBASE, BLOCKHASH(NUMBER-1) ve CHAINID değerlerini döndüren kontrat
+	runtime := common.FromHex("436000524260205241604052600143034060605246608052" + "60a06000f3")
+	statedb := NewStateDB(manager.State())
+	args := common.BigToHash(big.NewInt(0)).Bytes()
+	deployed := manager.ApplyMessage(statedb, &Message{From: sender, Data: append(initCode(runtime), args...), GasLimit: 200000})
+	if deployed.Failed() {
+		t.Fatalf("Kontrat deploy edilemedi: %v", deployed.Err)
+	}
+	statedb.Commit()
+
+	coinbase := common.HexToAddress("0xc0ffee")
+	parent := common.HexToHash("0xabcdef")
+	block := &BlockContext{
+		Number:   10,
+		Time:     1700000000,
+		Coinbase: coinbase,
+		GasLimit: 8000000,
+		GetHash: func(number uint64) common.Hash {
+			if number == 9 {
+				return parent
+			}
+			return common.Hash{}
+		},
+	}
+	result := manager.Simulate(manager.State(), &Message{From: sender, To: &deployed.ContractAddress, Block: block})
+	if result.Failed() || len(result.ReturnData) != 160 {
+		t.Fatalf("Çağrı başarısız: %v", result.Err)
+	}
+
+	word := func(i int) []byte { return result.ReturnData[i*32 : (i+1)*32] }
+	if number := new(big.Int).SetBytes(word(0)).Uint64(); number != 10 {
+		t.Errorf("NUMBER hatalı: %d", number)
+	}
+	if time := new(big.Int).SetBytes(word(1)).Uint64(); time != 1700000000 {
+		t.Errorf("TIMESTAMP hatalı: %d", time)
+	}
+	if got := common.BytesToAddress(word(2)); got != coinbase {
+		t.Errorf("COINBASE hatalı: %s", got.Hex())
+	}
+	if got := common.BytesToHash(word(3)); got != parent {
+		t.Errorf("BLOCKHASH hatalı: %s", got.Hex())
+	}
+	if chainID := new(big.Int).SetBytes(word(4)); chainID.Cmp(DefaultChainID) != 0 {
+		t.Errorf("CHAINID hatalı: %s", chainID)
+	}
+
+	// Chain config değiştirildiğinde CHAINID yeni değeri döndürür
+	config := DefaultChainConfig()
+	config.ChainID = big.NewInt(99)
+	manager.SetChainConfig(config)
+	result = manager.Simulate(manager.State(), &Message{From: sender, To: &deployed.ContractAddress, Block: block})
+	if result.Failed() || new(big.Int).SetBytes(result.ReturnData[128:]).Int64() != 99 {
+		t.Errorf("CHAINID yeni config'i kullanmadı: %x %v", result.ReturnData, result.Err)
+	}
+}
+
+// TestChainConfigForks fork kurallarının chain config'e göre uygulandığını test eder
+func TestChainConfigForks(t *testing.T) {
+	// PUSH0 yalnızca Shanghai ile geçerlidir: PUSH0 PUSH0 RETURN
+	runtime := common.FromHex("5f5ff3")
+	sender := common.HexToAddress("0x1234567890")
+	address := common.HexToAddress("0xc0de")
+
+	run := func(config *params.ChainConfig) *ExecutionResult {
+		manager := NewManager()
+		manager.SetChainConfig(config)
+		statedb := NewStateDB(manager.State())
+		statedb.CreateAccount(address)
+		statedb.SetCode(address, runtime)
+		statedb.Commit()
+		return manager.Simulate(manager.State(), &Message{From: sender, To: &address})
+	}
+
+	if result := run(DefaultChainConfig()); result.Failed() {
+		t.Errorf("Shanghai ile PUSH0 çalışmalı: %v", result.Err)
+	}
+
+	london := DefaultChainConfig()
+	london.ShanghaiTime = nil
+	if result := run(london); !result.Failed() {
+		t.Error("Shanghai olmadan PUSH0 geçersiz olmalı")
+	}
+
+	invalid := DefaultChainConfig()
+	invalid.ChainID = nil
+	if err := ValidateChainConfig(invalid); err == nil {
+		t.Error("Chain ID'siz config kabul edildi")
+	}
+	invalid = DefaultChainConfig()
+	invalid.ByzantiumBlock = nil
+	if err := ValidateChainConfig(invalid); err == nil {
+		t.Error("Sırasız fork'lar kabul edildi")
+	}
+}
@@ -0,0 +1,78 @@
+package contracts
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// ErrIntrinsicGas is returned when the gas limit does not cover the intrinsic gas
+	ErrIntrinsicGas = errors.New("intrinsic gas too low")
+
+	// ErrInsufficientFunds is returned when the sender cannot pay for gas * price + value
+	ErrInsufficientFunds = errors.New("insufficient funds for gas * price + value")
+
+	// ErrGasPriceTooLow is returned when a transaction pays less than the minimum gas price
+	ErrGasPriceTooLow = errors.New("gas price below minimum")
+
+	// ErrGasUintOverflow is returned when the gas calculation overflows
+	ErrGasUintOverflow = errors.New("gas uint64 overflow")
+)
+
+// IntrinsicGas computes the gas charged before execution: the base transaction cost,
+// the calldata cost and, for deployments after Shanghai, the init code word cost (EIP-3860).
+func IntrinsicGas(data []byte, isContractCreation bool, rules params.Rules) (uint64, error) {
+	var gas uint64
+	if isContractCreation && rules.IsHomestead {
+		gas = params.TxGasContractCreation
+	} else {
+		gas = params.TxGas
+	}
+
+	dataLen := uint64(len(data))
+	if dataLen == 0 {
+		return gas, nil
+	}
+
+	// Sıfır olmayan byte'lar daha pahalıdır (EIP-2028 ile ucuzladı)
+	var nonZero uint64
+	for _, b := range data {
+		if b != 0 {
+			nonZero++
+		}
+	}
+	nonZeroGas := params.TxDataNonZeroGasFrontier
+	if rules.IsIstanbul {
+		nonZeroGas = params.TxDataNonZeroGasEIP2028
+	}
+	if (math.MaxUint64-gas)/nonZeroGas < nonZero {
+		return 0, ErrGasUintOverflow
+	}
+	gas += nonZero * nonZeroGas
+
+	zero := dataLen - nonZero
+	if (math.MaxUint64-gas)/params.TxDataZeroGas < zero {
+		return 0, ErrGasUintOverflow
+	}
+	gas += zero * params.TxDataZeroGas
+
+	if isContractCreation && rules.IsShanghai {
+		words := (dataLen + 31) / 32
+		if (math.MaxUint64-gas)/params.InitCodeWordGas < words {
+			return 0, ErrGasUintOverflow
+		}
+		gas += words * params.InitCodeWordGas
+	}
+
+	return gas, nil
+}
+
+// refundQuotient returns the maximum share of used gas that can be refunded
+func refundQuotient(rules params.Rules) uint64 {
+	if rules.IsLondon {
+		return params.RefundQuotientEIP3529
+	}
+	return params.RefundQuotient
+}
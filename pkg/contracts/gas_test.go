@@ -0,0 +1,137 @@
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestIntrinsicGas calldata ve deploy maliyetlerinin kurallara göre hesaplandığını test eder
+func TestIntrinsicGas(t *testing.T) {
+	data := []byte{0x00, 0x01}
+
+	frontier, err := IntrinsicGas(data, false, params.Rules{})
+	if err != nil {
+		t.Fatalf("Intrinsic gas hesaplanamadı: %v", err)
+	}
+	if frontier != params.TxGas+params.TxDataZeroGas+params.TxDataNonZeroGasFrontier {
+		t.Errorf("Frontier intrinsic gas hatalı: %d", frontier)
+	}
+
+	istanbul, _ := IntrinsicGas(data, false, params.Rules{IsIstanbul: true})
+	if istanbul != params.TxGas+params.TxDataZeroGas+params.TxDataNonZeroGasEIP2028 {
+		t.Errorf("Istanbul intrinsic gas hatalı: %d", istanbul)
+	}
+
+	create, _ := IntrinsicGas(nil, true, params.Rules{IsHomestead: true})
+	if create != params.TxGasContractCreation {
+		t.Errorf("Deploy intrinsic gas hatalı: %d", create)
+	}
+}
+
+// TestGasFees gönderenin kullanılan gas kadar ödediğini ve ücretin coinbase'e yazıldığını test eder
+func TestGasFees(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+	coinbase := common.HexToAddress("0xc0ffee")
+	initial := big.NewInt(1e18)
+	manager.State().SetAccount(sender, &Account{Balance: new(big.Int).Set(initial)})
+
+	statedb := NewStateDB(manager.State())
+	args := common.BigToHash(big.NewInt(0)).Bytes()
+	data := append(initCode(counterCode), args...)
+	result := manager.ApplyMessage(statedb, &Message{
+		From:     sender,
+		Data:     data,
+		GasLimit: 200000,
+		GasPrice: big.NewInt(2),
+		Coinbase: coinbase,
+	})
+	if result.Failed() {
+		t.Fatalf("Kontrat deploy edilemedi: %v", result.Err)
+	}
+	statedb.Commit()
+
+	intrinsicGas, _ := manager.IntrinsicGas(data, true, nil)
+	if result.GasUsed <= intrinsicGas || result.GasUsed >= 200000 {
+		t.Errorf("Kullanılan gas hatalı: %d", result.GasUsed)
+	}
+	if result.Fee.Cmp(big.NewInt(int64(result.GasUsed)*2)) != 0 {
+		t.Errorf("Ücret gas * fiyat değil: %s", result.Fee)
+	}
+
+	expected := new(big.Int).Sub(initial, result.Fee)
+	if balance := manager.State().GetBalance(sender); balance.Cmp(expected) != 0 {
+		t.Errorf("Gönderen bakiyesi hatalı: beklenen %s, alınan %s", expected, balance)
+	}
+	if balance := manager.State().GetBalance(coinbase); balance.Cmp(result.Fee) != 0 {
+		t.Errorf("Coinbase bakiyesi hatalı: beklenen %s, alınan %s", result.Fee, balance)
+	}
+}
+
+// TestOutOfGas gas'ı biten çağrının state'i geri aldığını ve tüm gas limit'ini ücretlendirdiğini test eder
+func TestOutOfGas(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+	manager.State().SetAccount(sender, &Account{Balance: big.NewInt(1e18)})
+
+	statedb := NewStateDB(manager.State())
+	args := common.BigToHash(big.NewInt(7)).Bytes()
+	deploy := manager.ApplyMessage(statedb, &Message{From: sender, Data: append(initCode(counterCode), args...), GasLimit: 200000})
+	if deploy.Failed() {
+		t.Fatalf("Kontrat deploy edilemedi: %v", deploy.Err)
+	}
+	statedb.Commit()
+	address := deploy.ContractAddress
+
+	// SSTORE için yeterli gas bırakılmaz
+	intrinsicGas, _ := manager.IntrinsicGas(nil, false, nil)
+	gasLimit := intrinsicGas + 100
+	before := manager.State().GetBalance(sender)
+
+	statedb = NewStateDB(manager.State())
+	result := manager.ApplyMessage(statedb, &Message{From: sender, To: &address, GasLimit: gasLimit, GasPrice: big.NewInt(3)})
+	if !result.Failed() {
+		t.Fatal("Gas'ı yetmeyen çağrı başarılı oldu")
+	}
+	statedb.Commit()
+
+	if result.GasUsed != gasLimit {
+		t.Errorf("Tüm gas limit'i kullanılmalı: %d", result.GasUsed)
+	}
+	if slot := statedb.GetState(address, common.Hash{}); slot != common.BigToHash(big.NewInt(7)) {
+		t.Errorf("Başarısız çağrının storage değişikliği geri alınmadı: %x", slot)
+	}
+	expected := new(big.Int).Sub(before, big.NewInt(int64(gasLimit)*3))
+	if balance := manager.State().GetBalance(sender); balance.Cmp(expected) != 0 {
+		t.Errorf("Gönderen bakiyesi hatalı: beklenen %s, alınan %s", expected, balance)
+	}
+	if nonce := manager.State().GetAccount(sender).Nonce; nonce != 2 {
+		t.Errorf("Başarısız çağrı nonce'u artırmadı: %d", nonce)
+	}
+}
+
+// TestInsufficientFunds gas'ı ödeyemeyen mesajın state'i değiştirmediğini test eder
+func TestInsufficientFunds(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+
+	statedb := NewStateDB(manager.State())
+	result := manager.ApplyMessage(statedb, &Message{From: sender, Data: initCode(counterCode), GasLimit: 200000, GasPrice: big.NewInt(1)})
+	if !errors.Is(result.Err, ErrInsufficientFunds) {
+		t.Fatalf("Yetersiz bakiye hatası bekleniyordu: %v", result.Err)
+	}
+	statedb.Commit()
+
+	if manager.State().GetAccount(sender) != nil {
+		t.Error("Ödenemeyen mesaj gönderen hesabını değiştirdi")
+	}
+
+	low := manager.ApplyMessage(NewStateDB(manager.State()), &Message{From: sender, Data: initCode(counterCode), GasLimit: 100})
+	if !errors.Is(low.Err, ErrIntrinsicGas) {
+		t.Errorf("Intrinsic gas hatası bekleniyordu: %v", low.Err)
+	}
+}
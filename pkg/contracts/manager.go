@@ -3,27 +3,40 @@ package contracts
 import (
 	"encoding/json"
 	"errors"
-	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // Contract represents a smart contract
 type Contract struct {
-	Address    common.Address `json:"address"`
-	Code       []byte        `json:"code"`
-	Owner      common.Address `json:"owner"`
-	Name       string        `json:"name"`
-	Version    string        `json:"version"`
-	Timestamp  int64         `json:"timestamp"`
-	IsEnabled  bool          `json:"is_enabled"`
+	Address   common.Address `json:"address"`
+	Code      []byte         `json:"code"`
+	Owner     common.Address `json:"owner"`
+	Name      string         `json:"name"`
+	Version   string         `json:"version"`
+	Timestamp int64          `json:"timestamp"`
+	IsEnabled bool           `json:"is_enabled"`
+
+	// Kontratla deploy edilen ABI, metot çağrılarını ve event'leri çözmek için kullanılır
+	ABI json.RawMessage `json:"abi,omitempty"`
+
+	// Doğrulanmış kaynak kod, explorer'da gösterilir
+	Source *SourceVerification `json:"source,omitempty"`
+
+	// Proxy kontratların implementasyon geçmişi, sonuncusu güncel implementasyondur
+	Versions []ImplementationVersion `json:"versions,omitempty"`
+
+	// Yönetim yetkileri; state'teki ContractAccess kayıtlarının bilgi amaçlı kopyasıdır
+	Roles map[common.Address]Role `json:"roles,omitempty"`
 }
 
 // Manager manages smart contracts
 type Manager struct {
 	contracts map[common.Address]*Contract
+	registry  map[string]common.Address // Logical name -> proxy address
 	vm        *VM
 	mu        sync.RWMutex
 }
@@ -32,32 +45,35 @@ type Manager struct {
 func NewManager() *Manager {
 	return &Manager{
 		contracts: make(map[common.Address]*Contract),
-		vm:        NewVM(),
+		registry:  make(map[string]common.Address),
+		vm:        NewVM(NewWorldState()),
 	}
 }
 
-// DeployContract deploys a new smart contract
-func (m *Manager) DeployContract(code []byte, owner common.Address, name, version string, timestamp int64) (*Contract, error) {
-	// Kontrat kodunu doğrula
-	if err := m.vm.ValidateContract(code); err != nil {
-		return nil, err
-	}
+// ChainConfig returns the chain ID and fork configuration contracts run with
+func (m *Manager) ChainConfig() *params.ChainConfig {
+	return m.vm.ChainConfig()
+}
 
-	// Kontrat adresi oluştur
-	address := generateContractAddress(code, owner, timestamp)
+// SetChainConfig replaces the chain ID and fork configuration contracts run with
+func (m *Manager) SetChainConfig(config *params.ChainConfig) {
+	m.vm.SetChainConfig(config)
+}
 
-	// Kontratın zaten var olup olmadığını kontrol et
-	m.mu.RLock()
-	if _, exists := m.contracts[address]; exists {
-		m.mu.RUnlock()
-		return nil, errors.New("contract already exists")
+// DeployContract runs the init code directly against the current state and registers
+// the contract with its runtime code. Deployments on the chain go through transactions.
+func (m *Manager) DeployContract(code []byte, owner common.Address, name, version string, timestamp int64) (*Contract, error) {
+	statedb := NewStateDB(m.vm.State())
+	result := m.ApplyMessage(statedb, &Message{From: owner, Data: code, GasLimit: m.vm.GasCap(), Timestamp: timestamp})
+	if result.Failed() {
+		return nil, result.Err
 	}
-	m.mu.RUnlock()
+	statedb.Commit()
 
 	// Yeni kontrat oluştur
 	contract := &Contract{
-		Address:   address,
-		Code:      code,
+		Address:   result.ContractAddress,
+		Code:      result.ReturnData,
 		Owner:     owner,
 		Name:      name,
 		Version:   version,
@@ -66,79 +82,60 @@ func (m *Manager) DeployContract(code []byte, owner common.Address, name, versio
 	}
 
 	// Kontratı kaydet
-	m.mu.Lock()
-	m.contracts[address] = contract
-	m.mu.Unlock()
-
+	m.RegisterContract(contract)
 	return contract, nil
 }
 
-// ExecuteContract executes a smart contract
-func (m *Manager) ExecuteContract(address common.Address, input []byte) ([]byte, error) {
-	// Kontratı bul
-	m.mu.RLock()
-	contract, exists := m.contracts[address]
-	m.mu.RUnlock()
-
-	if !exists {
-		return nil, errors.New("contract not found")
-	}
-
-	if !contract.IsEnabled {
-		return nil, errors.New("contract is disabled")
-	}
-
-	// Kontratı çalıştır
-	return m.vm.Execute(contract.Code, input)
+// ValidateContract validates contract code before it is deployed
+func (m *Manager) ValidateContract(code []byte) error {
+	return m.vm.ValidateContract(code)
 }
 
-// GetContract returns a contract by address
-func (m *Manager) GetContract(address common.Address) (*Contract, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+// ValidateRuntimeCode validates runtime code with the checks run on deploy
+func (m *Manager) ValidateRuntimeCode(code []byte) error {
+	return m.vm.ValidateRuntimeCode(code)
+}
 
-	contract, exists := m.contracts[address]
-	if !exists {
-		return nil, errors.New("contract not found")
-	}
+// SetValidationConfig replaces the checks run on contract code on deploy
+func (m *Manager) SetValidationConfig(config ValidationConfig) {
+	m.vm.SetValidationConfig(config)
+}
 
-	return contract, nil
+// IntrinsicGas returns the intrinsic gas of a deployment or call under the chain rules of
+// the block, or of the genesis block if nil
+func (m *Manager) IntrinsicGas(data []byte, isContractCreation bool, block *BlockContext) (uint64, error) {
+	return IntrinsicGas(data, isContractCreation, m.vm.Rules(block))
 }
 
-// DisableContract disables a contract
-func (m *Manager) DisableContract(address common.Address, owner common.Address) error {
+// RegisterContract records a contract deployed by a transaction
+func (m *Manager) RegisterContract(contract *Contract) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	contract, exists := m.contracts[address]
-	if !exists {
-		return errors.New("contract not found")
-	}
-
-	if contract.Owner != owner {
-		return errors.New("not contract owner")
-	}
+	m.contracts[contract.Address] = contract
+	m.registerProxyLocked(contract)
+}
 
-	contract.IsEnabled = false
-	return nil
+// State returns the world state backing contract execution
+func (m *Manager) State() *WorldState {
+	return m.vm.State()
 }
 
-// EnableContract enables a contract
-func (m *Manager) EnableContract(address common.Address, owner common.Address) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// GetContract returns a contract by address
+func (m *Manager) GetContract(address common.Address) (*Contract, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
 	contract, exists := m.contracts[address]
 	if !exists {
-		return errors.New("contract not found")
-	}
-
-	if contract.Owner != owner {
-		return errors.New("not contract owner")
+		// Sistem kontratları kayıtlı kontratlar arasında listelenmez
+		if record := systemContractRecord(address); record != nil {
+			return record, nil
+		}
+		return nil, errors.New("contract not found")
 	}
 
-	contract.IsEnabled = true
-	return nil
+	return contract, nil
 }
 
 // ListContracts returns all contracts
@@ -154,6 +151,32 @@ func (m *Manager) ListContracts() []*Contract {
 	return contracts
 }
 
+// RestoreContracts replaces all contracts, used when restoring state from a snapshot
+func (m *Manager) RestoreContracts(contracts []*Contract) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.contracts = make(map[common.Address]*Contract, len(contracts))
+	m.registry = make(map[string]common.Address)
+	for _, contract := range contracts {
+		m.contracts[contract.Address] = contract
+	}
+
+	// Aynı isimli proxy'lerden ilk oluşturulan ismi alır
+	var proxies []*Contract
+	for _, contract := range contracts {
+		if contract.IsProxy() {
+			proxies = append(proxies, contract)
+		}
+	}
+	sort.Slice(proxies, func(i, j int) bool {
+		return proxies[i].Versions[0].BlockHeight < proxies[j].Versions[0].BlockHeight
+	})
+	for _, proxy := range proxies {
+		m.registerProxyLocked(proxy)
+	}
+}
+
 // MarshalJSON implements json.Marshaler
 func (c *Contract) MarshalJSON() ([]byte, error) {
 	type Alias Contract
@@ -167,13 +190,3 @@ func (c *Contract) MarshalJSON() ([]byte, error) {
 		Alias:   (*Alias)(c),
 	})
 }
-
-// generateContractAddress generates a unique contract address
-func generateContractAddress(code []byte, owner common.Address, timestamp int64) common.Address {
-	// Adres oluşturmak için gerekli verileri birleştir
-	data := append(code, owner.Bytes()...)
-	data = append(data, common.BigToHash(big.NewInt(timestamp)).Bytes()...)
-	
-	// Keccak-256 hash'i hesapla
-	return common.BytesToAddress(crypto.Keccak256(data))
-} 
\ No newline at end of file
@@ -0,0 +1,197 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Message is a contract deployment or call applied to the world state during block processing
+type Message struct {
+	From      common.Address
+	To        *common.Address // nil for contract deployment
+	Value     *big.Int
+	Data      []byte       // Init code followed by constructor arguments for deployments
+	Salt      *common.Hash // Deploys with CREATE2 when set
+	GasLimit  uint64
+	GasPrice  *big.Int
+	Coinbase  common.Address // Account credited with the fees
+	Timestamp int64          // Timestamp of the block including the message
+
+	// Block is the block the message is executed in; nil executes it in an empty genesis block
+	Block *BlockContext
+
+	// Implementation creates a proxy (deployment) or upgrades the proxy the message is sent to
+	Implementation *common.Address
+
+	// Name is the unique name a proxy is registered under when it is created
+	Name string
+
+	// Tracer receives the execution steps of the message when set
+	Tracer vm.EVMLogger
+}
+
+// IsDeployment reports whether the message deploys a new contract
+func (msg *Message) IsDeployment() bool {
+	return msg.To == nil
+}
+
+// executionContext returns the environment the message is executed in: the message's
+// block, with the sender as the origin of the transaction
+func (msg *Message) executionContext() *ExecutionContext {
+	return &ExecutionContext{Block: msg.Block, Origin: msg.From, GasPrice: msg.GasPrice, Tracer: msg.Tracer}
+}
+
+// ExecutionResult is the outcome of applying a message
+type ExecutionResult struct {
+	ReturnData      []byte
+	ContractAddress common.Address
+	GasUsed         uint64   // Gas charged after the refund
+	RefundedGas     uint64   // Gas refunded for storage clearing
+	Fee             *big.Int // GasUsed * GasPrice, credited to the coinbase
+	Err             error
+}
+
+// Failed reports whether the message failed; its state changes are reverted
+func (r *ExecutionResult) Failed() bool {
+	return r.Err != nil
+}
+
+// ApplyMessage applies a deployment or call to the given StateDB. The sender buys the
+// gas limit up front, unused and refunded gas is returned and the fee for the used gas
+// is credited to the coinbase. A message that cannot pay for its gas leaves the state
+// unchanged; a failed execution (revert, out of gas) only keeps the fee and nonce increment.
+func (m *Manager) ApplyMessage(statedb *StateDB, msg *Message) *ExecutionResult {
+	rules := m.vm.Rules(msg.Block)
+	gasPrice := bigOrZero(msg.GasPrice)
+	value := bigOrZero(msg.Value)
+
+	if msg.Tracer != nil {
+		msg.Tracer.CaptureTxStart(msg.GasLimit)
+	}
+
+	// Ön kontroller
+	intrinsicGas, err := IntrinsicGas(msg.Data, msg.IsDeployment(), rules)
+	if err != nil {
+		return &ExecutionResult{Fee: new(big.Int), Err: err}
+	}
+	if msg.GasLimit < intrinsicGas {
+		return &ExecutionResult{Fee: new(big.Int), Err: fmt.Errorf("%w: have %d, want %d", ErrIntrinsicGas, msg.GasLimit, intrinsicGas)}
+	}
+	gasCost := new(big.Int).Mul(new(big.Int).SetUint64(msg.GasLimit), gasPrice)
+	if statedb.GetBalance(msg.From).Cmp(new(big.Int).Add(gasCost, value)) < 0 {
+		return &ExecutionResult{Fee: new(big.Int), Err: ErrInsufficientFunds}
+	}
+
+	// Gas'ı peşin satın al
+	statedb.SubBalance(msg.From, gasCost)
+	gas := msg.GasLimit - intrinsicGas
+	statedb.Prepare(rules, msg.From, msg.Coinbase, msg.To, vm.ActivePrecompiles(rules), nil)
+
+	var result *ExecutionResult
+	if msg.Implementation != nil {
+		result = m.applyProxy(statedb, msg, gas)
+	} else if msg.IsDeployment() {
+		result = m.applyDeployment(statedb, msg, gas)
+	} else {
+		// Gönderenin nonce'u başarısız çağrılarda da artar
+		statedb.SetNonce(msg.From, statedb.GetNonce(msg.From)+1)
+		result = m.applyCall(statedb, msg, gas)
+	}
+
+	// Refund en fazla kullanılan gas'ın belirli bir oranı kadar olabilir
+	gasUsed := intrinsicGas + result.GasUsed
+	refund := statedb.GetRefund()
+	if maxRefund := gasUsed / refundQuotient(rules); refund > maxRefund {
+		refund = maxRefund
+	}
+	gasUsed -= refund
+
+	remaining := new(big.Int).Mul(new(big.Int).SetUint64(msg.GasLimit-gasUsed), gasPrice)
+	statedb.AddBalance(msg.From, remaining)
+
+	// Ücret parametrelerinde bir alıcı tanımlıysa ücretler validator yerine ona gider
+	coinbase := msg.Coinbase
+	if recipient := FeeRecipient(statedb); recipient != (common.Address{}) {
+		coinbase = recipient
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), gasPrice)
+	statedb.AddBalance(coinbase, fee)
+
+	if msg.Tracer != nil {
+		msg.Tracer.CaptureTxEnd(msg.GasLimit - gasUsed)
+	}
+
+	result.GasUsed = gasUsed
+	result.RefundedGas = refund
+	result.Fee = fee
+	result.Err = revertError(result)
+	return result
+}
+
+// applyDeployment runs the init code and stores the returned runtime code.
+// The EVM increments the sender's nonce and reverts its own changes on failure.
+func (m *Manager) applyDeployment(statedb *StateDB, msg *Message, gas uint64) *ExecutionResult {
+	if !DeployerAllowed(statedb, msg.From) {
+		statedb.SetNonce(msg.From, statedb.GetNonce(msg.From)+1)
+		return &ExecutionResult{Err: ErrDeployerNotAllowed}
+	}
+	if err := m.vm.ValidateContract(msg.Data); err != nil {
+		statedb.SetNonce(msg.From, statedb.GetNonce(msg.From)+1)
+		return &ExecutionResult{Err: err}
+	}
+
+	nonce := statedb.GetNonce(msg.From)
+	snapshot := statedb.Snapshot()
+	ret, address, gasUsed, err := m.vm.Create(statedb, msg.executionContext(), msg.From, msg.Data, msg.Salt, bigOrZero(msg.Value), gas)
+	// Geçersiz runtime kodu saklanmaz; oluşturma geri alınır ve tüm gas tüketilir. EVM'in
+	// fork kurallarıyla reddettiği kodlar için de ayrıntılı doğrulama hatası döndürülür.
+	if err == nil || errors.Is(err, vm.ErrInvalidCode) || errors.Is(err, vm.ErrMaxCodeSizeExceeded) {
+		if err := m.vm.ValidateRuntimeCode(ret); err != nil {
+			statedb.RevertToSnapshot(snapshot)
+			statedb.SetNonce(msg.From, nonce+1)
+			return &ExecutionResult{GasUsed: gas, Err: err}
+		}
+	}
+
+	result := &ExecutionResult{ReturnData: ret, GasUsed: gasUsed, Err: err}
+	if err == nil {
+		result.ContractAddress = address
+		setContractOwner(statedb, address, msg.From)
+	}
+	return result
+}
+
+// applyCall executes the contract the message is sent to. A message without data sent to
+// an account without code is a plain value transfer.
+func (m *Manager) applyCall(statedb *StateDB, msg *Message, gas uint64) *ExecutionResult {
+	if IsSystemContract(*msg.To) {
+		return m.applySystemCall(statedb, msg, gas)
+	}
+	if !ContractAllowed(statedb, *msg.To) {
+		return &ExecutionResult{Err: ErrContractNotAllowed}
+	}
+
+	if !ContractEnabled(statedb, *msg.To) {
+		return &ExecutionResult{Err: ErrContractDisabled}
+	}
+
+	// Kodu olmayan hesaplara yalnızca değer transferi yapılabilir
+	if statedb.GetCodeSize(*msg.To) == 0 && len(msg.Data) > 0 {
+		return &ExecutionResult{Err: errors.New("no contract code at address")}
+	}
+
+	ret, gasUsed, err := m.vm.Call(statedb, msg.executionContext(), msg.From, *msg.To, msg.Data, bigOrZero(msg.Value), gas)
+	return &ExecutionResult{ReturnData: ret, GasUsed: gasUsed, Err: err}
+}
+
+// bigOrZero returns the value, or zero if it is nil
+func bigOrZero(value *big.Int) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	return value
+}
@@ -0,0 +1,73 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Contract access storage slot 5 holds mapping(contract => metadata hash), committing the
+// metadata of the contract records kept by the manager to the state root
+const metadataMappingSlot = 5
+
+// contractMetadata is the part of a contract record set by its deployment and upgrade
+// transactions. The code, owner, enabled flag and roles are kept in the state itself.
+type contractMetadata struct {
+	Name      string                  `json:"name"`
+	Version   string                  `json:"version"`
+	Timestamp int64                   `json:"timestamp"`
+	Versions  []ImplementationVersion `json:"versions"`
+}
+
+// MetadataHash returns the hash committing the metadata and the ABI of the contract record
+func (c *Contract) MetadataHash() common.Hash {
+	// Alanlar her zaman kodlanabilir; ABI ham baytlarıyla ayrıca hash'lenir
+	data, _ := json.Marshal(&contractMetadata{
+		Name:      c.Name,
+		Version:   c.Version,
+		Timestamp: c.Timestamp,
+		Versions:  c.Versions,
+	})
+	return crypto.Keccak256Hash(data, c.ABI)
+}
+
+// SetContractMetadata commits the metadata of the contract record to the state
+func SetContractMetadata(statedb vm.StateDB, contract *Contract) {
+	statedb.SetState(ContractAccessAddress, mappingSlot(contract.Address, metadataMappingSlot), contract.MetadataHash())
+}
+
+// VerifyContractRecord checks a contract record received from a peer against the state.
+// It returns a copy with the owner, enabled flag and roles taken from the state; verified
+// sources are local to a node and are dropped.
+func VerifyContractRecord(statedb vm.StateDB, contract *Contract) (*Contract, error) {
+	code := statedb.GetCode(contract.Address)
+	if len(code) == 0 || !bytes.Equal(code, contract.Code) {
+		return nil, errors.New("contract code does not match the state")
+	}
+	committed := statedb.GetState(ContractAccessAddress, mappingSlot(contract.Address, metadataMappingSlot))
+	if committed == (common.Hash{}) || committed != contract.MetadataHash() {
+		return nil, errors.New("contract metadata does not match the state")
+	}
+	if contract.IsProxy() != IsProxyAddress(statedb, contract.Address) {
+		return nil, errors.New("contract proxy flag does not match the state")
+	}
+
+	verified := *contract
+	verified.Owner = ContractOwner(statedb, contract.Address)
+	verified.IsEnabled = ContractEnabled(statedb, contract.Address)
+	verified.Source = nil
+	verified.Roles = nil
+	for account := range contract.Roles {
+		if role := ContractRole(statedb, contract.Address, account); role != "" {
+			if verified.Roles == nil {
+				verified.Roles = make(map[common.Address]Role)
+			}
+			verified.Roles[account] = role
+		}
+	}
+	return &verified, nil
+}
@@ -0,0 +1,166 @@
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ImplementationVersion is an implementation a proxy pointed to
+type ImplementationVersion struct {
+	Version        string         `json:"version"`
+	Implementation common.Address `json:"implementation"`
+	BlockHeight    uint64         `json:"block_height"`
+	TxHash         string         `json:"tx_hash"`
+	Timestamp      int64          `json:"timestamp"`
+}
+
+// IsProxy reports whether the contract is an upgradeable proxy
+func (c *Contract) IsProxy() bool {
+	return len(c.Versions) > 0
+}
+
+// Proxy registry storage of the contract access system contract: mapping(string name =>
+// proxy) at slot 3 and mapping(address => isProxy) at slot 4
+const (
+	proxyNameMappingSlot = 3
+	proxyMappingSlot     = 4
+)
+
+// proxyNameSlot returns the storage slot of the proxy registered under the name
+func proxyNameSlot(name string) common.Hash {
+	return crypto.Keccak256Hash([]byte(name), common.BigToHash(big.NewInt(proxyNameMappingSlot)).Bytes())
+}
+
+// ProxyAddress returns the proxy registered under the name, the zero address if there is none
+func ProxyAddress(statedb vm.StateDB, name string) common.Address {
+	return common.BytesToAddress(statedb.GetState(ContractAccessAddress, proxyNameSlot(name)).Bytes())
+}
+
+// IsProxyAddress reports whether the address is a proxy created by a proxy transaction
+func IsProxyAddress(statedb vm.StateDB, address common.Address) bool {
+	return statedb.GetState(ContractAccessAddress, mappingSlot(address, proxyMappingSlot)) != (common.Hash{})
+}
+
+// registerProxy records a new proxy under its name
+func registerProxy(statedb vm.StateDB, name string, address common.Address) {
+	statedb.SetState(ContractAccessAddress, proxyNameSlot(name), common.BytesToHash(address.Bytes()))
+	statedb.SetState(ContractAccessAddress, mappingSlot(address, proxyMappingSlot), boolHash(true))
+}
+
+// applyProxy creates a proxy, or repoints an existing proxy, to the implementation of the
+// message. The runtime code of the implementation is copied to the proxy address, which
+// keeps its own storage and balance across upgrades. New proxies are registered under a
+// name no other proxy has; only the proxy owner can upgrade it.
+func (m *Manager) applyProxy(statedb *StateDB, msg *Message, gas uint64) *ExecutionResult {
+	nonce := statedb.GetNonce(msg.From)
+	statedb.SetNonce(msg.From, nonce+1)
+
+	if bigOrZero(msg.Value).Sign() != 0 {
+		return &ExecutionResult{Err: errors.New("proxy operations cannot transfer value")}
+	}
+	code := statedb.GetCode(*msg.Implementation)
+	if len(code) == 0 {
+		return &ExecutionResult{Err: errors.New("no contract code at implementation address")}
+	}
+
+	var address common.Address
+	if msg.IsDeployment() {
+		if !DeployerAllowed(statedb, msg.From) {
+			return &ExecutionResult{Err: ErrDeployerNotAllowed}
+		}
+		if msg.Name == "" {
+			return &ExecutionResult{Err: errors.New("proxy requires a contract name")}
+		}
+		if ProxyAddress(statedb, msg.Name) != (common.Address{}) {
+			return &ExecutionResult{Err: fmt.Errorf("contract name %s is already registered", msg.Name)}
+		}
+		address = crypto.CreateAddress(msg.From, nonce)
+		if statedb.GetNonce(address) != 0 || statedb.GetCodeSize(address) != 0 {
+			return &ExecutionResult{GasUsed: gas, Err: vm.ErrContractAddressCollision}
+		}
+	} else {
+		address = *msg.To
+		if err := checkProxyOwner(statedb, address, msg.From); err != nil {
+			return &ExecutionResult{Err: err}
+		}
+	}
+
+	// Kod kopyalama, deploy sırasındaki kod depolama ücretiyle ücretlendirilir
+	codeGas := uint64(len(code)) * params.CreateDataGas
+	if gas < codeGas {
+		return &ExecutionResult{GasUsed: gas, Err: vm.ErrOutOfGas}
+	}
+
+	if msg.IsDeployment() {
+		statedb.CreateAccount(address)
+		setContractOwner(statedb, address, msg.From)
+		registerProxy(statedb, msg.Name, address)
+	}
+	statedb.SetCode(address, code)
+	return &ExecutionResult{ContractAddress: address, GasUsed: codeGas}
+}
+
+// checkProxyOwner verifies that the address is a proxy owned by the account
+func checkProxyOwner(statedb vm.StateDB, address, owner common.Address) error {
+	if !IsProxyAddress(statedb, address) {
+		return errors.New("contract is not a proxy")
+	}
+	if ContractOwner(statedb, address) != owner {
+		return errors.New("only the contract owner can upgrade it")
+	}
+	return nil
+}
+
+// GetProxy returns the proxy registered under the logical contract name
+func (m *Manager) GetProxy(name string) (*Contract, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	address, exists := m.registry[name]
+	if !exists {
+		return nil, fmt.Errorf("no contract registered as %s", name)
+	}
+	return m.contracts[address], nil
+}
+
+// RecordUpgrade records a new implementation of a proxy. The proxy takes over the code,
+// version and ABI of the implementation.
+func (m *Manager) RecordUpgrade(address common.Address, version ImplementationVersion, code []byte, contractABI json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	contract, exists := m.contracts[address]
+	if !exists || !contract.IsProxy() {
+		return errors.New("contract is not a proxy")
+	}
+	m.contracts[address] = contract.WithUpgrade(version, code, contractABI)
+	return nil
+}
+
+// WithUpgrade returns a copy of the proxy record pointing to the new implementation
+func (c *Contract) WithUpgrade(version ImplementationVersion, code []byte, contractABI json.RawMessage) *Contract {
+	upgraded := *c
+	upgraded.Code = code
+	upgraded.Version = version.Version
+	upgraded.ABI = contractABI
+	upgraded.Versions = append(append(make([]ImplementationVersion, 0, len(c.Versions)+1), c.Versions...), version)
+	return &upgraded
+}
+
+// registerProxyLocked maps the name of a proxy to its address; the first proxy
+// registered under a name keeps it. Caller must hold the lock.
+func (m *Manager) registerProxyLocked(contract *Contract) {
+	if !contract.IsProxy() || contract.Name == "" {
+		return
+	}
+	if _, exists := m.registry[contract.Name]; !exists {
+		m.registry[contract.Name] = contract.Address
+	}
+}
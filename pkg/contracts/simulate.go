@@ -0,0 +1,87 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Simulate applies the message to the given state without committing it, like eth_call.
+// A zero gas limit defaults to the block gas limit.
+func (m *Manager) Simulate(state *WorldState, msg *Message) *ExecutionResult {
+	cpy := *msg
+	if cpy.GasLimit == 0 || cpy.GasLimit > m.vm.GasCap() {
+		cpy.GasLimit = m.vm.GasCap()
+	}
+	return m.ApplyMessage(NewStateDB(state), &cpy)
+}
+
+// EstimateGas finds the lowest gas limit the message succeeds with by binary search
+// between the intrinsic gas and the message gas limit (or the block gas limit).
+func (m *Manager) EstimateGas(state *WorldState, msg *Message) (uint64, error) {
+	hi := msg.GasLimit
+	if hi == 0 || hi > m.vm.GasCap() {
+		hi = m.vm.GasCap()
+	}
+
+	intrinsicGas, err := m.IntrinsicGas(msg.Data, msg.IsDeployment(), msg.Block)
+	if err != nil {
+		return 0, err
+	}
+	if hi < intrinsicGas {
+		return 0, fmt.Errorf("%w: have %d, want %d", ErrIntrinsicGas, hi, intrinsicGas)
+	}
+
+	// Gas fiyatı verilmişse gönderenin bakiyesi üst sınırı belirler
+	if price := bigOrZero(msg.GasPrice); price.Sign() > 0 {
+		available := state.GetBalance(msg.From)
+		available.Sub(available, bigOrZero(msg.Value))
+		if available.Sign() < 0 {
+			return 0, ErrInsufficientFunds
+		}
+		if allowance := available.Div(available, price); allowance.IsUint64() && allowance.Uint64() < hi {
+			hi = allowance.Uint64()
+		}
+	}
+
+	run := func(gas uint64) *ExecutionResult {
+		cpy := *msg
+		cpy.GasLimit = gas
+		return m.ApplyMessage(NewStateDB(state), &cpy)
+	}
+
+	// En yüksek limitte başarısız olan mesaj hiçbir limitte başarılı olmaz
+	result := run(hi)
+	if result.Failed() {
+		return 0, result.Err
+	}
+
+	// Refund öncesi harcanan gas'tan azıyla mesaj başarılı olamaz
+	lo := intrinsicGas - 1
+	if used := result.GasUsed + result.RefundedGas; used > 0 && used-1 > lo {
+		lo = used - 1
+	}
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		if run(mid).Failed() {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi, nil
+}
+
+// revertError adds the decoded revert reason to a reverted execution's error
+func revertError(result *ExecutionResult) error {
+	if !errors.Is(result.Err, vm.ErrExecutionReverted) {
+		return result.Err
+	}
+	reason, err := abi.UnpackRevert(result.ReturnData)
+	if err != nil {
+		return result.Err
+	}
+	return fmt.Errorf("%w: %s", result.Err, reason)
+}
@@ -0,0 +1,130 @@
+package contracts
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// deployCounter sayaç kontratını deploy edip state'e yazar
+func deployCounter(t *testing.T, manager *Manager, sender common.Address) common.Address {
+	statedb := NewStateDB(manager.State())
+	args := common.BigToHash(big.NewInt(0)).Bytes()
+	result := manager.ApplyMessage(statedb, &Message{From: sender, Data: append(initCode(counterCode), args...), GasLimit: 200000})
+	if result.Failed() {
+		t.Fatalf("Kontrat deploy edilemedi: %v", result.Err)
+	}
+	statedb.Commit()
+	return result.ContractAddress
+}
+
+// TestSimulate simülasyonun state'i değiştirmediğini test eder
+func TestSimulate(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+	address := deployCounter(t, manager, sender)
+	root := manager.State().Root()
+
+	for i := 0; i < 2; i++ {
+		result := manager.Simulate(manager.State(), &Message{From: sender, To: &address})
+		if result.Failed() {
+			t.Fatalf("Simülasyon başarısız: %v", result.Err)
+		}
+		if got := new(big.Int).SetBytes(result.ReturnData); got.Int64() != 1 {
+			t.Errorf("Simülasyon sonucu hatalı: %d", got)
+		}
+	}
+
+	if manager.State().Root() != root {
+		t.Error("Simülasyon state'i değiştirdi")
+	}
+}
+
+// TestTransactionContext çağrıların kendi gönderen, değer ve gas fiyatıyla çalıştığını ve
+// eşzamanlı simülasyonların birbirini etkilemediğini test eder
+func TestTransactionContext(t *testing.T) {
+	manager := NewManager()
+	deployer := common.HexToAddress("0x1234567890")
+
+	// CALLER, ORIGIN, CALLVALUE, GASPRICE ve ADDRESS değerlerini döndüren kontrat
+	runtime := common.FromHex("3360005232602052346040523a60605230608052" + "60a06000f3")
+	statedb := NewStateDB(manager.State())
+	args := common.BigToHash(big.NewInt(0)).Bytes()
+	deployed := manager.ApplyMessage(statedb, &Message{From: deployer, Data: append(initCode(runtime), args...), GasLimit: 200000})
+	if deployed.Failed() {
+		t.Fatalf("Kontrat deploy edilemedi: %v", deployed.Err)
+	}
+	statedb.Commit()
+	address := deployed.ContractAddress
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 8; i++ {
+		sender := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+		manager.State().SetAccount(sender, &Account{Balance: big.NewInt(1e18)})
+
+		wg.Add(1)
+		go func(i int, sender common.Address) {
+			defer wg.Done()
+			result := manager.Simulate(manager.State(), &Message{
+				From:     sender,
+				To:       &address,
+				Value:    big.NewInt(int64(i)),
+				GasLimit: 100000,
+				GasPrice: big.NewInt(int64(i * 10)),
+			})
+			if result.Failed() || len(result.ReturnData) != 160 {
+				t.Errorf("Çağrı başarısız: %v", result.Err)
+				return
+			}
+
+			word := func(i int) []byte { return result.ReturnData[i*32 : (i+1)*32] }
+			if got := common.BytesToAddress(word(0)); got != sender {
+				t.Errorf("CALLER hatalı: %s", got.Hex())
+			}
+			if got := common.BytesToAddress(word(1)); got != sender {
+				t.Errorf("ORIGIN hatalı: %s", got.Hex())
+			}
+			if value := new(big.Int).SetBytes(word(2)).Int64(); value != int64(i) {
+				t.Errorf("CALLVALUE hatalı: %d", value)
+			}
+			if price := new(big.Int).SetBytes(word(3)).Int64(); price != int64(i*10) {
+				t.Errorf("GASPRICE hatalı: %d", price)
+			}
+			if got := common.BytesToAddress(word(4)); got != address {
+				t.Errorf("ADDRESS hatalı: %s", got.Hex())
+			}
+		}(i, sender)
+	}
+	wg.Wait()
+}
+
+// TestEstimateGas tahmin edilen gas'ın çağrı için yeterli olan en düşük değer olduğunu test eder
+func TestEstimateGas(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+	address := deployCounter(t, manager, sender)
+
+	msg := &Message{From: sender, To: &address}
+	gas, err := manager.EstimateGas(manager.State(), msg)
+	if err != nil {
+		t.Fatalf("Gas tahmin edilemedi: %v", err)
+	}
+
+	run := func(gasLimit uint64) *ExecutionResult {
+		return manager.Simulate(manager.State(), &Message{From: sender, To: &address, GasLimit: gasLimit})
+	}
+	if result := run(gas); result.Failed() {
+		t.Errorf("Tahmin edilen gas ile çağrı başarısız: %v", result.Err)
+	}
+	if result := run(gas - 1); !result.Failed() {
+		t.Error("Tahmin edilen gas en düşük değer değil")
+	}
+
+	// Her gas limit'inde başarısız olan deploy için tahmin hata döndürmeli
+	invalid := common.FromHex("fe") // INVALID
+	if _, err := manager.EstimateGas(manager.State(), &Message{From: sender, Data: invalid}); err == nil {
+		t.Error("Başarısız deploy için hata bekleniyordu")
+	}
+}
@@ -0,0 +1,285 @@
+package contracts
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Account represents an account in the world state
+type Account struct {
+	Nonce   uint64                      `json:"nonce"`
+	Balance *big.Int                    `json:"balance"`
+	Code    []byte                      `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// newAccount creates an empty account
+func newAccount() *Account {
+	return &Account{
+		Balance: new(big.Int),
+		Storage: make(map[common.Hash]common.Hash),
+	}
+}
+
+// copy returns a deep copy of the account
+func (a *Account) copy() *Account {
+	cpy := &Account{
+		Nonce:   a.Nonce,
+		Balance: new(big.Int),
+		Code:    common.CopyBytes(a.Code),
+		Storage: make(map[common.Hash]common.Hash, len(a.Storage)),
+	}
+	if a.Balance != nil {
+		cpy.Balance.Set(a.Balance)
+	}
+	for key, value := range a.Storage {
+		cpy.Storage[key] = value
+	}
+	return cpy
+}
+
+// CodeHash returns the keccak hash of the account's code
+func (a *Account) CodeHash() common.Hash {
+	return crypto.Keccak256Hash(a.Code)
+}
+
+// WorldState holds the committed state of all accounts. Stored accounts are never
+// modified in place, so copies of a world state share the accounts they have in common.
+type WorldState struct {
+	accounts map[common.Address]*Account
+	mu       sync.RWMutex
+}
+
+// NewWorldState creates an empty world state
+func NewWorldState() *WorldState {
+	return &WorldState{
+		accounts: make(map[common.Address]*Account),
+	}
+}
+
+// GetAccount returns a copy of the account, or nil if it does not exist
+func (ws *WorldState) GetAccount(address common.Address) *Account {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	account, exists := ws.accounts[address]
+	if !exists {
+		return nil
+	}
+	return account.copy()
+}
+
+// getStorage returns a single committed storage slot
+func (ws *WorldState) getStorage(address common.Address, key common.Hash) common.Hash {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	if account, exists := ws.accounts[address]; exists {
+		return account.Storage[key]
+	}
+	return common.Hash{}
+}
+
+// GetStorage returns a committed storage slot, or zero if it is not set
+func (ws *WorldState) GetStorage(address common.Address, key common.Hash) common.Hash {
+	return ws.getStorage(address, key)
+}
+
+// GetCode returns the committed code of an account
+func (ws *WorldState) GetCode(address common.Address) []byte {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	if account, exists := ws.accounts[address]; exists {
+		return common.CopyBytes(account.Code)
+	}
+	return nil
+}
+
+// StorageEntry is a storage slot and its value
+type StorageEntry struct {
+	Slot  common.Hash `json:"slot"`
+	Value common.Hash `json:"value"`
+}
+
+// StorageRange is a page of an account's storage in slot order
+type StorageRange struct {
+	Storage  []StorageEntry `json:"storage"`
+	NextSlot *common.Hash   `json:"next_slot,omitempty"` // First slot of the next page, nil on the last page
+}
+
+// StorageRange returns up to limit storage slots of the account starting at the given
+// slot, ordered by slot
+func (ws *WorldState) StorageRange(address common.Address, start common.Hash, limit int) *StorageRange {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	result := &StorageRange{Storage: []StorageEntry{}}
+	account, exists := ws.accounts[address]
+	if !exists {
+		return result
+	}
+
+	keys := make([]common.Hash, 0, len(account.Storage))
+	for key := range account.Storage {
+		if bytes.Compare(key.Bytes(), start.Bytes()) >= 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i].Bytes(), keys[j].Bytes()) < 0
+	})
+
+	for i, key := range keys {
+		if i == limit {
+			next := key
+			result.NextSlot = &next
+			break
+		}
+		result.Storage = append(result.Storage, StorageEntry{Slot: key, Value: account.Storage[key]})
+	}
+	return result
+}
+
+// GetBalance returns the committed balance of an account
+func (ws *WorldState) GetBalance(address common.Address) *big.Int {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	if account, exists := ws.accounts[address]; exists {
+		return new(big.Int).Set(account.Balance)
+	}
+	return new(big.Int)
+}
+
+// SetAccount stores a copy of the account, a nil account deletes it
+func (ws *WorldState) SetAccount(address common.Address, account *Account) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if account == nil {
+		delete(ws.accounts, address)
+		return
+	}
+	ws.accounts[address] = account.copy()
+}
+
+// Copy returns a copy of the world state sharing its accounts
+func (ws *WorldState) Copy() *WorldState {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	cpy := NewWorldState()
+	for address, account := range ws.accounts {
+		cpy.accounts[address] = account
+	}
+	return cpy
+}
+
+// Diff returns the accounts that differ in the other world state as they are there, nil
+// for the accounts it does not hold. Applying the diff turns the world state into the
+// other one. The returned accounts are shared and must not be modified.
+func (ws *WorldState) Diff(other *WorldState) map[common.Address]*Account {
+	theirs := other.Copy().accounts
+
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	// Hesaplar yerinde değiştirilmediğinden farklı işaretçiler değişen hesaplardır
+	diff := make(map[common.Address]*Account)
+	for address, account := range ws.accounts {
+		if theirs[address] != account {
+			diff[address] = theirs[address]
+		}
+	}
+	for address, account := range theirs {
+		if _, exists := ws.accounts[address]; !exists {
+			diff[address] = account
+		}
+	}
+	return diff
+}
+
+// Apply stores the accounts of a diff returned by Diff, deleting the nil ones
+func (ws *WorldState) Apply(diff map[common.Address]*Account) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	for address, account := range diff {
+		if account == nil {
+			delete(ws.accounts, address)
+			continue
+		}
+		ws.accounts[address] = account
+	}
+}
+
+// Export returns a copy of all accounts
+func (ws *WorldState) Export() map[common.Address]*Account {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	accounts := make(map[common.Address]*Account, len(ws.accounts))
+	for address, account := range ws.accounts {
+		accounts[address] = account.copy()
+	}
+	return accounts
+}
+
+// Import replaces all accounts, used when restoring state from a snapshot
+func (ws *WorldState) Import(accounts map[common.Address]*Account) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.accounts = make(map[common.Address]*Account, len(accounts))
+	for address, account := range accounts {
+		ws.accounts[address] = account.copy()
+	}
+}
+
+// Root returns a deterministic hash over all accounts and their storage
+func (ws *WorldState) Root() common.Hash {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	addresses := make([]common.Address, 0, len(ws.accounts))
+	for address := range ws.accounts {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+	})
+
+	hasher := crypto.NewKeccakState()
+	for _, address := range addresses {
+		account := ws.accounts[address]
+		hasher.Write(address.Bytes())
+		hasher.Write(common.BigToHash(new(big.Int).SetUint64(account.Nonce)).Bytes())
+		hasher.Write(common.BigToHash(account.Balance).Bytes())
+		hasher.Write(account.CodeHash().Bytes())
+		hasher.Write(common.BigToHash(big.NewInt(int64(len(account.Storage)))).Bytes())
+
+		// Storage slot'ları sıralı olarak hash'e dahil edilir
+		keys := make([]common.Hash, 0, len(account.Storage))
+		for key := range account.Storage {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return bytes.Compare(keys[i].Bytes(), keys[j].Bytes()) < 0
+		})
+		for _, key := range keys {
+			value := account.Storage[key]
+			hasher.Write(key.Bytes())
+			hasher.Write(value.Bytes())
+		}
+	}
+
+	var root common.Hash
+	hasher.Read(root[:])
+	return root
+}
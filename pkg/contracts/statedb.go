@@ -0,0 +1,436 @@
+package contracts
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// stateObject is an account being modified during execution
+type stateObject struct {
+	account  *Account
+	dirty    bool // Modified during execution, written back by Finalise
+	suicided bool
+}
+
+// StateDB implements vm.StateDB on top of the world state. Changes are kept
+// in memory until Commit writes them back.
+type StateDB struct {
+	world   *WorldState
+	objects map[common.Address]*stateObject
+
+	// journal undo işlemlerini snapshot'lara geri dönmek için tutar
+	journal []func()
+
+	refund    uint64
+	logs      []*types.Log
+	preimages map[common.Hash][]byte
+
+	accessAddresses map[common.Address]struct{}
+	accessSlots     map[common.Address]map[common.Hash]struct{}
+	transient       map[common.Address]map[common.Hash]common.Hash
+
+	txHash  common.Hash
+	txIndex int
+}
+
+// NewStateDB creates a new state database over the given world state
+func NewStateDB(world *WorldState) *StateDB {
+	return &StateDB{
+		world:           world,
+		objects:         make(map[common.Address]*stateObject),
+		preimages:       make(map[common.Hash][]byte),
+		accessAddresses: make(map[common.Address]struct{}),
+		accessSlots:     make(map[common.Address]map[common.Hash]struct{}),
+		transient:       make(map[common.Address]map[common.Hash]common.Hash),
+	}
+}
+
+// SetTxContext sets the transaction hash and index attached to emitted logs
+func (s *StateDB) SetTxContext(txHash common.Hash, txIndex int) {
+	s.txHash = txHash
+	s.txIndex = txIndex
+}
+
+// getObject returns the live state object, loading it from the world state if needed
+func (s *StateDB) getObject(address common.Address) *stateObject {
+	if obj, exists := s.objects[address]; exists {
+		return obj
+	}
+
+	account := s.world.GetAccount(address)
+	if account == nil {
+		return nil
+	}
+	obj := &stateObject{account: account}
+	s.objects[address] = obj
+	return obj
+}
+
+// getOrNewObject returns the live state object to modify, creating an empty account if needed
+func (s *StateDB) getOrNewObject(address common.Address) *stateObject {
+	if obj := s.getObject(address); obj != nil {
+		obj.dirty = true
+		return obj
+	}
+	return s.createObject(address)
+}
+
+// createObject replaces the account at the address with an empty one
+func (s *StateDB) createObject(address common.Address) *stateObject {
+	prev, existed := s.objects[address]
+	obj := &stateObject{account: newAccount(), dirty: true}
+	s.objects[address] = obj
+
+	s.journal = append(s.journal, func() {
+		if existed {
+			s.objects[address] = prev
+		} else {
+			delete(s.objects, address)
+		}
+	})
+	return obj
+}
+
+// CreateAccount creates a new account, keeping the balance of an existing one
+func (s *StateDB) CreateAccount(address common.Address) {
+	prev := s.getObject(address)
+	obj := s.createObject(address)
+	if prev != nil {
+		obj.account.Balance.Set(prev.account.Balance)
+	}
+}
+
+// SubBalance subtracts an amount from the account's balance
+func (s *StateDB) SubBalance(address common.Address, amount *big.Int) {
+	if amount.Sign() == 0 {
+		return
+	}
+	obj := s.getOrNewObject(address)
+	s.setBalance(obj, new(big.Int).Sub(obj.account.Balance, amount))
+}
+
+// AddBalance adds an amount to the account's balance
+func (s *StateDB) AddBalance(address common.Address, amount *big.Int) {
+	obj := s.getOrNewObject(address)
+	s.setBalance(obj, new(big.Int).Add(obj.account.Balance, amount))
+}
+
+// setBalance sets the balance of a state object and journals the change
+func (s *StateDB) setBalance(obj *stateObject, balance *big.Int) {
+	prev := new(big.Int).Set(obj.account.Balance)
+	obj.account.Balance = balance
+	s.journal = append(s.journal, func() { obj.account.Balance = prev })
+}
+
+// GetBalance returns the account's balance
+func (s *StateDB) GetBalance(address common.Address) *big.Int {
+	if obj := s.getObject(address); obj != nil {
+		return new(big.Int).Set(obj.account.Balance)
+	}
+	return new(big.Int)
+}
+
+// GetNonce returns the account's nonce
+func (s *StateDB) GetNonce(address common.Address) uint64 {
+	if obj := s.getObject(address); obj != nil {
+		return obj.account.Nonce
+	}
+	return 0
+}
+
+// SetNonce sets the account's nonce
+func (s *StateDB) SetNonce(address common.Address, nonce uint64) {
+	obj := s.getOrNewObject(address)
+	prev := obj.account.Nonce
+	obj.account.Nonce = nonce
+	s.journal = append(s.journal, func() { obj.account.Nonce = prev })
+}
+
+// GetCodeHash returns the hash of the account's code, or the zero hash for missing accounts
+func (s *StateDB) GetCodeHash(address common.Address) common.Hash {
+	obj := s.getObject(address)
+	if obj == nil {
+		return common.Hash{}
+	}
+	return obj.account.CodeHash()
+}
+
+// GetCode returns the account's code
+func (s *StateDB) GetCode(address common.Address) []byte {
+	if obj := s.getObject(address); obj != nil {
+		return obj.account.Code
+	}
+	return nil
+}
+
+// SetCode sets the account's code
+func (s *StateDB) SetCode(address common.Address, code []byte) {
+	obj := s.getOrNewObject(address)
+	prev := obj.account.Code
+	obj.account.Code = common.CopyBytes(code)
+	s.journal = append(s.journal, func() { obj.account.Code = prev })
+}
+
+// GetCodeSize returns the size of the account's code
+func (s *StateDB) GetCodeSize(address common.Address) int {
+	return len(s.GetCode(address))
+}
+
+// AddRefund adds gas to the refund counter
+func (s *StateDB) AddRefund(gas uint64) {
+	prev := s.refund
+	s.refund += gas
+	s.journal = append(s.journal, func() { s.refund = prev })
+}
+
+// SubRefund removes gas from the refund counter
+func (s *StateDB) SubRefund(gas uint64) {
+	prev := s.refund
+	if gas > s.refund {
+		panic("refund counter below zero")
+	}
+	s.refund -= gas
+	s.journal = append(s.journal, func() { s.refund = prev })
+}
+
+// GetRefund returns the current value of the refund counter
+func (s *StateDB) GetRefund() uint64 {
+	return s.refund
+}
+
+// GetCommittedState returns the storage value as it is in the world state
+func (s *StateDB) GetCommittedState(address common.Address, key common.Hash) common.Hash {
+	return s.world.getStorage(address, key)
+}
+
+// GetState returns the current storage value
+func (s *StateDB) GetState(address common.Address, key common.Hash) common.Hash {
+	if obj := s.getObject(address); obj != nil {
+		return obj.account.Storage[key]
+	}
+	return common.Hash{}
+}
+
+// SetState sets a storage value, zero values delete the slot
+func (s *StateDB) SetState(address common.Address, key, value common.Hash) {
+	obj := s.getOrNewObject(address)
+	prev, existed := obj.account.Storage[key]
+
+	if value == (common.Hash{}) {
+		delete(obj.account.Storage, key)
+	} else {
+		obj.account.Storage[key] = value
+	}
+
+	s.journal = append(s.journal, func() {
+		if existed {
+			obj.account.Storage[key] = prev
+		} else {
+			delete(obj.account.Storage, key)
+		}
+	})
+}
+
+// GetTransientState returns a transient storage value (EIP-1153)
+func (s *StateDB) GetTransientState(address common.Address, key common.Hash) common.Hash {
+	return s.transient[address][key]
+}
+
+// SetTransientState sets a transient storage value (EIP-1153)
+func (s *StateDB) SetTransientState(address common.Address, key, value common.Hash) {
+	prev := s.GetTransientState(address, key)
+	s.setTransient(address, key, value)
+	s.journal = append(s.journal, func() { s.setTransient(address, key, prev) })
+}
+
+// setTransient writes transient storage without journaling
+func (s *StateDB) setTransient(address common.Address, key, value common.Hash) {
+	if _, exists := s.transient[address]; !exists {
+		s.transient[address] = make(map[common.Hash]common.Hash)
+	}
+	s.transient[address][key] = value
+}
+
+// Suicide marks the account as self-destructed and clears its balance
+func (s *StateDB) Suicide(address common.Address) bool {
+	obj := s.getObject(address)
+	if obj == nil {
+		return false
+	}
+
+	prevSuicided := obj.suicided
+	prevBalance := obj.account.Balance
+	obj.suicided = true
+	obj.account.Balance = new(big.Int)
+
+	s.journal = append(s.journal, func() {
+		obj.suicided = prevSuicided
+		obj.account.Balance = prevBalance
+	})
+	return true
+}
+
+// HasSuicided reports whether the account self-destructed in this execution
+func (s *StateDB) HasSuicided(address common.Address) bool {
+	if obj := s.getObject(address); obj != nil {
+		return obj.suicided
+	}
+	return false
+}
+
+// Exist reports whether the account exists, including self-destructed accounts
+func (s *StateDB) Exist(address common.Address) bool {
+	return s.getObject(address) != nil
+}
+
+// Empty reports whether the account is empty according to EIP-161
+func (s *StateDB) Empty(address common.Address) bool {
+	obj := s.getObject(address)
+	return obj == nil || (obj.account.Nonce == 0 && obj.account.Balance.Sign() == 0 && len(obj.account.Code) == 0)
+}
+
+// AddressInAccessList reports whether the address is in the access list
+func (s *StateDB) AddressInAccessList(address common.Address) bool {
+	_, ok := s.accessAddresses[address]
+	return ok
+}
+
+// SlotInAccessList reports whether the address and the slot are in the access list
+func (s *StateDB) SlotInAccessList(address common.Address, slot common.Hash) (bool, bool) {
+	_, addressOk := s.accessAddresses[address]
+	_, slotOk := s.accessSlots[address][slot]
+	return addressOk, slotOk
+}
+
+// AddAddressToAccessList adds an address to the access list
+func (s *StateDB) AddAddressToAccessList(address common.Address) {
+	if s.AddressInAccessList(address) {
+		return
+	}
+	s.accessAddresses[address] = struct{}{}
+	s.journal = append(s.journal, func() { delete(s.accessAddresses, address) })
+}
+
+// AddSlotToAccessList adds an address and a slot to the access list
+func (s *StateDB) AddSlotToAccessList(address common.Address, slot common.Hash) {
+	s.AddAddressToAccessList(address)
+	if _, ok := s.accessSlots[address][slot]; ok {
+		return
+	}
+
+	if _, exists := s.accessSlots[address]; !exists {
+		s.accessSlots[address] = make(map[common.Hash]struct{})
+	}
+	s.accessSlots[address][slot] = struct{}{}
+	s.journal = append(s.journal, func() { delete(s.accessSlots[address], slot) })
+}
+
+// Prepare resets the access list and transient storage before executing a transaction
+func (s *StateDB) Prepare(rules params.Rules, sender, coinbase common.Address, dest *common.Address, precompiles []common.Address, txAccesses types.AccessList) {
+	if rules.IsBerlin {
+		s.accessAddresses = make(map[common.Address]struct{})
+		s.accessSlots = make(map[common.Address]map[common.Hash]struct{})
+
+		s.accessAddresses[sender] = struct{}{}
+		if dest != nil {
+			s.accessAddresses[*dest] = struct{}{}
+		}
+		for _, address := range precompiles {
+			s.accessAddresses[address] = struct{}{}
+		}
+		for _, entry := range txAccesses {
+			s.accessAddresses[entry.Address] = struct{}{}
+			for _, key := range entry.StorageKeys {
+				if _, exists := s.accessSlots[entry.Address]; !exists {
+					s.accessSlots[entry.Address] = make(map[common.Hash]struct{})
+				}
+				s.accessSlots[entry.Address][key] = struct{}{}
+			}
+		}
+		if rules.IsShanghai {
+			s.accessAddresses[coinbase] = struct{}{}
+		}
+	}
+	s.transient = make(map[common.Address]map[common.Hash]common.Hash)
+}
+
+// Snapshot returns an identifier for the current revision of the state
+func (s *StateDB) Snapshot() int {
+	return len(s.journal)
+}
+
+// RevertToSnapshot reverts all changes made since the given revision
+func (s *StateDB) RevertToSnapshot(revision int) {
+	for i := len(s.journal) - 1; i >= revision; i-- {
+		s.journal[i]()
+	}
+	s.journal = s.journal[:revision]
+}
+
+// AddLog records a log emitted during execution
+func (s *StateDB) AddLog(log *types.Log) {
+	log.TxHash = s.txHash
+	log.TxIndex = uint(s.txIndex)
+	log.Index = uint(len(s.logs))
+	s.logs = append(s.logs, log)
+
+	s.journal = append(s.journal, func() { s.logs = s.logs[:len(s.logs)-1] })
+}
+
+// Logs returns the logs emitted during execution
+func (s *StateDB) Logs() []*types.Log {
+	return s.logs
+}
+
+// AddPreimage records the preimage of a SHA3 hash
+func (s *StateDB) AddPreimage(hash common.Hash, preimage []byte) {
+	if _, exists := s.preimages[hash]; !exists {
+		s.preimages[hash] = common.CopyBytes(preimage)
+	}
+}
+
+// ForEachStorage iterates over the current storage of an account
+func (s *StateDB) ForEachStorage(address common.Address, cb func(key, value common.Hash) bool) error {
+	obj := s.getObject(address)
+	if obj == nil {
+		return nil
+	}
+	for key, value := range obj.account.Storage {
+		if !cb(key, value) {
+			break
+		}
+	}
+	return nil
+}
+
+// Commit writes all changes back to the world state and clears the journal.
+// Self-destructed and empty accounts (EIP-161) are removed.
+func (s *StateDB) Commit() common.Hash {
+	s.Finalise()
+	return s.world.Root()
+}
+
+// Finalise writes the changes of the current transaction to the world state without
+// computing the root, so that the next transaction sees them as committed state.
+func (s *StateDB) Finalise() {
+	for address, obj := range s.objects {
+		// Yalnızca okunan hesaplar world state'te paylaşılmaya devam eder
+		if !obj.dirty && !obj.suicided {
+			continue
+		}
+		account := obj.account
+		empty := account.Nonce == 0 && account.Balance.Sign() == 0 && len(account.Code) == 0 && len(account.Storage) == 0
+		if obj.suicided || empty {
+			s.world.SetAccount(address, nil)
+			continue
+		}
+		s.world.SetAccount(address, obj.account)
+	}
+
+	s.objects = make(map[common.Address]*stateObject)
+	s.journal = nil
+	s.refund = 0
+}
@@ -0,0 +1,252 @@
+package contracts
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// counterCode slot 0'ı bir artırır ve yeni değeri döndürür
+var counterCode = common.FromHex("6000546001018060005560005260206000f3")
+
+// initCode runtime kodu döndüren bir constructor üretir. Constructor, kodun sonuna
+// eklenen 32 byte'lık argümanı slot 0'a yazar.
+func initCode(runtime []byte) []byte {
+	const constructorSize = 25
+	argOffset := byte(constructorSize + len(runtime))
+	code := []byte{
+		0x60, 0x20, 0x60, argOffset, 0x60, 0x00, 0x39, // CODECOPY(0, argOffset, 32)
+		0x60, 0x00, 0x51, 0x60, 0x00, 0x55, // SSTORE(0, MLOAD(0))
+		0x60, byte(len(runtime)), 0x60, constructorSize, 0x60, 0x00, 0x39, // CODECOPY(0, constructorSize, len)
+		0x60, byte(len(runtime)), 0x60, 0x00, 0xf3, // RETURN(0, len)
+	}
+	return append(code, runtime...)
+}
+
+// TestContractStoragePersists kontrat storage'ının çağrılar arasında korunduğunu test eder
+func TestContractStoragePersists(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+
+	statedb := NewStateDB(manager.State())
+	args := common.BigToHash(big.NewInt(0)).Bytes()
+	deploy := manager.ApplyMessage(statedb, &Message{From: sender, Data: append(initCode(counterCode), args...), GasLimit: 200000, Timestamp: time.Now().Unix()})
+	if deploy.Failed() {
+		t.Fatalf("Kontrat deploy edilemedi: %v", deploy.Err)
+	}
+	statedb.Commit()
+	address := deploy.ContractAddress
+
+	for i := int64(1); i <= 3; i++ {
+		statedb := NewStateDB(manager.State())
+		result := manager.ApplyMessage(statedb, &Message{From: sender, To: &address, GasLimit: 100000})
+		if result.Failed() {
+			t.Fatalf("Kontrat çalıştırılamadı: %v", result.Err)
+		}
+		statedb.Commit()
+
+		if got := new(big.Int).SetBytes(result.ReturnData); got.Int64() != i {
+			t.Errorf("Sayaç değeri yanlış: beklenen %d, alınan %d", i, got)
+		}
+	}
+
+	account := manager.State().GetAccount(address)
+	if account == nil {
+		t.Fatal("Kontrat hesabı world state'te bulunamadı")
+	}
+	if value := account.Storage[common.Hash{}]; value != common.BigToHash(big.NewInt(3)) {
+		t.Errorf("Storage değeri yanlış: %s", value.Hex())
+	}
+	if nonce := manager.State().GetAccount(sender).Nonce; nonce != 4 {
+		t.Errorf("Gönderen nonce'u yanlış: %d", nonce)
+	}
+
+	// Salt okunur çağrı state'i değiştirmemeli
+	if result := manager.Simulate(manager.State(), &Message{From: sender, To: &address}); result.Failed() {
+		t.Fatalf("Kontrat çağrılamadı: %v", result.Err)
+	}
+	if value := manager.State().GetAccount(address).Storage[common.Hash{}]; value != common.BigToHash(big.NewInt(3)) {
+		t.Errorf("Salt okunur çağrı storage'ı değiştirdi: %s", value.Hex())
+	}
+}
+
+// TestDeployRunsInitCode deploy sırasında constructor'ın çalıştığını ve runtime kodun saklandığını test eder
+func TestDeployRunsInitCode(t *testing.T) {
+	manager := NewManager()
+	owner := common.HexToAddress("0x1234567890")
+	args := common.BigToHash(big.NewInt(41)).Bytes()
+
+	contract, err := manager.DeployContract(append(initCode(counterCode), args...), owner, "Counter", "1.0", time.Now().Unix())
+	if err != nil {
+		t.Fatalf("Kontrat deploy edilemedi: %v", err)
+	}
+
+	// Adres gönderen ve nonce'tan türetilir
+	if expected := crypto.CreateAddress(owner, 0); contract.Address != expected {
+		t.Errorf("Kontrat adresi yanlış: beklenen %s, alınan %s", expected.Hex(), contract.Address.Hex())
+	}
+	if !bytes.Equal(contract.Code, counterCode) {
+		t.Errorf("Runtime kod saklanmadı: %x", contract.Code)
+	}
+
+	// Constructor argümanı storage'a yazılmış olmalı
+	result := manager.Simulate(manager.State(), &Message{From: owner, To: &contract.Address})
+	if result.Failed() {
+		t.Fatalf("Kontrat çalıştırılamadı: %v", result.Err)
+	}
+	if got := new(big.Int).SetBytes(result.ReturnData); got.Int64() != 42 {
+		t.Errorf("Constructor argümanı uygulanmadı: %d", got)
+	}
+
+	// İkinci deploy bir sonraki nonce'u kullanır
+	second, err := manager.DeployContract(append(initCode(counterCode), args...), owner, "Counter", "1.1", time.Now().Unix())
+	if err != nil {
+		t.Fatalf("İkinci kontrat deploy edilemedi: %v", err)
+	}
+	if expected := crypto.CreateAddress(owner, 1); second.Address != expected {
+		t.Errorf("İkinci kontrat adresi yanlış: %s", second.Address.Hex())
+	}
+}
+
+// TestDeployCreate2 salt verildiğinde CREATE2 adresinin kullanıldığını test eder
+func TestDeployCreate2(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+	salt := common.HexToHash("0x2a")
+	code := append(initCode(counterCode), make([]byte, 32)...)
+
+	statedb := NewStateDB(manager.State())
+	result := manager.ApplyMessage(statedb, &Message{From: sender, Data: code, Salt: &salt, GasLimit: 200000})
+	if result.Failed() {
+		t.Fatalf("Kontrat deploy edilemedi: %v", result.Err)
+	}
+
+	expected := crypto.CreateAddress2(sender, salt, crypto.Keccak256(code))
+	if result.ContractAddress != expected {
+		t.Errorf("CREATE2 adresi yanlış: beklenen %s, alınan %s", expected.Hex(), result.ContractAddress.Hex())
+	}
+
+	// Aynı salt ile ikinci deploy çakışmalı
+	if again := manager.ApplyMessage(statedb, &Message{From: sender, Data: code, Salt: &salt, GasLimit: 200000}); !again.Failed() {
+		t.Error("Aynı salt ile ikinci deploy başarılı oldu")
+	}
+}
+
+// TestStateDBRevert snapshot'a geri dönüldüğünde değişikliklerin geri alındığını test eder
+func TestStateDBRevert(t *testing.T) {
+	world := NewWorldState()
+	statedb := NewStateDB(world)
+	address := common.HexToAddress("0xabcdef")
+	key := common.HexToHash("0x01")
+
+	statedb.CreateAccount(address)
+	statedb.AddBalance(address, big.NewInt(100))
+	statedb.SetState(address, key, common.HexToHash("0x0a"))
+
+	snapshot := statedb.Snapshot()
+	statedb.AddBalance(address, big.NewInt(50))
+	statedb.SetState(address, key, common.HexToHash("0x0b"))
+	statedb.RevertToSnapshot(snapshot)
+
+	if balance := statedb.GetBalance(address); balance.Int64() != 100 {
+		t.Errorf("Bakiye geri alınmadı: %s", balance)
+	}
+	if value := statedb.GetState(address, key); value != common.HexToHash("0x0a") {
+		t.Errorf("Storage geri alınmadı: %s", value.Hex())
+	}
+
+	// Commit edilmeden önce world state değişmemeli
+	if world.GetAccount(address) != nil {
+		t.Error("Commit edilmeden world state değişti")
+	}
+
+	emptyRoot := world.Root()
+	root := statedb.Commit()
+	if root == emptyRoot {
+		t.Error("Commit sonrası state root değişmedi")
+	}
+	if balance := world.GetBalance(address); balance.Int64() != 100 {
+		t.Errorf("Commit edilen bakiye yanlış: %s", balance)
+	}
+}
+
+// TestStorageRange storage slot'larının sıralı sayfalar halinde döndürüldüğünü test eder
+func TestStorageRange(t *testing.T) {
+	world := NewWorldState()
+	statedb := NewStateDB(world)
+	address := common.HexToAddress("0x1234567890")
+	statedb.CreateAccount(address)
+	for i := int64(1); i <= 5; i++ {
+		statedb.SetState(address, common.BigToHash(big.NewInt(i)), common.BigToHash(big.NewInt(i*10)))
+	}
+	statedb.Commit()
+
+	page := world.StorageRange(address, common.Hash{}, 2)
+	if len(page.Storage) != 2 || page.Storage[0].Slot != common.BigToHash(big.NewInt(1)) || page.Storage[1].Value != common.BigToHash(big.NewInt(20)) {
+		t.Errorf("İlk sayfa hatalı: %+v", page.Storage)
+	}
+	if page.NextSlot == nil || *page.NextSlot != common.BigToHash(big.NewInt(3)) {
+		t.Fatalf("Sonraki slot hatalı: %v", page.NextSlot)
+	}
+
+	// Son sayfada sonraki slot yoktur
+	page = world.StorageRange(address, *page.NextSlot, 10)
+	if len(page.Storage) != 3 || page.NextSlot != nil {
+		t.Errorf("Son sayfa hatalı: %+v", page)
+	}
+
+	if page := world.StorageRange(common.HexToAddress("0x01"), common.Hash{}, 10); len(page.Storage) != 0 {
+		t.Errorf("Olmayan hesabın storage'ı boş olmalı: %+v", page)
+	}
+}
+
+// TestWorldStateDiff kopyaların değişmeyen hesapları paylaştığını ve farkın yalnızca değişen hesapları içerdiğini test eder
+func TestWorldStateDiff(t *testing.T) {
+	world := NewWorldState()
+	sender, reader, removed := common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03")
+	for _, address := range []common.Address{sender, reader, removed} {
+		world.SetAccount(address, &Account{Balance: big.NewInt(100), Storage: map[common.Hash]common.Hash{{}: common.HexToHash("0x01")}})
+	}
+
+	before := world.Root()
+
+	// Salt okunan hesap kopyada değişmeden paylaşılır
+	next := world.Copy()
+	statedb := NewStateDB(next)
+	statedb.GetState(reader, common.Hash{})
+	statedb.SubBalance(sender, big.NewInt(40))
+	statedb.Suicide(removed)
+	statedb.AddBalance(common.HexToAddress("0x04"), big.NewInt(40))
+	root := statedb.Commit()
+	if world.Root() != before {
+		t.Fatal("Kopyadaki değişiklikler önceki state'i değiştirdi")
+	}
+
+	diff := world.Diff(next)
+	if len(diff) != 3 {
+		t.Fatalf("Fark yalnızca değişen hesapları içermeli: %d hesap", len(diff))
+	}
+	if _, exists := diff[reader]; exists {
+		t.Error("Salt okunan hesap farka eklenmemeli")
+	}
+	if account, exists := diff[removed]; !exists || account != nil {
+		t.Error("Silinen hesap farkta nil olmalı")
+	}
+
+	// Ters fark kopyayı önceki haline döndürür
+	next.Apply(next.Diff(world))
+	if next.Root() != before {
+		t.Error("Ters fark uygulandıktan sonra state root'u eşleşmiyor")
+	}
+	world.Apply(diff)
+	if world.Root() != root {
+		t.Error("Fark uygulandıktan sonra state root'u eşleşmiyor")
+	}
+	if balance := world.GetBalance(sender); balance.Int64() != 60 {
+		t.Errorf("Bakiye hatalı: %s", balance)
+	}
+}
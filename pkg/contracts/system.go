@@ -0,0 +1,685 @@
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Addresses of the system contracts
+var (
+	ValidatorSetAddress      = common.HexToAddress("0x0000000000000000000000000000000000001000")
+	FeeParamsAddress         = common.HexToAddress("0x0000000000000000000000000000000000001001")
+	ContractAllowlistAddress = common.HexToAddress("0x0000000000000000000000000000000000001002")
+	DeployerAllowlistAddress = common.HexToAddress("0x0000000000000000000000000000000000001003")
+)
+
+// Gas charged for system contract calls
+const (
+	SystemReadGas  = 2000
+	SystemWriteGas = 20000
+)
+
+// ValidatorSetABI is the ABI of the validator set system contract. Validators are added with
+// their uncompressed P-256 or secp256k1 public key; the account of a secp256k1 validator is
+// its Keccak address, that of a P-256 validator the last 20 bytes of the key's SHA-256 hash.
+// Votes for changes of all system contracts are emitted by it as Voted events.
+const ValidatorSetABI = `[
+	{"type":"function","name":"getValidators","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address[]"}]},
+	{"type":"function","name":"isValidator","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"addValidator","stateMutability":"nonpayable","inputs":[{"name":"publicKey","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"removeValidator","stateMutability":"nonpayable","inputs":[{"name":"account","type":"address"}],"outputs":[]},
+	{"type":"event","name":"ValidatorAdded","inputs":[{"name":"account","type":"address","indexed":true},{"name":"publicKey","type":"bytes","indexed":false}]},
+	{"type":"event","name":"ValidatorRemoved","inputs":[{"name":"account","type":"address","indexed":true}]},
+	{"type":"event","name":"Voted","inputs":[{"name":"account","type":"address","indexed":true},{"name":"proposal","type":"bytes32","indexed":false},{"name":"votes","type":"uint256","indexed":false},{"name":"required","type":"uint256","indexed":false}]}
+]`
+
+// FeeParamsABI is the ABI of the fee parameters system contract. A zero fee recipient
+// credits fees to the block validator.
+const FeeParamsABI = `[
+	{"type":"function","name":"minGasPrice","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"feeRecipient","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"setMinGasPrice","stateMutability":"nonpayable","inputs":[{"name":"price","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"setFeeRecipient","stateMutability":"nonpayable","inputs":[{"name":"recipient","type":"address"}],"outputs":[]},
+	{"type":"event","name":"MinGasPriceChanged","inputs":[{"name":"price","type":"uint256","indexed":false}]},
+	{"type":"event","name":"FeeRecipientChanged","inputs":[{"name":"recipient","type":"address","indexed":true}]}
+]`
+
+// ContractAllowlistABI is the ABI of the contract allowlist system contract. When enabled,
+// transactions can only call contracts on the allowlist.
+const ContractAllowlistABI = `[
+	{"type":"function","name":"enabled","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"isAllowed","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"setEnabled","stateMutability":"nonpayable","inputs":[{"name":"enabled","type":"bool"}],"outputs":[]},
+	{"type":"function","name":"allow","stateMutability":"nonpayable","inputs":[{"name":"account","type":"address"}],"outputs":[]},
+	{"type":"function","name":"disallow","stateMutability":"nonpayable","inputs":[{"name":"account","type":"address"}],"outputs":[]},
+	{"type":"event","name":"AllowlistEnabled","inputs":[{"name":"enabled","type":"bool","indexed":false}]},
+	{"type":"event","name":"ContractAllowed","inputs":[{"name":"account","type":"address","indexed":true}]},
+	{"type":"event","name":"ContractDisallowed","inputs":[{"name":"account","type":"address","indexed":true}]}
+]`
+
+// DeployerAllowlistABI is the ABI of the deployer allowlist system contract. When enabled,
+// only accounts on the allowlist can deploy contracts.
+const DeployerAllowlistABI = `[
+	{"type":"function","name":"enabled","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"isAllowed","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"setEnabled","stateMutability":"nonpayable","inputs":[{"name":"enabled","type":"bool"}],"outputs":[]},
+	{"type":"function","name":"allow","stateMutability":"nonpayable","inputs":[{"name":"account","type":"address"}],"outputs":[]},
+	{"type":"function","name":"disallow","stateMutability":"nonpayable","inputs":[{"name":"account","type":"address"}],"outputs":[]},
+	{"type":"event","name":"DeployerAllowlistEnabled","inputs":[{"name":"enabled","type":"bool","indexed":false}]},
+	{"type":"event","name":"DeployerAllowed","inputs":[{"name":"account","type":"address","indexed":true}]},
+	{"type":"event","name":"DeployerDisallowed","inputs":[{"name":"account","type":"address","indexed":true}]}
+]`
+
+var (
+	// ErrNotAuthority is returned when a system contract call that changes state is not sent by a validator
+	ErrNotAuthority = errors.New("caller is not an authority")
+
+	// ErrContractNotAllowed is returned when a transaction calls a contract that is not on the allowlist
+	ErrContractNotAllowed = errors.New("contract is not on the allowlist")
+
+	// ErrDeployerNotAllowed is returned when an account that is not on the deployer allowlist deploys a contract
+	ErrDeployerNotAllowed = errors.New("account is not allowed to deploy contracts")
+)
+
+// systemContract is a native contract at a fixed address
+type systemContract struct {
+	name       string
+	definition string // ABI JSON
+	abi        abi.ABI
+	run        func(ctx *systemContext, method string, args []interface{}) ([]interface{}, error)
+	code       []byte // EVM code of the view methods, called by contracts
+}
+
+// systemContext is the environment of a system contract call
+type systemContext struct {
+	statedb  vm.StateDB
+	contract *systemContract
+	address  common.Address
+	caller   common.Address
+	input    []byte // Call data, identifies the change validators vote for
+}
+
+// systemContracts are the system contracts by address
+var systemContracts = map[common.Address]*systemContract{
+	ValidatorSetAddress:      newSystemContract("ValidatorSet", ValidatorSetABI, runValidatorSet, validatorSetViews),
+	FeeParamsAddress:         newSystemContract("FeeParams", FeeParamsABI, runFeeParams, feeParamsViews),
+	ContractAllowlistAddress: newSystemContract("ContractAllowlist", ContractAllowlistABI, runAllowlist("AllowlistEnabled", "ContractAllowed", "ContractDisallowed"), allowlistViews),
+	DeployerAllowlistAddress: newSystemContract("DeployerAllowlist", DeployerAllowlistABI, runAllowlist("DeployerAllowlistEnabled", "DeployerAllowed", "DeployerDisallowed"), allowlistViews),
+	ContractAccessAddress:    newSystemContract("ContractAccess", ContractAccessABI, runContractAccess, contractAccessViews),
+}
+
+// newSystemContract creates a system contract with the ABI definition and the EVM code of
+// its view methods
+func newSystemContract(name, definition string, run func(*systemContext, string, []interface{}) ([]interface{}, error), views systemViews) *systemContract {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("invalid %s ABI: %v", name, err))
+	}
+	return &systemContract{name: name, definition: definition, abi: parsed, run: run, code: viewCode(parsed, views)}
+}
+
+// InitSystemState installs the code of the system contracts and the validators the chain
+// starts with, given by their public keys, in a genesis state
+func InitSystemState(statedb vm.StateDB, validators [][]byte) error {
+	for address, contract := range systemContracts {
+		statedb.SetCode(address, contract.code)
+	}
+	for _, publicKey := range validators {
+		account, err := ValidatorAccount(publicKey)
+		if err != nil {
+			return err
+		}
+		if IsValidator(statedb, account) {
+			return fmt.Errorf("duplicate validator %s", account.Hex())
+		}
+		addValidator(statedb, account, publicKey)
+	}
+	return nil
+}
+
+// IsSystemContract reports whether the address is a system contract
+func IsSystemContract(address common.Address) bool {
+	_, exists := systemContracts[address]
+	return exists
+}
+
+// systemContractRecord returns the contract record of a system contract, so its ABI can be
+// used to call it and decode its events, or nil if the address is not a system contract
+func systemContractRecord(address common.Address) *Contract {
+	contract, exists := systemContracts[address]
+	if !exists {
+		return nil
+	}
+	return &Contract{
+		Address:   address,
+		Name:      contract.name,
+		Version:   "system",
+		Code:      contract.code,
+		IsEnabled: true,
+		ABI:       json.RawMessage(contract.definition),
+	}
+}
+
+// SystemContracts returns the contract records of the system contracts
+func SystemContracts() []*Contract {
+	records := make([]*Contract, 0, len(systemContracts))
+	for address := range systemContracts {
+		records = append(records, systemContractRecord(address))
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Address.Hex() < records[j].Address.Hex()
+	})
+	return records
+}
+
+// systemGas returns the gas charged for a system contract call
+func systemGas(address common.Address, input []byte) uint64 {
+	if len(input) >= 4 {
+		if method, err := systemContracts[address].abi.MethodById(input[:4]); err == nil && !method.IsConstant() {
+			return SystemWriteGas
+		}
+	}
+	return SystemReadGas
+}
+
+// runSystemCall decodes the call with the ABI of the system contract, runs it and encodes the outputs
+func runSystemCall(ctx *systemContext, input []byte) ([]byte, error) {
+	ctx.contract = systemContracts[ctx.address]
+	ctx.input = input
+	if len(input) < 4 {
+		return nil, errors.New("missing method selector")
+	}
+	method, err := ctx.contract.abi.MethodById(input[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments for %s: %v", method.Name, err)
+	}
+
+	outputs, err := ctx.contract.run(ctx, method.Name, args)
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(outputs...)
+}
+
+// applySystemCall applies a transaction sent to a system contract. Transactions are
+// dispatched here, before the EVM; contracts reach the view methods through the code of the
+// system contract account. Failed calls revert their changes and are charged the call gas.
+func (m *Manager) applySystemCall(statedb *StateDB, msg *Message, gas uint64) *ExecutionResult {
+	cost := systemGas(*msg.To, msg.Data)
+	if gas < cost {
+		return &ExecutionResult{GasUsed: gas, Err: vm.ErrOutOfGas}
+	}
+	if bigOrZero(msg.Value).Sign() != 0 {
+		return &ExecutionResult{GasUsed: cost, Err: errors.New("system contracts cannot receive value")}
+	}
+
+	snapshot := statedb.Snapshot()
+	ret, err := runSystemCall(&systemContext{statedb: statedb, address: *msg.To, caller: msg.From}, msg.Data)
+	if err != nil {
+		statedb.RevertToSnapshot(snapshot)
+	}
+	return &ExecutionResult{ReturnData: ret, GasUsed: cost, Err: err}
+}
+
+// authorize checks that the call may vote for a change: it must be a transaction sent by a validator
+func (ctx *systemContext) authorize() error {
+	if !IsValidator(ctx.statedb, ctx.caller) {
+		return ErrNotAuthority
+	}
+	return nil
+}
+
+// votedEvent is the event of the validator set emitted for every vote
+var votedEvent = func() abi.Event {
+	parsed, err := abi.JSON(strings.NewReader(ValidatorSetABI))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ValidatorSet ABI: %v", err))
+	}
+	return parsed.Events["Voted"]
+}()
+
+// approve records the caller's vote for the change the call makes and reports whether a
+// majority of the validators has voted for it, so that it is applied. Votes are cast by
+// sending the same call; they count for the current validator set only, as changing the set
+// starts every open vote over.
+func (ctx *systemContext) approve() (bool, error) {
+	version := ctx.statedb.GetState(ValidatorSetAddress, common.BigToHash(big.NewInt(validatorVersionSlot)))
+	proposal := crypto.Keccak256Hash(ctx.address.Bytes(), ctx.input, version.Bytes())
+	voteSlot := func(account common.Address) common.Hash {
+		return crypto.Keccak256Hash(common.LeftPadBytes(account.Bytes(), 32), hashMappingSlot(proposal, voteMappingSlot).Bytes())
+	}
+	if ctx.statedb.GetState(ValidatorSetAddress, voteSlot(ctx.caller)) != (common.Hash{}) {
+		return false, errors.New("validator has already voted for this change")
+	}
+
+	countSlot := hashMappingSlot(proposal, voteCountSlot)
+	votes := ctx.statedb.GetState(ValidatorSetAddress, countSlot).Big().Int64() + 1
+	validators := ValidatorAccounts(ctx.statedb)
+	required := int64(len(validators)/2 + 1)
+	if err := emitEvent(ctx.statedb, ValidatorSetAddress, votedEvent, ctx.caller, [32]byte(proposal), big.NewInt(votes), big.NewInt(required)); err != nil {
+		return false, err
+	}
+
+	if votes < required {
+		ctx.statedb.SetState(ValidatorSetAddress, voteSlot(ctx.caller), boolHash(true))
+		ctx.statedb.SetState(ValidatorSetAddress, countSlot, common.BigToHash(big.NewInt(votes)))
+		return false, nil
+	}
+
+	// Uygulanan değişikliğin oyları silinir, böylece aynı değişiklik yeniden oylanabilir
+	for _, validator := range validators {
+		ctx.statedb.SetState(ValidatorSetAddress, voteSlot(validator), common.Hash{})
+	}
+	ctx.statedb.SetState(ValidatorSetAddress, countSlot, common.Hash{})
+	return true, nil
+}
+
+// emit adds an event of the system contract. Indexed arguments must be addresses.
+func (ctx *systemContext) emit(name string, args ...interface{}) error {
+	return emitEvent(ctx.statedb, ctx.address, ctx.contract.abi.Events[name], args...)
+}
+
+// emitEvent adds an event of the system contract at the address. Indexed arguments must be addresses.
+func emitEvent(statedb vm.StateDB, address common.Address, event abi.Event, args ...interface{}) error {
+	topics := []common.Hash{event.ID}
+	var data []interface{}
+	for i, input := range event.Inputs {
+		if input.Indexed {
+			topics = append(topics, common.BytesToHash(args[i].(common.Address).Bytes()))
+		} else {
+			data = append(data, args[i])
+		}
+	}
+
+	packed, err := event.Inputs.NonIndexed().Pack(data...)
+	if err != nil {
+		return err
+	}
+	statedb.AddLog(&types.Log{Address: address, Topics: topics, Data: packed})
+	return nil
+}
+
+// ValidatorAccount returns the account of a validator from its uncompressed P-256 or
+// secp256k1 public key
+func ValidatorAccount(publicKey []byte) (common.Address, error) {
+	return validator.AccountFromPublicKey(publicKey)
+}
+
+// Validator set storage, laid out as Solidity would: address[] of the validators at slot 0,
+// mapping(address => uint256) with the position + 1 of each validator in the array at slot 1,
+// mapping(address => bytes) with their public keys at slot 2 and the version of the set,
+// incremented by every change, at slot 3. The votes for changes of the system contracts are
+// kept at slot 4 as mapping(bytes32 => mapping(address => bool)) by proposal and voter, and
+// their counts at slot 5 as mapping(bytes32 => uint256).
+const (
+	validatorListSlot    = 0
+	validatorIndexSlot   = 1
+	validatorKeySlot     = 2
+	validatorVersionSlot = 3
+	voteMappingSlot      = 4
+	voteCountSlot        = 5
+)
+
+// ValidatorAccounts returns the accounts of the validators in the state
+func ValidatorAccounts(statedb vm.StateDB) []common.Address {
+	count := statedb.GetState(ValidatorSetAddress, common.BigToHash(big.NewInt(validatorListSlot))).Big().Int64()
+	accounts := make([]common.Address, count)
+	for i := range accounts {
+		accounts[i] = common.BytesToAddress(statedb.GetState(ValidatorSetAddress, arraySlot(validatorListSlot, int64(i))).Bytes())
+	}
+	return accounts
+}
+
+// IsValidator reports whether the account is a validator in the state
+func IsValidator(statedb vm.StateDB, account common.Address) bool {
+	return statedb.GetState(ValidatorSetAddress, mappingSlot(account, validatorIndexSlot)) != (common.Hash{})
+}
+
+// ValidatorPublicKey returns the public key of a validator in the state, nil if the account
+// is not a validator
+func ValidatorPublicKey(statedb vm.StateDB, account common.Address) []byte {
+	return getBytesState(statedb, ValidatorSetAddress, mappingSlot(account, validatorKeySlot))
+}
+
+// addValidator appends a validator to the validator set in the state
+func addValidator(statedb vm.StateDB, account common.Address, publicKey []byte) {
+	lengthSlot := common.BigToHash(big.NewInt(validatorListSlot))
+	count := statedb.GetState(ValidatorSetAddress, lengthSlot).Big().Int64()
+
+	statedb.SetState(ValidatorSetAddress, arraySlot(validatorListSlot, count), common.BytesToHash(account.Bytes()))
+	statedb.SetState(ValidatorSetAddress, lengthSlot, common.BigToHash(big.NewInt(count+1)))
+	statedb.SetState(ValidatorSetAddress, mappingSlot(account, validatorIndexSlot), common.BigToHash(big.NewInt(count+1)))
+	setBytesState(statedb, ValidatorSetAddress, mappingSlot(account, validatorKeySlot), publicKey)
+	bumpValidatorVersion(statedb)
+}
+
+// removeValidator removes a validator from the validator set in the state. The last
+// validator of the array takes its position.
+func removeValidator(statedb vm.StateDB, account common.Address) {
+	lengthSlot := common.BigToHash(big.NewInt(validatorListSlot))
+	count := statedb.GetState(ValidatorSetAddress, lengthSlot).Big().Int64()
+	index := statedb.GetState(ValidatorSetAddress, mappingSlot(account, validatorIndexSlot)).Big().Int64() - 1
+
+	if index != count-1 {
+		last := statedb.GetState(ValidatorSetAddress, arraySlot(validatorListSlot, count-1))
+		statedb.SetState(ValidatorSetAddress, arraySlot(validatorListSlot, index), last)
+		statedb.SetState(ValidatorSetAddress, mappingSlot(common.BytesToAddress(last.Bytes()), validatorIndexSlot), common.BigToHash(big.NewInt(index+1)))
+	}
+	statedb.SetState(ValidatorSetAddress, arraySlot(validatorListSlot, count-1), common.Hash{})
+	statedb.SetState(ValidatorSetAddress, lengthSlot, common.BigToHash(big.NewInt(count-1)))
+	statedb.SetState(ValidatorSetAddress, mappingSlot(account, validatorIndexSlot), common.Hash{})
+	setBytesState(statedb, ValidatorSetAddress, mappingSlot(account, validatorKeySlot), nil)
+	bumpValidatorVersion(statedb)
+}
+
+// bumpValidatorVersion increments the version of the validator set after a change
+func bumpValidatorVersion(statedb vm.StateDB) {
+	slot := common.BigToHash(big.NewInt(validatorVersionSlot))
+	version := statedb.GetState(ValidatorSetAddress, slot).Big()
+	statedb.SetState(ValidatorSetAddress, slot, common.BigToHash(version.Add(version, big.NewInt(1))))
+}
+
+// runValidatorSet implements the validator set system contract. Applied changes are also
+// emitted as events, which the chain applies to its consensus validator set when the block
+// is added.
+func runValidatorSet(ctx *systemContext, method string, args []interface{}) ([]interface{}, error) {
+	switch method {
+	case "getValidators":
+		return []interface{}{ValidatorAccounts(ctx.statedb)}, nil
+
+	case "isValidator":
+		return []interface{}{IsValidator(ctx.statedb, args[0].(common.Address))}, nil
+
+	case "addValidator":
+		if err := ctx.authorize(); err != nil {
+			return nil, err
+		}
+		publicKey := args[0].([]byte)
+		account, err := ValidatorAccount(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		if IsValidator(ctx.statedb, account) {
+			return nil, errors.New("account is already a validator")
+		}
+		if approved, err := ctx.approve(); err != nil || !approved {
+			return nil, err
+		}
+		addValidator(ctx.statedb, account, publicKey)
+		return nil, ctx.emit("ValidatorAdded", account, publicKey)
+
+	case "removeValidator":
+		if err := ctx.authorize(); err != nil {
+			return nil, err
+		}
+		account := args[0].(common.Address)
+		if !IsValidator(ctx.statedb, account) {
+			return nil, errors.New("account is not a validator")
+		}
+		if len(ValidatorAccounts(ctx.statedb)) == 1 {
+			return nil, errors.New("cannot remove the last validator")
+		}
+		if approved, err := ctx.approve(); err != nil || !approved {
+			return nil, err
+		}
+		removeValidator(ctx.statedb, account)
+		return nil, ctx.emit("ValidatorRemoved", account)
+	}
+	return nil, fmt.Errorf("unknown method %s", method)
+}
+
+// PackAddValidator encodes the data of a transaction to the validator set system contract
+// adding the validator with the uncompressed public key
+func PackAddValidator(publicKey []byte) ([]byte, error) {
+	return systemContracts[ValidatorSetAddress].abi.Pack("addValidator", publicKey)
+}
+
+// PackRemoveValidator encodes the data of a transaction to the validator set system contract
+// removing the validator with the account
+func PackRemoveValidator(account common.Address) ([]byte, error) {
+	return systemContracts[ValidatorSetAddress].abi.Pack("removeValidator", account)
+}
+
+// ValidatorSetChange is a change of the validator set made by a transaction
+type ValidatorSetChange struct {
+	Account   common.Address
+	PublicKey []byte // Set for added validators
+	Added     bool
+}
+
+// DecodeValidatorSetChange decodes a log of the validator set system contract
+func DecodeValidatorSetChange(log *types.Log) (*ValidatorSetChange, bool) {
+	if log.Address != ValidatorSetAddress || len(log.Topics) != 2 {
+		return nil, false
+	}
+	contractABI := systemContracts[ValidatorSetAddress].abi
+	account := common.BytesToAddress(log.Topics[1].Bytes())
+
+	switch log.Topics[0] {
+	case contractABI.Events["ValidatorAdded"].ID:
+		values, err := contractABI.Events["ValidatorAdded"].Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil || len(values) != 1 {
+			return nil, false
+		}
+		return &ValidatorSetChange{Account: account, PublicKey: values[0].([]byte), Added: true}, true
+	case contractABI.Events["ValidatorRemoved"].ID:
+		return &ValidatorSetChange{Account: account}, true
+	}
+	return nil, false
+}
+
+// Fee parameter storage slots
+var (
+	minGasPriceSlot  = common.BigToHash(big.NewInt(0))
+	feeRecipientSlot = common.BigToHash(big.NewInt(1))
+)
+
+// runFeeParams implements the fee parameters system contract
+func runFeeParams(ctx *systemContext, method string, args []interface{}) ([]interface{}, error) {
+	switch method {
+	case "minGasPrice":
+		return []interface{}{MinGasPrice(ctx.statedb)}, nil
+
+	case "feeRecipient":
+		return []interface{}{FeeRecipient(ctx.statedb)}, nil
+
+	case "setMinGasPrice":
+		if err := ctx.authorize(); err != nil {
+			return nil, err
+		}
+		if approved, err := ctx.approve(); err != nil || !approved {
+			return nil, err
+		}
+		price := args[0].(*big.Int)
+		ctx.statedb.SetState(FeeParamsAddress, minGasPriceSlot, common.BigToHash(price))
+		return nil, ctx.emit("MinGasPriceChanged", price)
+
+	case "setFeeRecipient":
+		if err := ctx.authorize(); err != nil {
+			return nil, err
+		}
+		if approved, err := ctx.approve(); err != nil || !approved {
+			return nil, err
+		}
+		recipient := args[0].(common.Address)
+		ctx.statedb.SetState(FeeParamsAddress, feeRecipientSlot, common.BytesToHash(recipient.Bytes()))
+		return nil, ctx.emit("FeeRecipientChanged", recipient)
+	}
+	return nil, fmt.Errorf("unknown method %s", method)
+}
+
+// MinGasPrice returns the lowest gas price contract transactions must pay
+func MinGasPrice(statedb vm.StateDB) *big.Int {
+	return statedb.GetState(FeeParamsAddress, minGasPriceSlot).Big()
+}
+
+// FeeRecipient returns the account credited with transaction fees, or the zero address
+// if fees go to the block validator
+func FeeRecipient(statedb vm.StateDB) common.Address {
+	return common.BytesToAddress(statedb.GetState(FeeParamsAddress, feeRecipientSlot).Bytes())
+}
+
+// Allowlist storage: slot 0 holds the enabled flag, slot 1 the allowed mapping
+var allowlistEnabledSlot = common.BigToHash(big.NewInt(0))
+
+// mappingSlot returns the storage slot of the key in a Solidity mapping stored at the slot
+func mappingSlot(key common.Address, slot int64) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(key.Bytes(), 32), common.BigToHash(big.NewInt(slot)).Bytes())
+}
+
+// hashMappingSlot returns the storage slot of the key in a Solidity mapping with bytes32 keys
+// stored at the slot
+func hashMappingSlot(key common.Hash, slot int64) common.Hash {
+	return crypto.Keccak256Hash(key.Bytes(), common.BigToHash(big.NewInt(slot)).Bytes())
+}
+
+// arraySlot returns the storage slot of the element at the index of a Solidity dynamic array
+// stored at the slot
+func arraySlot(slot, index int64) common.Hash {
+	base := new(big.Int).SetBytes(crypto.Keccak256(common.BigToHash(big.NewInt(slot)).Bytes()))
+	return common.BigToHash(base.Add(base, big.NewInt(index)))
+}
+
+// setBytesState stores a byte string at the slot with the Solidity layout of bytes: short
+// values share the slot with their length, longer ones are stored from the hash of the slot.
+// Nil clears the value.
+func setBytesState(statedb vm.StateDB, address common.Address, slot common.Hash, data []byte) {
+	// Önceki uzun değerin kelimeleri temizlenir
+	if prev := statedb.GetState(address, slot).Big(); prev.Bit(0) == 1 {
+		length := new(big.Int).Rsh(prev, 1).Int64()
+		for i := int64(0); i < (length+31)/32; i++ {
+			statedb.SetState(address, bytesSlot(slot, i), common.Hash{})
+		}
+	}
+
+	switch {
+	case len(data) == 0:
+		statedb.SetState(address, slot, common.Hash{})
+	case len(data) < 32:
+		value := common.RightPadBytes(data, 32)
+		value[31] = byte(len(data) * 2)
+		statedb.SetState(address, slot, common.BytesToHash(value))
+	default:
+		statedb.SetState(address, slot, common.BigToHash(big.NewInt(int64(len(data))*2+1)))
+		for i := 0; i*32 < len(data); i++ {
+			end := (i + 1) * 32
+			if end > len(data) {
+				end = len(data)
+			}
+			statedb.SetState(address, bytesSlot(slot, int64(i)), common.BytesToHash(common.RightPadBytes(data[i*32:end], 32)))
+		}
+	}
+}
+
+// getBytesState returns the byte string stored at the slot with the Solidity layout of bytes
+func getBytesState(statedb vm.StateDB, address common.Address, slot common.Hash) []byte {
+	value := statedb.GetState(address, slot)
+	if value == (common.Hash{}) {
+		return nil
+	}
+	if value[31]&1 == 0 {
+		return common.CopyBytes(value[:value[31]/2])
+	}
+
+	length := new(big.Int).Rsh(value.Big(), 1).Int64()
+	data := make([]byte, 0, length+31)
+	for i := int64(0); i*32 < length; i++ {
+		word := statedb.GetState(address, bytesSlot(slot, i))
+		data = append(data, word.Bytes()...)
+	}
+	return data[:length]
+}
+
+// bytesSlot returns the storage slot of the word at the index of a long byte string stored at the slot
+func bytesSlot(slot common.Hash, index int64) common.Hash {
+	base := new(big.Int).SetBytes(crypto.Keccak256(slot.Bytes()))
+	return common.BigToHash(base.Add(base, big.NewInt(index)))
+}
+
+// runAllowlist implements an allowlist system contract that emits the given events
+func runAllowlist(enabledEvent, allowedEvent, disallowedEvent string) func(*systemContext, string, []interface{}) ([]interface{}, error) {
+	return func(ctx *systemContext, method string, args []interface{}) ([]interface{}, error) {
+		switch method {
+		case "enabled":
+			return []interface{}{allowlistEnabled(ctx.statedb, ctx.address)}, nil
+
+		case "isAllowed":
+			return []interface{}{allowlisted(ctx.statedb, ctx.address, args[0].(common.Address))}, nil
+
+		case "setEnabled":
+			if err := ctx.authorize(); err != nil {
+				return nil, err
+			}
+			if approved, err := ctx.approve(); err != nil || !approved {
+				return nil, err
+			}
+			enabled := args[0].(bool)
+			ctx.statedb.SetState(ctx.address, allowlistEnabledSlot, boolHash(enabled))
+			return nil, ctx.emit(enabledEvent, enabled)
+
+		case "allow", "disallow":
+			if err := ctx.authorize(); err != nil {
+				return nil, err
+			}
+			if approved, err := ctx.approve(); err != nil || !approved {
+				return nil, err
+			}
+			account := args[0].(common.Address)
+			ctx.statedb.SetState(ctx.address, mappingSlot(account, 1), boolHash(method == "allow"))
+			if method == "allow" {
+				return nil, ctx.emit(allowedEvent, account)
+			}
+			return nil, ctx.emit(disallowedEvent, account)
+		}
+		return nil, fmt.Errorf("unknown method %s", method)
+	}
+}
+
+// allowlistEnabled reports whether the allowlist at the address is enforced
+func allowlistEnabled(statedb vm.StateDB, allowlist common.Address) bool {
+	return statedb.GetState(allowlist, allowlistEnabledSlot) != (common.Hash{})
+}
+
+// allowlisted reports whether the account is on the allowlist at the address
+func allowlisted(statedb vm.StateDB, allowlist, account common.Address) bool {
+	return statedb.GetState(allowlist, mappingSlot(account, 1)) != (common.Hash{})
+}
+
+// ContractAllowed reports whether transactions may call the contract. System contracts are always allowed.
+func ContractAllowed(statedb vm.StateDB, address common.Address) bool {
+	if !allowlistEnabled(statedb, ContractAllowlistAddress) || IsSystemContract(address) {
+		return true
+	}
+	return allowlisted(statedb, ContractAllowlistAddress, address)
+}
+
+// DeployerAllowed reports whether the account may deploy contracts
+func DeployerAllowed(statedb vm.StateDB, account common.Address) bool {
+	if !allowlistEnabled(statedb, DeployerAllowlistAddress) {
+		return true
+	}
+	return allowlisted(statedb, DeployerAllowlistAddress, account)
+}
+
+// boolHash encodes a boolean storage value
+func boolHash(value bool) common.Hash {
+	if value {
+		return common.BigToHash(big.NewInt(1))
+	}
+	return common.Hash{}
+}
@@ -0,0 +1,306 @@
+package contracts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Transactions sent to a system contract are run natively, but contracts call system contracts
+// through the EVM. Each system contract account therefore holds EVM code implementing its view
+// methods over the storage the native implementation writes; state changing methods, calls
+// with value and unknown selectors revert.
+
+// program assembles EVM code with jump labels
+type program struct {
+	code   []byte
+	labels map[string]int
+	jumps  map[int]string // Offset of a PUSH2 operand -> label it jumps to
+}
+
+// newProgram creates an empty program
+func newProgram() *program {
+	return &program{labels: make(map[string]int), jumps: make(map[int]string)}
+}
+
+// op appends opcodes without immediates
+func (p *program) op(ops ...vm.OpCode) {
+	for _, op := range ops {
+		p.code = append(p.code, byte(op))
+	}
+}
+
+// push appends the shortest PUSH of the value
+func (p *program) push(value *big.Int) {
+	data := value.Bytes()
+	if len(data) == 0 {
+		data = []byte{0}
+	}
+	p.code = append(p.code, byte(vm.PUSH1)+byte(len(data)-1))
+	p.code = append(p.code, data...)
+}
+
+// pushInt appends the shortest PUSH of a small value
+func (p *program) pushInt(value int64) {
+	p.push(big.NewInt(value))
+}
+
+// jump appends a JUMP or JUMPI to the label
+func (p *program) jump(op vm.OpCode, label string) {
+	p.code = append(p.code, byte(vm.PUSH2), 0, 0)
+	p.jumps[len(p.code)-2] = label
+	p.op(op)
+}
+
+// label marks the current offset as a jump destination
+func (p *program) label(name string) {
+	p.labels[name] = len(p.code)
+	p.op(vm.JUMPDEST)
+}
+
+// assemble resolves the jump labels and returns the code
+func (p *program) assemble() []byte {
+	for offset, label := range p.jumps {
+		target, exists := p.labels[label]
+		if !exists {
+			panic(fmt.Sprintf("undefined label %s", label))
+		}
+		binary.BigEndian.PutUint16(p.code[offset:], uint16(target))
+	}
+	return p.code
+}
+
+// argument pushes the address argument at the index of the call
+func (p *program) argument(index int64) {
+	p.push(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1)))
+	p.pushInt(4 + 32*index)
+	p.op(vm.CALLDATALOAD, vm.AND)
+}
+
+// mappingSlot replaces the base slot and the key on top of it with the storage slot of the
+// key in a Solidity mapping at the base slot
+func (p *program) mappingSlot() {
+	p.pushInt(0)
+	p.op(vm.MSTORE)
+	p.pushInt(32)
+	p.op(vm.MSTORE)
+	p.pushInt(64)
+	p.pushInt(0)
+	p.op(vm.KECCAK256)
+}
+
+// mapping pushes the value stored for the address argument in the mapping at the slot
+func (p *program) mapping(slot, argument int64) {
+	p.pushInt(slot)
+	p.argument(argument)
+	p.mappingSlot()
+	p.op(vm.SLOAD)
+}
+
+// returnWord returns the value on top of the stack
+func (p *program) returnWord() {
+	p.pushInt(0)
+	p.op(vm.MSTORE)
+	p.pushInt(32)
+	p.pushInt(0)
+	p.op(vm.RETURN)
+}
+
+// returnBool returns whether the value on top of the stack is not zero
+func (p *program) returnBool() {
+	p.op(vm.ISZERO, vm.ISZERO)
+	p.returnWord()
+}
+
+// returnArray returns the Solidity dynamic array stored at the slot
+func (p *program) returnArray(slot int64) {
+	base := new(big.Int).SetBytes(crypto.Keccak256(common.BigToHash(big.NewInt(slot)).Bytes()))
+
+	// Bellek: 0x00 dizinin offset'i, 0x20 uzunluğu, 0x40'tan itibaren elemanlar
+	p.pushInt(32)
+	p.pushInt(0)
+	p.op(vm.MSTORE)
+	p.pushInt(slot)
+	p.op(vm.SLOAD, vm.DUP1)
+	p.pushInt(32)
+	p.op(vm.MSTORE)
+	p.pushInt(0)
+
+	// Yığın: uzunluk, indeks
+	p.label("array.loop")
+	p.op(vm.DUP2, vm.DUP2, vm.LT, vm.ISZERO)
+	p.jump(vm.JUMPI, "array.done")
+	p.op(vm.DUP1)
+	p.push(base)
+	p.op(vm.ADD, vm.SLOAD, vm.DUP2)
+	p.pushInt(32)
+	p.op(vm.MUL)
+	p.pushInt(64)
+	p.op(vm.ADD, vm.MSTORE)
+	p.pushInt(1)
+	p.op(vm.ADD)
+	p.jump(vm.JUMP, "array.loop")
+
+	p.label("array.done")
+	p.op(vm.POP)
+	p.pushInt(32)
+	p.op(vm.MUL)
+	p.pushInt(64)
+	p.op(vm.ADD)
+	p.pushInt(0)
+	p.op(vm.RETURN)
+}
+
+// returnString returns the string the value on top of the stack stands for, or the empty
+// string for other values. The strings must be shorter than 32 bytes.
+func (p *program) returnString(values map[int64]string) {
+	codes := make([]int64, 0, len(values))
+	for code := range values {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	p.pushInt(32)
+	p.pushInt(0)
+	p.op(vm.MSTORE)
+	for _, code := range codes {
+		p.op(vm.DUP1)
+		p.pushInt(code)
+		p.op(vm.EQ)
+		p.jump(vm.JUMPI, fmt.Sprintf("string.%d", code))
+	}
+	p.pushInt(0)
+	p.pushInt(32)
+	p.op(vm.MSTORE)
+	p.pushInt(64)
+	p.pushInt(0)
+	p.op(vm.RETURN)
+
+	for _, code := range codes {
+		p.label(fmt.Sprintf("string.%d", code))
+		p.pushInt(int64(len(values[code])))
+		p.pushInt(32)
+		p.op(vm.MSTORE)
+		p.push(new(big.Int).SetBytes(common.RightPadBytes([]byte(values[code]), 32)))
+		p.pushInt(64)
+		p.op(vm.MSTORE)
+		p.pushInt(96)
+		p.pushInt(0)
+		p.op(vm.RETURN)
+	}
+}
+
+// systemViews are the EVM implementations of the view methods of a system contract by
+// method name. Each one finds its arguments in the call data and ends with a RETURN.
+type systemViews map[string]func(p *program)
+
+// viewCode builds the runtime code of a system contract from its view methods
+func viewCode(contractABI abi.ABI, views systemViews) []byte {
+	names := make([]string, 0, len(views))
+	for name := range views {
+		if _, exists := contractABI.Methods[name]; !exists {
+			panic(fmt.Sprintf("unknown view method %s", name))
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p := newProgram()
+	p.op(vm.CALLVALUE)
+	p.jump(vm.JUMPI, "revert")
+	p.pushInt(4)
+	p.op(vm.CALLDATASIZE, vm.LT)
+	p.jump(vm.JUMPI, "revert")
+
+	// Metot seçicisi çağrı verisinin ilk 4 baytıdır
+	p.push(new(big.Int).Lsh(big.NewInt(1), 224))
+	p.pushInt(0)
+	p.op(vm.CALLDATALOAD, vm.DIV)
+	for _, name := range names {
+		p.op(vm.DUP1)
+		p.push(new(big.Int).SetBytes(contractABI.Methods[name].ID))
+		p.op(vm.EQ)
+		p.jump(vm.JUMPI, name)
+	}
+	p.label("revert")
+	p.pushInt(0)
+	p.op(vm.DUP1, vm.REVERT)
+
+	for _, name := range names {
+		p.label(name)
+		p.pushInt(int64(4 + 32*len(contractABI.Methods[name].Inputs)))
+		p.op(vm.CALLDATASIZE, vm.LT)
+		p.jump(vm.JUMPI, "revert")
+		views[name](p)
+	}
+	return p.assemble()
+}
+
+// validatorSetViews are the view methods of the validator set in EVM code
+var validatorSetViews = systemViews{
+	"getValidators": func(p *program) { p.returnArray(validatorListSlot) },
+	"isValidator": func(p *program) {
+		p.mapping(validatorIndexSlot, 0)
+		p.returnBool()
+	},
+}
+
+// feeParamsViews are the view methods of the fee parameters in EVM code
+var feeParamsViews = systemViews{
+	"minGasPrice": func(p *program) {
+		p.push(minGasPriceSlot.Big())
+		p.op(vm.SLOAD)
+		p.returnWord()
+	},
+	"feeRecipient": func(p *program) {
+		p.push(feeRecipientSlot.Big())
+		p.op(vm.SLOAD)
+		p.returnWord()
+	},
+}
+
+// allowlistViews are the view methods of the allowlists in EVM code
+var allowlistViews = systemViews{
+	"enabled": func(p *program) {
+		p.push(allowlistEnabledSlot.Big())
+		p.op(vm.SLOAD)
+		p.returnBool()
+	},
+	"isAllowed": func(p *program) {
+		p.mapping(1, 0)
+		p.returnBool()
+	},
+}
+
+// contractAccessViews are the view methods of the contract access in EVM code
+var contractAccessViews = systemViews{
+	"owner": func(p *program) {
+		p.mapping(ownerMappingSlot, 0)
+		p.returnWord()
+	},
+	"enabled": func(p *program) {
+		p.mapping(disabledMappingSlot, 0)
+		p.op(vm.ISZERO)
+		p.returnWord()
+	},
+	"roleOf": func(p *program) {
+		p.pushInt(roleMappingSlot)
+		p.argument(0)
+		p.mappingSlot()
+		p.argument(1)
+		p.mappingSlot()
+		p.op(vm.SLOAD)
+
+		names := make(map[int64]string, len(roleCodes))
+		for role, code := range roleCodes {
+			names[code] = string(role)
+		}
+		p.returnString(names)
+	},
+}
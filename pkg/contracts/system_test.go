@@ -0,0 +1,263 @@
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// setupAuthority sistem kontratlarını tek bir secp256k1 validator ile kurar ve hesabını döndürür
+func setupAuthority(t *testing.T, manager *Manager) common.Address {
+	key, _ := crypto.GenerateKey()
+	statedb := NewStateDB(manager.State())
+	if err := InitSystemState(statedb, [][]byte{crypto.FromECDSAPub(&key.PublicKey)}); err != nil {
+		t.Fatalf("Sistem state'i kurulamadı: %v", err)
+	}
+	statedb.Commit()
+	return crypto.PubkeyToAddress(key.PublicKey)
+}
+
+// forwarder çağrı verisini hedefe STATICCALL ile ileten ve sonucunu döndüren runtime kodudur
+func forwarder(target common.Address) []byte {
+	code := common.FromHex("366000600037" + "6000600036600073")
+	code = append(code, target.Bytes()...)
+	return append(code, common.FromHex("5afa"+"3d600060003e"+"603157"+"3d6000fd"+"5b3d6000f3")...)
+}
+
+// systemCall bir sistem kontratı metodunu ABI ile kodlar
+func systemCall(t *testing.T, address common.Address, method string, args ...interface{}) []byte {
+	data, err := systemContracts[address].abi.Pack(method, args...)
+	if err != nil {
+		t.Fatalf("%s kodlanamadı: %v", method, err)
+	}
+	return data
+}
+
+// TestSystemContracts sistem kontratlarının işlemlerle yönetildiğini test eder
+func TestSystemContracts(t *testing.T) {
+	manager := NewManager()
+	authority := setupAuthority(t, manager)
+	sender := common.HexToAddress("0x1234567890")
+	address := deployCounter(t, manager, sender)
+
+	apply := func(from, to common.Address, data []byte) *ExecutionResult {
+		statedb := NewStateDB(manager.State())
+		result := manager.ApplyMessage(statedb, &Message{From: from, To: &to, Data: data, GasLimit: 100000})
+		statedb.Commit()
+		return result
+	}
+
+	// Validator kümesi okunabilmeli
+	result := manager.Simulate(manager.State(), &Message{From: sender, To: &ValidatorSetAddress, Data: systemCall(t, ValidatorSetAddress, "getValidators")})
+	if result.Failed() {
+		t.Fatalf("Validator kümesi okunamadı: %v", result.Err)
+	}
+	values, err := systemContracts[ValidatorSetAddress].abi.Unpack("getValidators", result.ReturnData)
+	if err != nil || len(values[0].([]common.Address)) != 1 || values[0].([]common.Address)[0] != authority {
+		t.Errorf("Validator kümesi hatalı: %v %v", values, err)
+	}
+
+	// Yalnızca validator'lar parametreleri değiştirebilir
+	setPrice := systemCall(t, FeeParamsAddress, "setMinGasPrice", big.NewInt(7))
+	if result := apply(sender, FeeParamsAddress, setPrice); !errors.Is(result.Err, ErrNotAuthority) {
+		t.Errorf("Yetki hatası bekleniyordu: %v", result.Err)
+	}
+	statedb := NewStateDB(manager.State())
+	result = manager.ApplyMessage(statedb, &Message{From: authority, To: &FeeParamsAddress, Data: setPrice, GasLimit: 100000})
+	if result.Failed() {
+		t.Fatalf("Minimum gas fiyatı değiştirilemedi: %v", result.Err)
+	}
+	// Tek validator'lı zincirde oy tek başına çoğunluktur
+	if logs := statedb.Logs(); len(logs) != 2 || logs[0].Address != ValidatorSetAddress || logs[1].Address != FeeParamsAddress {
+		t.Errorf("Oy ve değişiklik event'leri üretilmedi: %v", logs)
+	}
+	statedb.Commit()
+	if price := MinGasPrice(NewStateDB(manager.State())); price.Int64() != 7 {
+		t.Errorf("Minimum gas fiyatı hatalı: %s", price)
+	}
+
+	// Kontratlar sistem kontratlarının okuma metotlarını EVM üzerinden çağırabilir
+	deploy := func(target common.Address) common.Address {
+		statedb := NewStateDB(manager.State())
+		code := append(initCode(forwarder(target)), common.Hash{}.Bytes()...)
+		deployed := manager.ApplyMessage(statedb, &Message{From: sender, Data: code, GasLimit: 200000})
+		if deployed.Failed() {
+			t.Fatalf("Kontrat deploy edilemedi: %v", deployed.Err)
+		}
+		statedb.Commit()
+		return deployed.ContractAddress
+	}
+	read := func(target common.Address, data []byte) *ExecutionResult {
+		reader := deploy(target)
+		return manager.Simulate(manager.State(), &Message{From: sender, To: &reader, Data: data})
+	}
+
+	result = read(ValidatorSetAddress, systemCall(t, ValidatorSetAddress, "getValidators"))
+	values, err = systemContracts[ValidatorSetAddress].abi.Unpack("getValidators", result.ReturnData)
+	if result.Failed() || err != nil || len(values[0].([]common.Address)) != 1 || values[0].([]common.Address)[0] != authority {
+		t.Errorf("Kontrat validator kümesini okuyamadı: %x %v %v", result.ReturnData, result.Err, err)
+	}
+	result = read(ValidatorSetAddress, systemCall(t, ValidatorSetAddress, "isValidator", authority))
+	if result.Failed() || new(big.Int).SetBytes(result.ReturnData).Int64() != 1 {
+		t.Errorf("Kontrat validator kontrolü yapamadı: %x %v", result.ReturnData, result.Err)
+	}
+	result = read(FeeParamsAddress, systemCall(t, FeeParamsAddress, "minGasPrice"))
+	if result.Failed() || new(big.Int).SetBytes(result.ReturnData).Int64() != 7 {
+		t.Errorf("Kontrat sistem parametresini okuyamadı: %x %v", result.ReturnData, result.Err)
+	}
+	result = read(ContractAccessAddress, systemCall(t, ContractAccessAddress, "owner", address))
+	if result.Failed() || common.BytesToAddress(result.ReturnData) != sender {
+		t.Errorf("Kontrat sahibini okuyamadı: %x %v", result.ReturnData, result.Err)
+	}
+
+	// Durum değiştiren metotlar kontratlardan çağrılamaz
+	if result := read(ValidatorSetAddress, systemCall(t, ValidatorSetAddress, "removeValidator", authority)); !result.Failed() {
+		t.Error("Kontrat validator kümesini değiştirebildi")
+	}
+
+	// Allowlist açıkken listede olmayan kontrat çağrılamaz
+	if result := apply(authority, ContractAllowlistAddress, systemCall(t, ContractAllowlistAddress, "setEnabled", true)); result.Failed() {
+		t.Fatalf("Allowlist açılamadı: %v", result.Err)
+	}
+	if result := apply(sender, address, nil); !errors.Is(result.Err, ErrContractNotAllowed) {
+		t.Errorf("Allowlist hatası bekleniyordu: %v", result.Err)
+	}
+	if result := apply(authority, ContractAllowlistAddress, systemCall(t, ContractAllowlistAddress, "allow", address)); result.Failed() {
+		t.Fatalf("Kontrat listeye eklenemedi: %v", result.Err)
+	}
+	if result := apply(sender, address, nil); result.Failed() {
+		t.Errorf("Listedeki kontrat çağrılamadı: %v", result.Err)
+	}
+}
+
+// TestDecodeValidatorSetChange validator kümesi event'lerinin çözülmesini test eder
+func TestDecodeValidatorSetChange(t *testing.T) {
+	manager := NewManager()
+	authority := setupAuthority(t, manager)
+	statedb := NewStateDB(manager.State())
+	apply := func(from common.Address, method string, args ...interface{}) *ExecutionResult {
+		return manager.ApplyMessage(statedb, &Message{From: from, To: &ValidatorSetAddress, Data: systemCall(t, ValidatorSetAddress, method, args...), GasLimit: 100000})
+	}
+	lastChange := func() *ValidatorSetChange {
+		logs := statedb.Logs()
+		change, _ := DecodeValidatorSetChange(logs[len(logs)-1])
+		return change
+	}
+
+	// Geçersiz public key reddedilmeli
+	if result := apply(authority, "addValidator", []byte{0x04, 0x01}); !result.Failed() {
+		t.Error("Geçersiz public key kabul edildi")
+	}
+
+	// P-256 generator noktası geçerli bir public key'dir
+	publicKey := common.FromHex("046b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c2964fe342e2fe1a7f9b8ee7eb4a7c0f9e162bce33576b315ececbb6406837bf51f5")
+	if result := apply(authority, "addValidator", publicKey); result.Failed() {
+		t.Fatalf("Validator eklenemedi: %v", result.Err)
+	}
+	account, _ := ValidatorAccount(publicKey)
+	change := lastChange()
+	if change == nil || !change.Added || change.Account != account || string(change.PublicKey) != string(publicKey) {
+		t.Errorf("Validator değişikliği çözülemedi: %+v", change)
+	}
+
+	// İki validator varken değişiklik için iki oy gerekir
+	key, _ := crypto.GenerateKey()
+	secpAccount := crypto.PubkeyToAddress(key.PublicKey)
+	if result := apply(authority, "addValidator", crypto.FromECDSAPub(&key.PublicKey)); result.Failed() {
+		t.Fatalf("Oy verilemedi: %v", result.Err)
+	}
+	if IsValidator(statedb, secpAccount) || lastChange() != nil {
+		t.Fatal("Değişiklik çoğunluk olmadan uygulandı")
+	}
+	if result := apply(authority, "addValidator", crypto.FromECDSAPub(&key.PublicKey)); !result.Failed() {
+		t.Error("Aynı validator iki kez oy verdi")
+	}
+	if result := apply(account, "addValidator", crypto.FromECDSAPub(&key.PublicKey)); result.Failed() {
+		t.Fatalf("secp256k1 validator eklenemedi: %v", result.Err)
+	}
+
+	// secp256k1 validator'ın hesabı Ethereum adresidir
+	if change := lastChange(); change == nil || change.Account != secpAccount {
+		t.Errorf("secp256k1 validator hesabı hatalı: %+v", change)
+	}
+	if validators := ValidatorAccounts(statedb); len(validators) != 3 || !IsValidator(statedb, account) {
+		t.Fatalf("Validator kümesi state'e yazılmadı: %v", validators)
+	}
+	if key := ValidatorPublicKey(statedb, account); string(key) != string(publicKey) {
+		t.Errorf("Public key state'e yazılmadı: %x", key)
+	}
+
+	// Küme değiştiğinde açık oylamalar yeniden başlar
+	setPrice := &Message{From: authority, To: &FeeParamsAddress, Data: systemCall(t, FeeParamsAddress, "setMinGasPrice", big.NewInt(9)), GasLimit: 100000}
+	if result := manager.ApplyMessage(statedb, setPrice); result.Failed() {
+		t.Fatalf("Oy verilemedi: %v", result.Err)
+	}
+	if result := apply(authority, "removeValidator", account); result.Failed() {
+		t.Fatalf("Oy verilemedi: %v", result.Err)
+	}
+	if result := apply(secpAccount, "removeValidator", account); result.Failed() {
+		t.Fatalf("Validator çıkarılamadı: %v", result.Err)
+	}
+	setPrice.From = secpAccount
+	if result := manager.ApplyMessage(statedb, setPrice); result.Failed() || MinGasPrice(statedb).Sign() != 0 {
+		t.Errorf("Önceki kümenin oyu sayıldı: %v %s", result.Err, MinGasPrice(statedb))
+	}
+
+	// Çıkarılan validator'ın yerini son validator alır
+	validators := ValidatorAccounts(statedb)
+	if len(validators) != 2 || validators[1] != secpAccount || IsValidator(statedb, account) || ValidatorPublicKey(statedb, account) != nil {
+		t.Errorf("Validator kümesi hatalı: %v", validators)
+	}
+
+	// Son validator çıkarılamaz
+	apply(authority, "removeValidator", secpAccount)
+	if result := apply(secpAccount, "removeValidator", secpAccount); result.Failed() {
+		t.Fatalf("Validator çıkarılamadı: %v", result.Err)
+	}
+	if result := apply(authority, "removeValidator", authority); !result.Failed() {
+		t.Error("Son validator çıkarıldı")
+	}
+}
+
+// TestDeployerAllowlist yalnızca listedeki hesapların kontrat deploy edebildiğini test eder
+func TestDeployerAllowlist(t *testing.T) {
+	manager := NewManager()
+	authority := setupAuthority(t, manager)
+	deployer := common.HexToAddress("0x1234567890")
+	code := append(initCode(counterCode), common.BigToHash(big.NewInt(0)).Bytes()...)
+
+	apply := func(method string, args ...interface{}) {
+		statedb := NewStateDB(manager.State())
+		msg := &Message{From: authority, To: &DeployerAllowlistAddress, Data: systemCall(t, DeployerAllowlistAddress, method, args...), GasLimit: 100000}
+		if result := manager.ApplyMessage(statedb, msg); result.Failed() {
+			t.Fatalf("%s başarısız: %v", method, result.Err)
+		}
+		statedb.Commit()
+	}
+
+	// Liste kapalıyken herkes deploy edebilir
+	implementation := deployCounter(t, manager, authority)
+	if _, err := manager.DeployContract(code, deployer, "Counter", "1.0.0", 0); err != nil {
+		t.Fatalf("Kontrat deploy edilemedi: %v", err)
+	}
+
+	apply("setEnabled", true)
+	if _, err := manager.DeployContract(code, deployer, "Counter", "1.0.0", 0); !errors.Is(err, ErrDeployerNotAllowed) {
+		t.Errorf("Deployer hatası bekleniyordu: %v", err)
+	}
+
+	// Proxy oluşturma da bir deploy işlemidir
+	statedb := NewStateDB(manager.State())
+	result := manager.ApplyMessage(statedb, &Message{From: deployer, Implementation: &implementation, GasLimit: 200000})
+	if !errors.Is(result.Err, ErrDeployerNotAllowed) {
+		t.Errorf("Proxy için deployer hatası bekleniyordu: %v", result.Err)
+	}
+
+	apply("allow", deployer)
+	if _, err := manager.DeployContract(code, deployer, "Counter", "1.0.0", 0); err != nil {
+		t.Errorf("Listedeki hesap deploy edemedi: %v", err)
+	}
+}
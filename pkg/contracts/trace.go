@@ -0,0 +1,430 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Tracer names
+const (
+	TracerStructLogger = "structLogger"
+	TracerCall         = "callTracer"
+	TracerPrestate     = "prestateTracer"
+)
+
+// ErrUnknownTracer is returned when the trace config names a tracer that does not exist
+var ErrUnknownTracer = errors.New("unknown tracer")
+
+// TraceConfig selects the tracer used by TraceMessage and configures the struct logger
+type TraceConfig struct {
+	Tracer         string `json:"tracer"` // structLogger if empty
+	DisableStack   bool   `json:"disableStack"`
+	DisableMemory  bool   `json:"disableMemory"`
+	DisableStorage bool   `json:"disableStorage"`
+	Limit          int    `json:"limit"` // Maximum number of struct logs, 0 for no limit
+}
+
+// Tracer collects the trace of a message through the EVM logger hooks
+type Tracer interface {
+	vm.EVMLogger
+
+	// Result returns the trace once the message is applied
+	Result(result *ExecutionResult) (interface{}, error)
+}
+
+// NewTracer creates the tracer selected by the config for the message. The prestate
+// tracer reads the accounts the message touches from pre, the state before the message.
+func NewTracer(config *TraceConfig, msg *Message, pre *StateDB) (Tracer, error) {
+	if config == nil {
+		config = &TraceConfig{}
+	}
+	switch config.Tracer {
+	case "", TracerStructLogger:
+		return &StructLogger{config: *config, storage: make(map[common.Address]map[common.Hash]common.Hash)}, nil
+	case TracerCall:
+		return &CallTracer{msg: msg}, nil
+	case TracerPrestate:
+		return newPrestateTracer(msg, pre), nil
+	}
+	return nil, fmt.Errorf("%w %s", ErrUnknownTracer, config.Tracer)
+}
+
+// TraceMessage applies the message to the StateDB with the tracer selected by the config
+// and returns the execution result with its trace. Changes pending in the StateDB must be
+// finalised first; the finalised state is the prestate of the message.
+func (m *Manager) TraceMessage(statedb *StateDB, msg *Message, config *TraceConfig) (*ExecutionResult, interface{}, error) {
+	tracer, err := NewTracer(config, msg, NewStateDB(statedb.world))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cpy := *msg
+	cpy.Tracer = tracer
+	result := m.ApplyMessage(statedb, &cpy)
+
+	trace, err := tracer.Result(result)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, trace, nil
+}
+
+// Trace simulates the message against the given state like Simulate and returns its trace
+func (m *Manager) Trace(state *WorldState, msg *Message, config *TraceConfig) (*ExecutionResult, interface{}, error) {
+	cpy := *msg
+	if cpy.GasLimit == 0 || cpy.GasLimit > m.vm.GasCap() {
+		cpy.GasLimit = m.vm.GasCap()
+	}
+	return m.TraceMessage(NewStateDB(state), &cpy, config)
+}
+
+// StructLog is a single EVM instruction executed by a message
+type StructLog struct {
+	Pc      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gasCost"`
+	Depth   int               `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack,omitempty"`
+	Memory  []string          `json:"memory,omitempty"`  // 32 byte words
+	Storage map[string]string `json:"storage,omitempty"` // Slots of the contract read or written so far
+}
+
+// StructLogResult is the trace returned by the struct logger
+type StructLogResult struct {
+	Gas         uint64      `json:"gas"`
+	Failed      bool        `json:"failed"`
+	ReturnValue string      `json:"returnValue"`
+	StructLogs  []StructLog `json:"structLogs"`
+}
+
+// StructLogger records every instruction executed with the stack, memory and storage
+type StructLogger struct {
+	config  TraceConfig
+	env     *vm.EVM
+	logs    []StructLog
+	storage map[common.Address]map[common.Hash]common.Hash
+}
+
+// CaptureTxStart implements vm.EVMLogger
+func (l *StructLogger) CaptureTxStart(gasLimit uint64) {}
+
+// CaptureTxEnd implements vm.EVMLogger
+func (l *StructLogger) CaptureTxEnd(restGas uint64) {}
+
+// CaptureStart implements vm.EVMLogger
+func (l *StructLogger) CaptureStart(env *vm.EVM, from, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	l.env = env
+}
+
+// CaptureEnd implements vm.EVMLogger
+func (l *StructLogger) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+// CaptureEnter implements vm.EVMLogger
+func (l *StructLogger) CaptureEnter(typ vm.OpCode, from, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+// CaptureExit implements vm.EVMLogger
+func (l *StructLogger) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+// CaptureState implements vm.EVMLogger
+func (l *StructLogger) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if l.config.Limit > 0 && len(l.logs) >= l.config.Limit {
+		return
+	}
+
+	log := StructLog{Pc: pc, Op: op.String(), Gas: gas, GasCost: cost, Depth: depth}
+	if err != nil {
+		log.Error = err.Error()
+	}
+
+	stack := scope.Stack.Data()
+	if !l.config.DisableStack {
+		log.Stack = make([]string, len(stack))
+		for i, value := range stack {
+			log.Stack[i] = value.Hex()
+		}
+	}
+	if !l.config.DisableMemory {
+		memory := scope.Memory.Data()
+		for i := 0; i+32 <= len(memory); i += 32 {
+			log.Memory = append(log.Memory, common.Bytes2Hex(memory[i:i+32]))
+		}
+	}
+
+	if !l.config.DisableStorage && (op == vm.SLOAD || op == vm.SSTORE) {
+		address := scope.Contract.Address()
+		slots, exists := l.storage[address]
+		if !exists {
+			slots = make(map[common.Hash]common.Hash)
+			l.storage[address] = slots
+		}
+
+		// SLOAD değeri state'ten okunur, SSTORE değeri stack'tedir
+		if op == vm.SLOAD && len(stack) >= 1 {
+			key := common.Hash(scope.Stack.Back(0).Bytes32())
+			slots[key] = l.env.StateDB.GetState(address, key)
+		} else if op == vm.SSTORE && len(stack) >= 2 {
+			slots[common.Hash(scope.Stack.Back(0).Bytes32())] = common.Hash(scope.Stack.Back(1).Bytes32())
+		}
+
+		log.Storage = make(map[string]string, len(slots))
+		for key, value := range slots {
+			log.Storage[common.Bytes2Hex(key.Bytes())] = common.Bytes2Hex(value.Bytes())
+		}
+	}
+
+	l.logs = append(l.logs, log)
+}
+
+// CaptureFault implements vm.EVMLogger. The failing instruction is recorded by CaptureState.
+func (l *StructLogger) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// Result implements Tracer
+func (l *StructLogger) Result(result *ExecutionResult) (interface{}, error) {
+	logs := l.logs
+	if logs == nil {
+		logs = []StructLog{}
+	}
+	return &StructLogResult{
+		Gas:         result.GasUsed,
+		Failed:      result.Failed(),
+		ReturnValue: common.Bytes2Hex(result.ReturnData),
+		StructLogs:  logs,
+	}, nil
+}
+
+// CallFrame is a call or contract creation made by a message, with the calls it made
+type CallFrame struct {
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []*CallFrame    `json:"calls,omitempty"`
+}
+
+// finish records the outcome of the frame
+func (f *CallFrame) finish(output []byte, gasUsed uint64, err error) {
+	f.GasUsed = hexutil.Uint64(gasUsed)
+	if err == nil {
+		f.Output = common.CopyBytes(output)
+		return
+	}
+
+	f.Error = err.Error()
+	if errors.Is(err, vm.ErrExecutionReverted) {
+		f.Output = common.CopyBytes(output)
+		if reason, unpackErr := abi.UnpackRevert(output); unpackErr == nil {
+			f.RevertReason = reason
+		}
+	}
+}
+
+// CallTracer records the call tree of a message
+type CallTracer struct {
+	msg   *Message
+	stack []*CallFrame // İlk eleman kök çağrıdır
+}
+
+// CaptureTxStart implements vm.EVMLogger
+func (t *CallTracer) CaptureTxStart(gasLimit uint64) {}
+
+// CaptureTxEnd implements vm.EVMLogger
+func (t *CallTracer) CaptureTxEnd(restGas uint64) {}
+
+// CaptureStart implements vm.EVMLogger
+func (t *CallTracer) CaptureStart(env *vm.EVM, from, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	typ := vm.CALL
+	if create {
+		typ = vm.CREATE
+	}
+	t.stack = []*CallFrame{newCallFrame(typ, from, to, input, gas, value)}
+}
+
+// CaptureEnd implements vm.EVMLogger
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	if len(t.stack) > 0 {
+		t.stack[0].finish(output, gasUsed, err)
+	}
+}
+
+// CaptureEnter implements vm.EVMLogger
+func (t *CallTracer) CaptureEnter(typ vm.OpCode, from, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.stack = append(t.stack, newCallFrame(typ, from, to, input, gas, value))
+}
+
+// CaptureExit implements vm.EVMLogger
+func (t *CallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(t.stack) < 2 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	frame.finish(output, gasUsed, err)
+
+	t.stack = t.stack[:len(t.stack)-1]
+	parent := t.stack[len(t.stack)-1]
+	parent.Calls = append(parent.Calls, frame)
+}
+
+// CaptureState implements vm.EVMLogger
+func (t *CallTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+// CaptureFault implements vm.EVMLogger
+func (t *CallTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// Result implements Tracer. The root frame reports the gas used by the whole message.
+func (t *CallTracer) Result(result *ExecutionResult) (interface{}, error) {
+	// EVM'e ulaşmayan mesajlarda (ön kontrol hatası, proxy işlemi) kök çağrı mesajdan oluşturulur
+	if len(t.stack) == 0 {
+		typ, to := vm.CALL, common.Address{}
+		if t.msg.IsDeployment() {
+			typ = vm.CREATE
+		} else {
+			to = *t.msg.To
+		}
+		t.stack = []*CallFrame{newCallFrame(typ, t.msg.From, to, t.msg.Data, t.msg.GasLimit, t.msg.Value)}
+	}
+
+	root := t.stack[0]
+	root.Gas = hexutil.Uint64(t.msg.GasLimit)
+	root.finish(result.ReturnData, result.GasUsed, result.Err)
+	if root.Type == vm.CREATE.String() && result.ContractAddress != (common.Address{}) {
+		root.To = &result.ContractAddress
+	}
+	return root, nil
+}
+
+// newCallFrame creates a call frame
+func newCallFrame(typ vm.OpCode, from, to common.Address, input []byte, gas uint64, value *big.Int) *CallFrame {
+	frame := &CallFrame{
+		Type:  typ.String(),
+		From:  from,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if to != (common.Address{}) {
+		frame.To = &to
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	return frame
+}
+
+// PrestateAccount is the state of an account before a message
+type PrestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Nonce   uint64                      `json:"nonce,omitempty"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// PrestateTracer records the state before a message of the accounts and storage slots it touches
+type PrestateTracer struct {
+	pre     *StateDB
+	touched map[common.Address]map[common.Hash]struct{}
+}
+
+// newPrestateTracer creates a prestate tracer touching the sender, recipient and coinbase of the message
+func newPrestateTracer(msg *Message, pre *StateDB) *PrestateTracer {
+	t := &PrestateTracer{pre: pre, touched: make(map[common.Address]map[common.Hash]struct{})}
+	t.touchAccount(msg.From)
+	t.touchAccount(msg.Coinbase)
+	if msg.To != nil {
+		t.touchAccount(*msg.To)
+	}
+	if msg.Implementation != nil {
+		t.touchAccount(*msg.Implementation)
+	}
+	return t
+}
+
+// touchAccount marks the account as touched
+func (t *PrestateTracer) touchAccount(address common.Address) {
+	if _, exists := t.touched[address]; !exists {
+		t.touched[address] = make(map[common.Hash]struct{})
+	}
+}
+
+// CaptureTxStart implements vm.EVMLogger
+func (t *PrestateTracer) CaptureTxStart(gasLimit uint64) {}
+
+// CaptureTxEnd implements vm.EVMLogger
+func (t *PrestateTracer) CaptureTxEnd(restGas uint64) {}
+
+// CaptureStart implements vm.EVMLogger
+func (t *PrestateTracer) CaptureStart(env *vm.EVM, from, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.touchAccount(from)
+	t.touchAccount(to)
+}
+
+// CaptureEnd implements vm.EVMLogger
+func (t *PrestateTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+// CaptureEnter implements vm.EVMLogger. Calls, creations and self-destruct beneficiaries all enter here.
+func (t *PrestateTracer) CaptureEnter(typ vm.OpCode, from, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.touchAccount(to)
+}
+
+// CaptureExit implements vm.EVMLogger
+func (t *PrestateTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+// CaptureState implements vm.EVMLogger
+func (t *PrestateTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil || len(scope.Stack.Data()) == 0 {
+		return
+	}
+
+	switch op {
+	case vm.SLOAD, vm.SSTORE:
+		address := scope.Contract.Address()
+		t.touchAccount(address)
+		t.touched[address][common.Hash(scope.Stack.Back(0).Bytes32())] = struct{}{}
+	case vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODECOPY, vm.EXTCODEHASH:
+		t.touchAccount(common.Address(scope.Stack.Back(0).Bytes20()))
+	}
+}
+
+// CaptureFault implements vm.EVMLogger
+func (t *PrestateTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// Result implements Tracer. Accounts that did not exist before the message are left out.
+func (t *PrestateTracer) Result(result *ExecutionResult) (interface{}, error) {
+	accounts := make(map[common.Address]*PrestateAccount, len(t.touched))
+	for address, slots := range t.touched {
+		if !t.pre.Exist(address) {
+			continue
+		}
+
+		account := &PrestateAccount{
+			Balance: (*hexutil.Big)(t.pre.GetBalance(address)),
+			Nonce:   t.pre.GetNonce(address),
+			Code:    common.CopyBytes(t.pre.GetCode(address)),
+		}
+		if len(slots) > 0 {
+			account.Storage = make(map[common.Hash]common.Hash, len(slots))
+			for slot := range slots {
+				account.Storage[slot] = t.pre.GetState(address, slot)
+			}
+		}
+		accounts[address] = account
+	}
+	return accounts, nil
+}
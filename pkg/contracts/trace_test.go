@@ -0,0 +1,139 @@
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestStructLogger komut bazlı izlemeyi test eder
+func TestStructLogger(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+	address := deployCounter(t, manager, sender)
+	root := manager.State().Root()
+
+	result, trace, err := manager.Trace(manager.State(), &Message{From: sender, To: &address}, nil)
+	if err != nil {
+		t.Fatalf("İzleme başarısız: %v", err)
+	}
+	logs := trace.(*StructLogResult)
+	if logs.Failed || logs.Gas != result.GasUsed {
+		t.Errorf("İzleme sonucu hatalı: %+v", logs)
+	}
+
+	// Sayaç kodu 12 komuttan oluşur
+	if len(logs.StructLogs) != 12 {
+		t.Fatalf("12 komut bekleniyordu: %d", len(logs.StructLogs))
+	}
+	if op := logs.StructLogs[1].Op; op != "SLOAD" {
+		t.Errorf("İkinci komut SLOAD olmalı: %s", op)
+	}
+	sstore := logs.StructLogs[6]
+	slot := common.Bytes2Hex(common.Hash{}.Bytes())
+	if sstore.Op != "SSTORE" || sstore.Storage[slot] != common.Bytes2Hex(common.BigToHash(big.NewInt(1)).Bytes()) {
+		t.Errorf("SSTORE storage değeri hatalı: %+v", sstore)
+	}
+	if len(sstore.Stack) != 3 {
+		t.Errorf("SSTORE öncesi stack 3 eleman içermeli: %v", sstore.Stack)
+	}
+	if ret := logs.StructLogs[11]; ret.Op != "RETURN" || len(ret.Memory) != 1 {
+		t.Errorf("RETURN öncesi bellek hatalı: %+v", ret)
+	}
+	if manager.State().Root() != root {
+		t.Error("İzleme state'i değiştirdi")
+	}
+
+	// Limit ve devre dışı bırakılan alanlar
+	_, trace, err = manager.Trace(manager.State(), &Message{From: sender, To: &address}, &TraceConfig{Limit: 5, DisableStack: true})
+	if err != nil {
+		t.Fatalf("İzleme başarısız: %v", err)
+	}
+	logs = trace.(*StructLogResult)
+	if len(logs.StructLogs) != 5 || logs.StructLogs[2].Stack != nil {
+		t.Errorf("Limit veya stack ayarı uygulanmadı: %+v", logs.StructLogs)
+	}
+
+	if _, _, err := manager.Trace(manager.State(), &Message{From: sender, To: &address}, &TraceConfig{Tracer: "unknown"}); !errors.Is(err, ErrUnknownTracer) {
+		t.Errorf("Bilinmeyen tracer hatası bekleniyordu: %v", err)
+	}
+}
+
+// TestCallTracer iç içe çağrıların izlenmesini test eder
+func TestCallTracer(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+	counter := deployCounter(t, manager, sender)
+
+	// CALL(0xffff, counter, 0, 0, 0, 0, 32) yapan kontrat. Frontier kurallarında GAS ile
+	// kalan gas'ın tamamı iç çağrıya verilemez.
+	caller := append(common.FromHex("60206000600060006000"), 0x73)
+	caller = append(caller, counter.Bytes()...)
+	caller = append(caller, 0x61, 0xff, 0xff, 0xf1, 0x00) // PUSH2 0xffff, CALL, STOP
+
+	statedb := NewStateDB(manager.State())
+	args := common.BigToHash(big.NewInt(0)).Bytes()
+	deployed := manager.ApplyMessage(statedb, &Message{From: sender, Data: append(initCode(caller), args...), GasLimit: 200000})
+	if deployed.Failed() {
+		t.Fatalf("Kontrat deploy edilemedi: %v", deployed.Err)
+	}
+	statedb.Commit()
+
+	result, trace, err := manager.Trace(manager.State(), &Message{From: sender, To: &deployed.ContractAddress, GasLimit: 100000}, &TraceConfig{Tracer: TracerCall})
+	if err != nil {
+		t.Fatalf("İzleme başarısız: %v", err)
+	}
+	frame := trace.(*CallFrame)
+	if frame.Type != "CALL" || *frame.To != deployed.ContractAddress || uint64(frame.GasUsed) != result.GasUsed || uint64(frame.Gas) != 100000 {
+		t.Errorf("Kök çağrı hatalı: %+v", frame)
+	}
+	if len(frame.Calls) != 1 {
+		t.Fatalf("Bir iç çağrı bekleniyordu: %d", len(frame.Calls))
+	}
+	inner := frame.Calls[0]
+	if inner.From != deployed.ContractAddress || *inner.To != counter || new(big.Int).SetBytes(inner.Output).Int64() != 1 {
+		t.Errorf("İç çağrı hatalı: %+v", inner)
+	}
+
+	// EVM'e ulaşmayan mesajın kök çağrısı mesajdan oluşturulur
+	_, trace, err = manager.Trace(manager.State(), &Message{From: sender, To: &counter, GasLimit: 1000}, &TraceConfig{Tracer: TracerCall})
+	if err != nil {
+		t.Fatalf("İzleme başarısız: %v", err)
+	}
+	if frame := trace.(*CallFrame); frame.Error == "" || *frame.To != counter {
+		t.Errorf("Gas yetersizliği kök çağrıda görülmeli: %+v", frame)
+	}
+}
+
+// TestPrestateTracer mesaj öncesi state'in izlenmesini test eder
+func TestPrestateTracer(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+	address := deployCounter(t, manager, sender)
+
+	// Slot 0'ı 1 yap
+	statedb := NewStateDB(manager.State())
+	if result := manager.ApplyMessage(statedb, &Message{From: sender, To: &address, GasLimit: 100000}); result.Failed() {
+		t.Fatalf("Çağrı başarısız: %v", result.Err)
+	}
+	statedb.Commit()
+
+	_, trace, err := manager.Trace(manager.State(), &Message{From: sender, To: &address}, &TraceConfig{Tracer: TracerPrestate})
+	if err != nil {
+		t.Fatalf("İzleme başarısız: %v", err)
+	}
+	accounts := trace.(map[common.Address]*PrestateAccount)
+
+	contract, exists := accounts[address]
+	if !exists {
+		t.Fatal("Kontrat prestate'te yok")
+	}
+	if string(contract.Code) != string(counterCode) || contract.Storage[common.Hash{}] != common.BigToHash(big.NewInt(1)) {
+		t.Errorf("Kontrat prestate'i hatalı: %+v", contract)
+	}
+	if account, exists := accounts[sender]; !exists || account.Nonce != 2 {
+		t.Errorf("Gönderenin işlem öncesi nonce'u 2 olmalı: %+v", account)
+	}
+}
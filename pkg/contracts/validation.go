@@ -0,0 +1,241 @@
+package contracts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Validation issue codes
+const (
+	IssueEmptyCode         = "empty_code"
+	IssueInitCodeTooLarge  = "init_code_too_large"
+	IssueCodeTooLarge      = "code_too_large"
+	IssueEFPrefix          = "ef_prefix"
+	IssueInvalidOpcode     = "invalid_opcode"
+	IssueInvalidJump       = "invalid_jump_destination"
+	IssueDeniedOpcode      = "denied_opcode"
+	IssueTruncatedPushData = "truncated_push_data"
+)
+
+// ValidationConfig configures the checks run on contract code on deploy. Runtime code
+// checks are part of block execution, so every node of a network must use the same config.
+type ValidationConfig struct {
+	MaxCodeSize          int  // Runtime code, EIP-170; 0 disables
+	MaxInitCodeSize      int  // Init code, EIP-3860; 0 disables
+	RejectEFPrefix       bool // EIP-3541
+	RejectInvalidOpcodes bool
+	RejectInvalidJumps   bool
+	DeniedOpcodes        []vm.OpCode // e.g. SELFDESTRUCT
+}
+
+// DefaultValidationConfig returns the Ethereum code size limits with all static checks enabled
+func DefaultValidationConfig() ValidationConfig {
+	return ValidationConfig{
+		MaxCodeSize:          params.MaxCodeSize,
+		MaxInitCodeSize:      params.MaxInitCodeSize,
+		RejectEFPrefix:       true,
+		RejectInvalidOpcodes: true,
+		RejectInvalidJumps:   true,
+	}
+}
+
+// ValidationIssue is a single problem found in contract code
+type ValidationIssue struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Offset  *int   `json:"offset,omitempty"`
+	Opcode  string `json:"opcode,omitempty"`
+}
+
+// ValidationError is returned when contract code fails validation
+type ValidationError struct {
+	Issues []ValidationIssue `json:"issues"`
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.Message
+	}
+	return "contract validation failed: " + strings.Join(messages, "; ")
+}
+
+// ParseOpcodes parses opcode names like "SELFDESTRUCT" into opcodes
+func ParseOpcodes(names []string) ([]vm.OpCode, error) {
+	opcodes := make([]vm.OpCode, 0, len(names))
+	for _, name := range names {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		op := vm.StringToOp(name)
+		if op.String() != name {
+			return nil, fmt.Errorf("unknown opcode %s", name)
+		}
+		opcodes = append(opcodes, op)
+	}
+	return opcodes, nil
+}
+
+// ValidateInitCode checks the init code submitted for a deployment. Its content is not
+// analysed: the runtime code and constructor arguments it carries are data.
+func ValidateInitCode(code []byte, config ValidationConfig) error {
+	if len(code) == 0 {
+		return &ValidationError{Issues: []ValidationIssue{{Code: IssueEmptyCode, Message: "empty contract code"}}}
+	}
+	if config.MaxInitCodeSize > 0 && len(code) > config.MaxInitCodeSize {
+		return &ValidationError{Issues: []ValidationIssue{{
+			Code:    IssueInitCodeTooLarge,
+			Message: fmt.Sprintf("init code size %d exceeds the limit of %d bytes", len(code), config.MaxInitCodeSize),
+		}}}
+	}
+	return nil
+}
+
+// ValidateRuntimeCode checks the runtime code returned by a constructor before it is stored
+func ValidateRuntimeCode(code []byte, config ValidationConfig) error {
+	if len(code) == 0 {
+		return nil
+	}
+
+	var issues []ValidationIssue
+	if config.MaxCodeSize > 0 && len(code) > config.MaxCodeSize {
+		issues = append(issues, ValidationIssue{
+			Code:    IssueCodeTooLarge,
+			Message: fmt.Sprintf("code size %d exceeds the limit of %d bytes", len(code), config.MaxCodeSize),
+		})
+	}
+	if config.RejectEFPrefix && code[0] == 0xef {
+		issues = append(issues, ValidationIssue{Code: IssueEFPrefix, Message: "code starts with the 0xEF byte", Offset: offset(0)})
+	}
+	issues = append(issues, analyzeCode(StripMetadata(code), config)...)
+
+	if len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
+
+// analyzeCode walks the instructions of the code. Constant jumps (PUSH followed by
+// JUMP or JUMPI) must land on a JUMPDEST; dynamic jumps cannot be checked statically.
+// Only reachable instructions are checked: the code from offset 0 or a JUMPDEST up to an
+// instruction that halts or jumps. Bytes after it, like data appended by the compiler,
+// are never executed.
+func analyzeCode(code []byte, config ValidationConfig) []ValidationIssue {
+	var issues []ValidationIssue
+
+	denied := make(map[vm.OpCode]bool, len(config.DeniedOpcodes))
+	for _, op := range config.DeniedOpcodes {
+		denied[op] = true
+	}
+
+	// JUMPDEST'ler ve sabit atlamalar toplanır, atlama hedefleri kod sonunda kontrol edilir
+	type jump struct {
+		offset int
+		dest   uint64
+	}
+	jumpdests := make(map[uint64]bool)
+	var jumps []jump
+	pushEnd, pushValue := -1, uint64(0) // Son PUSH'tan sonraki instruction ve PUSH değeri
+	reachable := true                   // Kod başından veya bir JUMPDEST'ten ulaşılabilir mi
+
+	for pc := 0; pc < len(code); pc++ {
+		op := vm.OpCode(code[pc])
+		if op == vm.JUMPDEST {
+			jumpdests[uint64(pc)] = true
+			reachable = true
+		}
+		if !reachable {
+			// Ulaşılamayan byte'lar yalnızca PUSH verisini atlamak için çözümlenir
+			if op >= vm.PUSH1 && op <= vm.PUSH32 {
+				pc += int(op-vm.PUSH1) + 1
+			}
+			continue
+		}
+
+		switch {
+		case op == vm.JUMP || op == vm.JUMPI:
+			if pc == pushEnd {
+				jumps = append(jumps, jump{offset: pc, dest: pushValue})
+			}
+
+		case op == vm.INVALID:
+			// 0xFE bilinçli olarak geçersiz bırakılmış opcode'dur (EIP-141), solc assert için kullanır
+
+		case strings.HasPrefix(op.String(), "opcode "):
+			if config.RejectInvalidOpcodes {
+				issues = append(issues, ValidationIssue{
+					Code:    IssueInvalidOpcode,
+					Message: fmt.Sprintf("invalid opcode 0x%02x at offset %d", byte(op), pc),
+					Offset:  offset(pc),
+					Opcode:  fmt.Sprintf("0x%02x", byte(op)),
+				})
+			}
+			reachable = false
+		}
+
+		if denied[op] {
+			issues = append(issues, ValidationIssue{
+				Code:    IssueDeniedOpcode,
+				Message: fmt.Sprintf("opcode %s at offset %d is not allowed", op, pc),
+				Offset:  offset(pc),
+				Opcode:  op.String(),
+			})
+		}
+
+		if op >= vm.PUSH1 && op <= vm.PUSH32 {
+			size := int(op-vm.PUSH1) + 1
+			if pc+size >= len(code) {
+				if !config.RejectInvalidOpcodes {
+					break
+				}
+				issues = append(issues, ValidationIssue{
+					Code:    IssueTruncatedPushData,
+					Message: fmt.Sprintf("%s at offset %d is missing push data", op, pc),
+					Offset:  offset(pc),
+					Opcode:  op.String(),
+				})
+				break
+			}
+
+			// 8 byte'tan büyük hedefler kodun dışındadır
+			pushEnd, pushValue = pc+size+1, ^uint64(0)
+			if size <= 8 {
+				pushValue = 0
+				for _, b := range code[pc+1 : pc+1+size] {
+					pushValue = pushValue<<8 | uint64(b)
+				}
+			}
+			pc += size
+		}
+
+		// Sonraki instruction'a geçmeyen opcode'lardan sonraki kod bir JUMPDEST'e kadar ulaşılamaz
+		switch op {
+		case vm.STOP, vm.RETURN, vm.REVERT, vm.INVALID, vm.SELFDESTRUCT, vm.JUMP:
+			reachable = false
+		}
+	}
+
+	if config.RejectInvalidJumps {
+		for _, j := range jumps {
+			if !jumpdests[j.dest] {
+				issues = append(issues, ValidationIssue{
+					Code:    IssueInvalidJump,
+					Message: fmt.Sprintf("jump at offset %d targets %d, which is not a JUMPDEST", j.offset, j.dest),
+					Offset:  offset(j.offset),
+					Opcode:  vm.OpCode(code[j.offset]).String(),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// offset returns a pointer to the code offset for a validation issue
+func offset(pc int) *int {
+	return &pc
+}
@@ -0,0 +1,108 @@
+package contracts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// issueCodes doğrulama hatasındaki sorun kodlarını döndürür
+func issueCodes(err error) []string {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+	codes := make([]string, len(validationErr.Issues))
+	for i, issue := range validationErr.Issues {
+		codes[i] = issue.Code
+	}
+	return codes
+}
+
+// TestValidateRuntimeCode runtime kod kontrollerini test eder
+func TestValidateRuntimeCode(t *testing.T) {
+	config := DefaultValidationConfig()
+	config.DeniedOpcodes = []vm.OpCode{vm.SELFDESTRUCT}
+
+	tests := []struct {
+		name string
+		code []byte
+		want []string
+	}{
+		{"geçerli kod", counterCode, nil},
+		{"metadata yok sayılır", append(common.CopyBytes(counterCode), metadata(0x0c)...), nil},
+		{"geçerli sabit atlama", common.FromHex("600456005b00"), nil},
+		{"geçersiz atlama hedefi", common.FromHex("6004565b00"), []string{IssueInvalidJump}},
+		{"PUSH verisi içinde JUMPDEST", common.FromHex("600456615b0000"), []string{IssueInvalidJump}},
+		{"tanımsız opcode", common.FromHex("600c0c00"), []string{IssueInvalidOpcode}},
+		{"0xFE geçerlidir", common.FromHex("6000fe"), nil},
+		{"eksik PUSH verisi", common.FromHex("6000610a"), []string{IssueTruncatedPushData}},
+		{"0xEF öneki", common.FromHex("ef00"), []string{IssueEFPrefix, IssueInvalidOpcode}},
+		{"yasaklı opcode", common.FromHex("6000ff"), []string{IssueDeniedOpcode}},
+		{"boyut sınırı", make([]byte, config.MaxCodeSize+1), []string{IssueCodeTooLarge}},
+		{"ulaşılamayan veri yok sayılır", common.FromHex("6000000cff"), nil},
+		{"JUMPDEST'ten sonraki kod kontrol edilir", common.FromHex("60005b0c"), []string{IssueInvalidOpcode}},
+		{"JUMPI sonrası kod ulaşılabilir", common.FromHex("6001600657ff5b00"), []string{IssueDeniedOpcode}},
+		{"ulaşılamayan PUSH verisindeki JUMPDEST", common.FromHex("00615b0cff"), nil},
+		{"metadata öncesi veri yok sayılır", append(append(common.CopyBytes(counterCode), common.FromHex("fe0cff")...), metadata(0x0c)...), nil},
+	}
+
+	for _, test := range tests {
+		got := issueCodes(ValidateRuntimeCode(test.code, config))
+		if len(got) != len(test.want) {
+			t.Errorf("%s: %v bekleniyordu, %v bulundu", test.name, test.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: %v bekleniyordu, %v bulundu", test.name, test.want, got)
+				break
+			}
+		}
+	}
+}
+
+// TestDeployRejectsInvalidRuntimeCode geçersiz runtime kodun saklanmadığını test eder
+func TestDeployRejectsInvalidRuntimeCode(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+
+	statedb := NewStateDB(manager.State())
+	result := manager.ApplyMessage(statedb, &Message{From: sender, Data: initCode(common.FromHex("ef00")), GasLimit: 200000})
+	if codes := issueCodes(result.Err); len(codes) == 0 || codes[0] != IssueEFPrefix {
+		t.Fatalf("0xEF öneki hatası bekleniyordu: %v", result.Err)
+	}
+	if result.GasUsed != 200000 {
+		t.Errorf("Tüm gas tüketilmeliydi: %d", result.GasUsed)
+	}
+	if statedb.GetNonce(sender) != 1 {
+		t.Errorf("Gönderen nonce'u artırılmadı: %d", statedb.GetNonce(sender))
+	}
+
+	address := crypto.CreateAddress(sender, 0)
+	if statedb.GetCodeSize(address) != 0 {
+		t.Error("Geçersiz kod saklandı")
+	}
+
+	// Init code boyut sınırı
+	if codes := issueCodes(manager.ValidateContract(make([]byte, DefaultValidationConfig().MaxInitCodeSize+1))); len(codes) != 1 || codes[0] != IssueInitCodeTooLarge {
+		t.Errorf("Init code boyut hatası bekleniyordu: %v", codes)
+	}
+}
+
+// TestParseOpcodes opcode isimlerinin çözümlenmesini test eder
+func TestParseOpcodes(t *testing.T) {
+	opcodes, err := ParseOpcodes([]string{"selfdestruct", " DELEGATECALL", ""})
+	if err != nil {
+		t.Fatalf("Opcode'lar çözümlenemedi: %v", err)
+	}
+	if len(opcodes) != 2 || opcodes[0] != vm.SELFDESTRUCT || opcodes[1] != vm.DELEGATECALL {
+		t.Errorf("Opcode'lar hatalı: %v", opcodes)
+	}
+	if _, err := ParseOpcodes([]string{"NOPE"}); err == nil {
+		t.Error("Bilinmeyen opcode kabul edildi")
+	}
+}
@@ -0,0 +1,311 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	// ErrNoCompiler is returned when source verification needs solc but none is configured
+	ErrNoCompiler = errors.New("no Solidity compiler available, submit a compiled artifact instead")
+
+	// ErrBytecodeMismatch is returned when the compiled runtime code differs from the deployed code
+	ErrBytecodeMismatch = errors.New("compiled runtime bytecode does not match the deployed code")
+)
+
+// VerificationRequest is the source and compiler settings submitted for verification.
+// If Artifact is set, its runtime bytecode is compared instead of compiling the source.
+type VerificationRequest struct {
+	ContractName    string    `json:"contract_name"` // "Name" or "File.sol:Name"
+	Source          string    `json:"source"`
+	CompilerVersion string    `json:"compiler_version"` // e.g. "0.8.19" or "v0.8.19+commit.7dd6d404"
+	Optimize        bool      `json:"optimize"`
+	Runs            int       `json:"runs"`
+	EVMVersion      string    `json:"evm_version,omitempty"`
+	Artifact        *Artifact `json:"artifact,omitempty"`
+}
+
+// Artifact is the compiler output of a contract, as produced by Hardhat, Foundry or solc
+type Artifact struct {
+	DeployedBytecode string          `json:"deployedBytecode"`
+	ABI              json.RawMessage `json:"abi"`
+}
+
+// SourceVerification is the verified source stored with a contract
+type SourceVerification struct {
+	ContractName    string          `json:"contract_name"`
+	Source          string          `json:"source"`
+	CompilerVersion string          `json:"compiler_version"`
+	Optimize        bool            `json:"optimize"`
+	Runs            int             `json:"runs"`
+	EVMVersion      string          `json:"evm_version,omitempty"`
+	Method          string          `json:"method"` // "solc" or "artifact"
+	ABI             json.RawMessage `json:"abi,omitempty"`
+	VerifiedAt      int64           `json:"verified_at"`
+}
+
+// CompiledContract is the compiler output used to verify a contract
+type CompiledContract struct {
+	RuntimeCode []byte
+	ABI         json.RawMessage
+
+	// Immutable değişkenlerin runtime kodundaki yerleri, deploy sırasında doldurulurlar
+	ImmutableReferences []CodeRange
+}
+
+// CodeRange is a byte range in contract code
+type CodeRange struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// Compiler compiles the source of a verification request
+type Compiler interface {
+	Compile(req *VerificationRequest) (*CompiledContract, error)
+}
+
+// VerifyContract compiles the submitted source (or takes the submitted artifact), compares its
+// runtime bytecode with the deployed code ignoring the metadata hash, and stores the verified
+// source. The compiled ABI is stored with the contract if it was deployed without one.
+func (m *Manager) VerifyContract(address common.Address, req *VerificationRequest, compiler Compiler) (*Contract, error) {
+	if req.ContractName == "" {
+		return nil, errors.New("contract name is required")
+	}
+	if req.Artifact == nil && (req.Source == "" || req.CompilerVersion == "") {
+		return nil, errors.New("source and compiler version are required")
+	}
+
+	code := NewStateDB(m.vm.State()).GetCode(address)
+	if len(code) == 0 {
+		return nil, errors.New("no contract code at address")
+	}
+
+	method := "solc"
+	var compiled *CompiledContract
+	var err error
+	switch {
+	case req.Artifact != nil:
+		method = "artifact"
+		compiled, err = req.Artifact.compiled()
+	case compiler == nil:
+		err = ErrNoCompiler
+	default:
+		compiled, err = compiler.Compile(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !MatchRuntimeCode(code, compiled) {
+		return nil, ErrBytecodeMismatch
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	contract, exists := m.contracts[address]
+	if !exists {
+		return nil, errors.New("contract not found")
+	}
+	contract.Source = &SourceVerification{
+		ContractName:    req.ContractName,
+		Source:          req.Source,
+		CompilerVersion: req.CompilerVersion,
+		Optimize:        req.Optimize,
+		Runs:            req.Runs,
+		EVMVersion:      req.EVMVersion,
+		Method:          method,
+		ABI:             compiled.ABI,
+		VerifiedAt:      time.Now().Unix(),
+	}
+	return contract, nil
+}
+
+// compiled returns the artifact as compiler output
+func (a *Artifact) compiled() (*CompiledContract, error) {
+	code := a.DeployedBytecode
+	if !strings.HasPrefix(code, "0x") {
+		code = "0x" + code
+	}
+	runtime, err := hexutil.Decode(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact bytecode: %v", err)
+	}
+	if len(a.ABI) > 0 {
+		if _, err := ParseABI(a.ABI); err != nil {
+			return nil, err
+		}
+	}
+	return &CompiledContract{RuntimeCode: runtime, ABI: a.ABI}, nil
+}
+
+// MatchRuntimeCode compares deployed code with compiled runtime code. The metadata hash
+// appended by solc and immutable values filled in on deploy are ignored.
+func MatchRuntimeCode(deployed []byte, compiled *CompiledContract) bool {
+	deployed = common.CopyBytes(deployed)
+	for _, ref := range compiled.ImmutableReferences {
+		if ref.Start >= 0 && ref.Length > 0 && ref.Start+ref.Length <= len(deployed) {
+			copy(deployed[ref.Start:ref.Start+ref.Length], make([]byte, ref.Length))
+		}
+	}
+	return bytes.Equal(StripMetadata(deployed), StripMetadata(compiled.RuntimeCode))
+}
+
+// StripMetadata removes the CBOR encoded metadata solc appends to the runtime code.
+// Its length is stored big endian in the last two bytes.
+func StripMetadata(code []byte) []byte {
+	if len(code) < 2 {
+		return code
+	}
+	length := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	if length == 0 || length+2 > len(code) {
+		return code
+	}
+
+	// CBOR metadata bir map ile başlar (major type 5)
+	metadata := code[len(code)-2-length : len(code)-2]
+	if metadata[0]&0xe0 != 0xa0 {
+		return code
+	}
+	return code[:len(code)-2-length]
+}
+
+// SolcCompiler compiles Solidity with a local solc binary through its standard JSON interface
+type SolcCompiler struct {
+	Path string
+}
+
+// NewSolcCompiler returns a compiler for the solc binary at the path, or found in PATH if empty
+func NewSolcCompiler(path string) (*SolcCompiler, error) {
+	if path == "" {
+		path = "solc"
+	}
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("solc not found: %v", err)
+	}
+	return &SolcCompiler{Path: resolved}, nil
+}
+
+// Version returns the version reported by solc, e.g. "0.8.19+commit.7dd6d404.Linux.g++"
+func (s *SolcCompiler) Version() (string, error) {
+	output, err := exec.Command(s.Path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run solc: %v", err)
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "Version: ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "Version: ")), nil
+		}
+	}
+	return "", errors.New("unexpected solc --version output")
+}
+
+// Compile compiles the request source with the solc binary. The binary must match the
+// requested compiler version.
+func (s *SolcCompiler) Compile(req *VerificationRequest) (*CompiledContract, error) {
+	version, err := s.Version()
+	if err != nil {
+		return nil, err
+	}
+	if !compilerVersionMatches(version, req.CompilerVersion) {
+		return nil, fmt.Errorf("solc version %s does not match requested version %s", version, req.CompilerVersion)
+	}
+
+	file, name := splitContractName(req.ContractName)
+	input := map[string]interface{}{
+		"language": "Solidity",
+		"sources": map[string]interface{}{
+			file: map[string]string{"content": req.Source},
+		},
+		"settings": map[string]interface{}{
+			"optimizer": map[string]interface{}{"enabled": req.Optimize, "runs": req.Runs},
+			"outputSelection": map[string]interface{}{
+				"*": map[string][]string{"*": {"abi", "evm.deployedBytecode.object", "evm.deployedBytecode.immutableReferences"}},
+			},
+		},
+	}
+	if req.EVMVersion != "" {
+		input["settings"].(map[string]interface{})["evmVersion"] = req.EVMVersion
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(s.Path, "--standard-json")
+	cmd.Stdin = bytes.NewReader(data)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run solc: %v", err)
+	}
+
+	var result struct {
+		Errors []struct {
+			Severity         string `json:"severity"`
+			FormattedMessage string `json:"formattedMessage"`
+		} `json:"errors"`
+		Contracts map[string]map[string]struct {
+			ABI json.RawMessage `json:"abi"`
+			EVM struct {
+				DeployedBytecode struct {
+					Object              string                 `json:"object"`
+					ImmutableReferences map[string][]CodeRange `json:"immutableReferences"`
+				} `json:"deployedBytecode"`
+			} `json:"evm"`
+		} `json:"contracts"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode solc output: %v", err)
+	}
+	for _, e := range result.Errors {
+		if e.Severity == "error" {
+			return nil, fmt.Errorf("compilation failed: %s", e.FormattedMessage)
+		}
+	}
+
+	contract, exists := result.Contracts[file][name]
+	if !exists {
+		return nil, fmt.Errorf("contract %s not found in compiler output", req.ContractName)
+	}
+	runtime, err := hexutil.Decode("0x" + contract.EVM.DeployedBytecode.Object)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compiler bytecode: %v", err)
+	}
+
+	compiled := &CompiledContract{RuntimeCode: runtime, ABI: contract.ABI}
+	for _, refs := range contract.EVM.DeployedBytecode.ImmutableReferences {
+		compiled.ImmutableReferences = append(compiled.ImmutableReferences, refs...)
+	}
+	return compiled, nil
+}
+
+// splitContractName splits "File.sol:Name" into the source file and contract name.
+// A bare name is looked up in a file named after it.
+func splitContractName(contractName string) (string, string) {
+	if i := strings.LastIndex(contractName, ":"); i >= 0 {
+		return contractName[:i], contractName[i+1:]
+	}
+	return contractName + ".sol", contractName
+}
+
+// compilerVersionMatches compares the solc version with the requested one. A request
+// without a commit hash matches any build of that release.
+func compilerVersionMatches(actual, requested string) bool {
+	requested = strings.TrimPrefix(requested, "v")
+	if requested == "" {
+		return false
+	}
+	if strings.Contains(requested, "+") {
+		return strings.HasPrefix(actual, requested)
+	}
+	return strings.SplitN(actual, "+", 2)[0] == requested
+}
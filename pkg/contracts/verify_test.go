@@ -0,0 +1,125 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// metadata solc'un runtime kodun sonuna eklediği CBOR metadata'yı taklit eder
+func metadata(hash byte) []byte {
+	cbor := append([]byte{0xa1, 0x64}, "ipfs"...)
+	cbor = append(cbor, 0x43, hash, hash, hash)
+	return append(cbor, 0x00, byte(len(cbor)))
+}
+
+// fakeCompiler derleme sonucu olarak sabit bir çıktı döndürür
+type fakeCompiler struct {
+	compiled *CompiledContract
+}
+
+func (f *fakeCompiler) Compile(req *VerificationRequest) (*CompiledContract, error) {
+	return f.compiled, nil
+}
+
+// TestStripMetadata metadata'nın runtime koddan ayrıldığını test eder
+func TestStripMetadata(t *testing.T) {
+	code := append(common.CopyBytes(counterCode), metadata(1)...)
+	if stripped := StripMetadata(code); !bytes.Equal(stripped, counterCode) {
+		t.Errorf("Metadata ayrılamadı: %x", stripped)
+	}
+
+	// Metadata içermeyen kod değişmemeli
+	if stripped := StripMetadata(counterCode); !bytes.Equal(stripped, counterCode) {
+		t.Errorf("Metadata içermeyen kod değişti: %x", stripped)
+	}
+}
+
+// TestVerifyContract kaynak kod doğrulamasını test eder
+func TestVerifyContract(t *testing.T) {
+	manager := NewManager()
+	sender := common.HexToAddress("0x1234567890")
+
+	// Runtime kodu metadata ile deploy et
+	statedb := NewStateDB(manager.State())
+	runtime := append(common.CopyBytes(counterCode), metadata(1)...)
+	args := common.BigToHash(big.NewInt(0)).Bytes()
+	result := manager.ApplyMessage(statedb, &Message{From: sender, Data: append(initCode(runtime), args...), GasLimit: 200000})
+	if result.Failed() {
+		t.Fatalf("Kontrat deploy edilemedi: %v", result.Err)
+	}
+	statedb.Commit()
+	address := result.ContractAddress
+	manager.RegisterContract(&Contract{Address: address, Owner: sender, Name: "Counter"})
+
+	// Farklı metadata hash'i doğrulamayı engellememeli
+	contractABI := json.RawMessage(`[{"type":"function","name":"increment","inputs":[],"outputs":[{"name":"","type":"uint256"}]}]`)
+	artifact := &Artifact{DeployedBytecode: hexutil.Encode(append(common.CopyBytes(counterCode), metadata(2)...)), ABI: contractABI}
+	contract, err := manager.VerifyContract(address, &VerificationRequest{ContractName: "Counter", Artifact: artifact}, nil)
+	if err != nil {
+		t.Fatalf("Kontrat doğrulanamadı: %v", err)
+	}
+	if contract.Source == nil || contract.Source.Method != "artifact" {
+		t.Errorf("Doğrulama kaydı hatalı: %+v", contract.Source)
+	}
+	if !bytes.Equal(contract.Source.ABI, contractABI) {
+		t.Errorf("ABI doğrulama kaydına eklenmedi: %s", contract.Source.ABI)
+	}
+
+	// Deploy ile gelen ABI state'e bağlıdır ve değişmez, doğrulanan ABI onun yerine kullanılır
+	if len(contract.ABI) != 0 {
+		t.Errorf("Deploy ABI'si değişmemeli: %s", contract.ABI)
+	}
+	if parsed, err := contract.ParsedABI(); err != nil || parsed.Methods["increment"].Name == "" {
+		t.Errorf("Doğrulanan ABI kullanılamadı: %v", err)
+	}
+
+	// Farklı kod reddedilmeli
+	artifact = &Artifact{DeployedBytecode: hexutil.Encode(append(common.FromHex("6001"), counterCode...))}
+	if _, err := manager.VerifyContract(address, &VerificationRequest{ContractName: "Counter", Artifact: artifact}, nil); !errors.Is(err, ErrBytecodeMismatch) {
+		t.Errorf("Bytecode uyuşmazlığı bekleniyordu: %v", err)
+	}
+
+	// Derleyici yoksa kaynak kod doğrulanamaz
+	req := &VerificationRequest{ContractName: "Counter", Source: "contract Counter {}", CompilerVersion: "0.8.19"}
+	if _, err := manager.VerifyContract(address, req, nil); !errors.Is(err, ErrNoCompiler) {
+		t.Errorf("Derleyici hatası bekleniyordu: %v", err)
+	}
+
+	// Derleyici çıktısında immutable alanlar sıfırdır, deploy edilen kodda ise doldurulmuştur
+	compiled := common.CopyBytes(counterCode)
+	compiled[0] = 0x00
+	compiler := &fakeCompiler{compiled: &CompiledContract{RuntimeCode: compiled}}
+	if _, err := manager.VerifyContract(address, req, compiler); !errors.Is(err, ErrBytecodeMismatch) {
+		t.Errorf("Immutable alanları bilinmeden bytecode uyuşmamalı: %v", err)
+	}
+	compiler.compiled.ImmutableReferences = []CodeRange{{Start: 0, Length: 1}}
+	contract, err = manager.VerifyContract(address, req, compiler)
+	if err != nil {
+		t.Fatalf("Kaynak kod doğrulanamadı: %v", err)
+	}
+	if contract.Source.Source != req.Source || contract.Source.Method != "solc" {
+		t.Errorf("Kaynak kod kaydedilmedi: %+v", contract.Source)
+	}
+}
+
+// TestCompilerVersionMatches derleyici sürümü karşılaştırmasını test eder
+func TestCompilerVersionMatches(t *testing.T) {
+	actual := "0.8.19+commit.7dd6d404.Linux.g++"
+	for requested, want := range map[string]bool{
+		"0.8.19":                  true,
+		"v0.8.19+commit.7dd6d404": true,
+		"0.8.1":                   false,
+		"v0.8.19+commit.00000000": false,
+		"":                        false,
+	} {
+		if got := compilerVersionMatches(actual, requested); got != want {
+			t.Errorf("%q için %v bekleniyordu", requested, want)
+		}
+	}
+}
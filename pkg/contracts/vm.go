@@ -1,135 +1,162 @@
 package contracts
 
 import (
-	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
 )
 
-// VM represents the smart contract virtual machine
+// DefaultGasCap is the gas limit of read-only calls and of messages executed outside of a block
+const DefaultGasCap = 30000000
+
+// VM represents the smart contract virtual machine. Every call and deployment runs in a
+// fresh EVM, so the VM can be used from concurrent goroutines.
 type VM struct {
-	evm *vm.EVM
+	config     *params.ChainConfig
+	state      *WorldState
+	validation ValidationConfig
 }
 
-// NewVM creates a new virtual machine instance
-func NewVM() *VM {
-	// EVM konfigürasyonu
-	vmConfig := vm.Config{}
+// ExecutionContext is the environment of an EVM execution
+type ExecutionContext struct {
+	Block    *BlockContext  // nil executes in an empty genesis block
+	Origin   common.Address // Account that sent the transaction (ORIGIN)
+	GasPrice *big.Int       // Gas price of the transaction (GASPRICE)
+	Tracer   vm.EVMLogger   // Receives the execution steps when set
+}
 
-	// EVM context'i
-	context := vm.BlockContext{
-		GetHash: func(uint64) common.Hash { return common.Hash{} },
-		BaseFee: big.NewInt(0),
-		GasLimit: uint64(30000000),
+// NewVM creates a new virtual machine instance over the given world state, running with
+// the default chain config
+func NewVM(state *WorldState) *VM {
+	return &VM{
+		config:     DefaultChainConfig(),
+		state:      state,
+		validation: DefaultValidationConfig(),
 	}
+}
 
-	// StateDB mock
-	statedb := NewMockStateDB()
+// State returns the world state the VM executes against
+func (v *VM) State() *WorldState {
+	return v.state
+}
 
-	// EVM oluştur
-	evm := vm.NewEVM(context, vm.TxContext{}, statedb, params.MainnetChainConfig, vmConfig)
+// ChainConfig returns the chain ID and fork configuration the VM runs with
+func (v *VM) ChainConfig() *params.ChainConfig {
+	return v.config
+}
 
-	return &VM{
-		evm: evm,
+// SetChainConfig replaces the chain ID and fork configuration the VM runs with
+func (v *VM) SetChainConfig(config *params.ChainConfig) {
+	v.config = config
+}
+
+// GasCap returns the gas limit of read-only calls
+func (v *VM) GasCap() uint64 {
+	return DefaultGasCap
+}
+
+// Rules returns the chain rules active in the block, or in the genesis block if nil
+func (v *VM) Rules(block *BlockContext) params.Rules {
+	number, time := new(big.Int), uint64(0)
+	if block != nil {
+		number.SetUint64(block.Number)
+		time = block.Time
 	}
+	return v.config.Rules(number, isMerge(v.config), time)
 }
 
-// Execute executes a smart contract
-func (v *VM) Execute(code []byte, input []byte) ([]byte, error) {
-	// Contract oluştur
-	value := big.NewInt(0)
-	caller := common.Address{}
-	contract := common.Address{}
-
-	// Gas limitini hesapla:
-	// - Temel gas: 21000 (EVM'de bir işlemin minimum gas maliyeti)
-	// - Kontrat kodu için gas: her byte için 200 gas
-	// - Input verisi için gas: her byte için 68 gas (4 if zero byte, 68 if non-zero byte)
-	// - Ekstra buffer: 100000 gas
-	gasLimit := uint64(21000) // base
-	gasLimit += uint64(len(code) * 200) // code size cost
-	
-	// Input verisi için gas hesapla
-	for _, b := range input {
-		if b == 0 {
-			gasLimit += 4 // zero byte
-		} else {
-			gasLimit += 68 // non-zero byte
+// blockContext returns the EVM block context of the block, or of an empty genesis
+// block if nil
+func (v *VM) blockContext(block *BlockContext) vm.BlockContext {
+	context := vm.BlockContext{
+		CanTransfer: canTransfer,
+		Transfer:    transfer,
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		BlockNumber: new(big.Int),
+		Difficulty:  new(big.Int),
+		BaseFee:     new(big.Int),
+		GasLimit:    DefaultGasCap,
+	}
+	if block != nil {
+		context.BlockNumber.SetUint64(block.Number)
+		context.Time = block.Time
+		context.Coinbase = block.Coinbase
+		if block.GasLimit > 0 {
+			context.GasLimit = block.GasLimit
+		}
+		if block.GetHash != nil {
+			context.GetHash = block.GetHash
 		}
 	}
-	
-	gasLimit += 100000 // buffer for execution
+	if isMerge(v.config) {
+		context.Random = &common.Hash{}
+	}
+	return context
+}
 
-	// Create contract
-	contractObj := vm.NewContract(vm.AccountRef(caller), vm.AccountRef(contract), value, gasLimit)
+// newEVM creates the EVM of an execution
+func (v *VM) newEVM(statedb *StateDB, ctx *ExecutionContext) *vm.EVM {
+	txContext := vm.TxContext{Origin: ctx.Origin, GasPrice: new(big.Int)}
+	if ctx.GasPrice != nil {
+		txContext.GasPrice.Set(ctx.GasPrice)
+	}
+	return vm.NewEVM(v.blockContext(ctx.Block), txContext, statedb, v.config, vm.Config{Tracer: ctx.Tracer})
+}
 
-	// Kontrat kodunu ayarla
-	contractObj.Code = code
-	contractObj.CodeHash = common.BytesToHash(code)
+// Call executes a message call from the caller on the given StateDB and returns the output
+// and gas used. Changes stay in the StateDB; it is up to the caller to commit them.
+func (v *VM) Call(statedb *StateDB, ctx *ExecutionContext, caller, address common.Address, input []byte, value *big.Int, gasLimit uint64) ([]byte, uint64, error) {
+	// Kontratı çalıştır, hata durumunda değişiklikler EVM tarafından geri alınır
+	evm := v.newEVM(statedb, ctx)
+	ret, leftOverGas, err := evm.Call(vm.AccountRef(caller), address, input, gasLimit, value)
+	return ret, gasLimit - leftOverGas, err
+}
 
-	// Kontratı çalıştır
-	ret, err := v.evm.Interpreter().Run(contractObj, input, false)
-	if err != nil {
-		return nil, err
+// Create runs the init code of a new contract deployed by the caller and stores the runtime
+// code it returns. The address is derived from the caller and its nonce, or from the salt
+// when one is given (CREATE2).
+func (v *VM) Create(statedb *StateDB, ctx *ExecutionContext, caller common.Address, code []byte, salt *common.Hash, value *big.Int, gasLimit uint64) ([]byte, common.Address, uint64, error) {
+	evm := v.newEVM(statedb, ctx)
+
+	var (
+		ret         []byte
+		address     common.Address
+		leftOverGas uint64
+		err         error
+	)
+	if salt != nil {
+		ret, address, leftOverGas, err = evm.Create2(vm.AccountRef(caller), code, gasLimit, value, new(uint256.Int).SetBytes(salt.Bytes()))
+	} else {
+		ret, address, leftOverGas, err = evm.Create(vm.AccountRef(caller), code, gasLimit, value)
 	}
-
-	return ret, nil
+	return ret, address, gasLimit - leftOverGas, err
 }
 
-// ValidateContract validates a smart contract code
+// ValidateContract validates the init code of a deployment
 func (v *VM) ValidateContract(code []byte) error {
-	if len(code) == 0 {
-		return errors.New("empty contract code")
-	}
+	return ValidateInitCode(code, v.validation)
+}
 
-	// TODO: Daha detaylı kontrat doğrulama kontrolleri eklenecek
-	return nil
-}
-
-// MockStateDB is a mock implementation of vm.StateDB
-type MockStateDB struct{}
-
-func NewMockStateDB() *MockStateDB {
-	return &MockStateDB{}
-}
-
-func (m *MockStateDB) CreateAccount(common.Address)                                        {}
-func (m *MockStateDB) SubBalance(common.Address, *big.Int)                                {}
-func (m *MockStateDB) AddBalance(addr common.Address, amount *big.Int)                    {}
-func (m *MockStateDB) GetBalance(common.Address) *big.Int                                 { return big.NewInt(0) }
-func (m *MockStateDB) GetNonce(common.Address) uint64                                     { return 0 }
-func (m *MockStateDB) SetNonce(common.Address, uint64)                                    {}
-func (m *MockStateDB) GetCodeHash(common.Address) common.Hash                             { return common.Hash{} }
-func (m *MockStateDB) GetCode(common.Address) []byte                                      { return nil }
-func (m *MockStateDB) SetCode(common.Address, []byte)                                     {}
-func (m *MockStateDB) GetCodeSize(common.Address) int                                     { return 0 }
-func (m *MockStateDB) AddRefund(uint64)                                                   {}
-func (m *MockStateDB) SubRefund(uint64)                                                   {}
-func (m *MockStateDB) GetRefund() uint64                                                  { return 0 }
-func (m *MockStateDB) GetCommittedState(common.Address, common.Hash) common.Hash          { return common.Hash{} }
-func (m *MockStateDB) GetState(common.Address, common.Hash) common.Hash                   { return common.Hash{} }
-func (m *MockStateDB) SetState(common.Address, common.Hash, common.Hash)                  {}
-func (m *MockStateDB) Suicide(common.Address) bool                                        { return false }
-func (m *MockStateDB) HasSuicided(common.Address) bool                                    { return false }
-func (m *MockStateDB) Exist(common.Address) bool                                          { return true }
-func (m *MockStateDB) Empty(common.Address) bool                                          { return false }
-func (m *MockStateDB) PrepareAccessList(common.Address, []common.Address, []common.Hash)  {}
-func (m *MockStateDB) AddressInAccessList(common.Address) bool                            { return false }
-func (m *MockStateDB) SlotInAccessList(common.Address, common.Hash) (bool, bool)          { return false, false }
-func (m *MockStateDB) AddAddressToAccessList(common.Address)                              {}
-func (m *MockStateDB) AddSlotToAccessList(common.Address, common.Hash)                    {}
-func (m *MockStateDB) RevertToSnapshot(int)                                               {}
-func (m *MockStateDB) Snapshot() int                                                      { return 0 }
-func (m *MockStateDB) AddLog(*types.Log)                                                  {}
-func (m *MockStateDB) AddPreimage(common.Hash, []byte)                                    {}
-func (m *MockStateDB) ForEachStorage(common.Address, func(common.Hash, common.Hash) bool) error { return nil }
-func (m *MockStateDB) GetTransientState(addr common.Address, key common.Hash) common.Hash { return common.Hash{} }
-func (m *MockStateDB) SetTransientState(addr common.Address, key, value common.Hash)      {}
-func (m *MockStateDB) Prepare(rules params.Rules, sender, coinbase common.Address, dest *common.Address, precompiles []common.Address, txAccesses types.AccessList) {
-}
-func (m *MockStateDB) ProcessAccessList(rules params.Rules, sender, coinbase common.Address, dest *common.Address, precompiles []common.Address, txAccesses types.AccessList) {
-} 
\ No newline at end of file
+// ValidateRuntimeCode validates the runtime code returned by a constructor
+func (v *VM) ValidateRuntimeCode(code []byte) error {
+	return ValidateRuntimeCode(code, v.validation)
+}
+
+// SetValidationConfig replaces the checks run on contract code on deploy
+func (v *VM) SetValidationConfig(config ValidationConfig) {
+	v.validation = config
+}
+
+// canTransfer checks whether the account has enough balance for the transfer
+func canTransfer(db vm.StateDB, address common.Address, amount *big.Int) bool {
+	return db.GetBalance(address).Cmp(amount) >= 0
+}
+
+// transfer moves value between two accounts
+func transfer(db vm.StateDB, sender, recipient common.Address, amount *big.Int) {
+	db.SubBalance(sender, amount)
+	db.AddBalance(recipient, amount)
+}
@@ -0,0 +1,554 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxFeeHistory is the largest number of blocks eth_feeHistory reports on
+const maxFeeHistory = 1024
+
+// EthAPI implements the eth_ namespace on top of the blockchain, its mempool and the
+// contract VM. Blocks are final once added, so the safe and finalized tags refer to the
+// latest block, and pending refers to the latest block except for account nonces.
+type EthAPI struct {
+	blockchain *blockchain.Blockchain
+}
+
+// NewEthAPI creates the eth_ API of the blockchain
+func NewEthAPI(bc *blockchain.Blockchain) *EthAPI {
+	return &EthAPI{blockchain: bc}
+}
+
+// revertError is returned for reverted calls; the revert data is the error data
+type revertError struct {
+	error
+	data []byte
+}
+
+// ErrorCode returns the JSON-RPC error code of reverted executions
+func (e *revertError) ErrorCode() int {
+	return 3
+}
+
+// ErrorData returns the revert data
+func (e *revertError) ErrorData() interface{} {
+	return hexutil.Encode(e.data)
+}
+
+// ChainId returns the chain ID transactions must be signed for
+func (api *EthAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int).Set(api.blockchain.ChainConfig().ChainID))
+}
+
+// BlockNumber returns the height of the latest block
+func (api *EthAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.latestHeight())
+}
+
+// Syncing returns false; blocks are synchronized from peers as they are produced
+func (api *EthAPI) Syncing() bool {
+	return false
+}
+
+// Accounts returns no accounts; the node does not hold user keys
+func (api *EthAPI) Accounts() []common.Address {
+	return []common.Address{}
+}
+
+// GasPrice returns the minimum gas price set in the fee parameters system contract
+func (api *EthAPI) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(contracts.MinGasPrice(contracts.NewStateDB(api.blockchain.ContractManager.State())))
+}
+
+// MaxPriorityFeePerGas returns the tip dynamic fee transactions should pay. Blocks have no
+// base fee, so this is the minimum gas price.
+func (api *EthAPI) MaxPriorityFeePerGas() *hexutil.Big {
+	return api.GasPrice()
+}
+
+// FeeHistory reports a zero base fee for the requested blocks, with the minimum gas price
+// as the reward at every percentile
+func (api *EthAPI) FeeHistory(blockCount math.HexOrDecimal64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (map[string]interface{}, error) {
+	last, err := api.blockHeight(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+	count := uint64(blockCount)
+	if count > maxFeeHistory {
+		count = maxFeeHistory
+	}
+	if count > last+1 {
+		count = last + 1
+	}
+
+	oldest := last + 1 - count
+	price := api.GasPrice()
+	baseFees := make([]*hexutil.Big, count+1)
+	ratios := make([]float64, count)
+	rewards := make([][]*hexutil.Big, count)
+	for i := range baseFees {
+		baseFees[i] = (*hexutil.Big)(new(big.Int))
+	}
+	for i := uint64(0); i < count; i++ {
+		block := api.blockchain.GetBlockByHeight(oldest + i)
+		if block != nil && block.Header.GasLimit > 0 {
+			ratios[i] = float64(api.gasUsed(block)) / float64(block.Header.GasLimit)
+		}
+		rewards[i] = make([]*hexutil.Big, len(rewardPercentiles))
+		for j := range rewardPercentiles {
+			rewards[i][j] = price
+		}
+	}
+
+	result := map[string]interface{}{
+		"oldestBlock":   hexutil.Uint64(oldest),
+		"baseFeePerGas": baseFees,
+		"gasUsedRatio":  ratios,
+	}
+	if len(rewardPercentiles) > 0 {
+		result["reward"] = rewards
+	}
+	return result, nil
+}
+
+// GetBalance returns the balance of an account at the given block (the latest if omitted)
+func (api *EthAPI) GetBalance(address common.Address, blockNrOrHash *rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	state, err := api.stateAt(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(state.GetBalance(address)), nil
+}
+
+// GetTransactionCount returns the nonce of an account at the given block. The pending
+// nonce includes the account's transactions in the mempool.
+func (api *EthAPI) GetTransactionCount(address common.Address, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	if blockNrOrHash != nil {
+		if number, ok := blockNrOrHash.Number(); ok && number == rpc.PendingBlockNumber {
+			return hexutil.Uint64(api.blockchain.GetNonce(address.Hex())), nil
+		}
+	}
+	state, err := api.stateAt(blockNrOrHash)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(contracts.NewStateDB(state).GetNonce(address)), nil
+}
+
+// GetCode returns the code of an account at the given block
+func (api *EthAPI) GetCode(address common.Address, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	height, err := api.stateHeight(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return api.blockchain.GetCodeAt(address, height)
+}
+
+// GetStorageAt returns a storage slot of an account at the given block
+func (api *EthAPI) GetStorageAt(address common.Address, slot string, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	// Slot hem tam hash hem de quantity ("0x0") olarak verilebilir
+	key := strings.TrimPrefix(slot, "0x")
+	if len(key)%2 == 1 {
+		key = "0" + key
+	}
+	decoded, err := hex.DecodeString(key)
+	if err != nil || len(decoded) > common.HashLength {
+		return nil, fmt.Errorf("invalid storage slot %s", slot)
+	}
+
+	height, err := api.stateHeight(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	value, err := api.blockchain.GetStorageAt(address, common.BytesToHash(decoded), height)
+	if err != nil {
+		return nil, err
+	}
+	return value.Bytes(), nil
+}
+
+// Call executes a call or deployment against the state at the given block without
+// creating a transaction
+func (api *EthAPI) Call(args CallArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	height, err := api.stateHeight(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	result, err := api.blockchain.Call(args.message(), height)
+	if err != nil {
+		return nil, err
+	}
+	if result.Failed() {
+		return nil, executionError(result)
+	}
+	return result.ReturnData, nil
+}
+
+// EstimateGas returns the lowest gas limit the call or deployment succeeds with
+func (api *EthAPI) EstimateGas(args CallArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	height, err := api.stateHeight(blockNrOrHash)
+	if err != nil {
+		return 0, err
+	}
+	msg := args.message()
+	gas, err := api.blockchain.EstimateGas(msg, height)
+	if err != nil {
+		// Revert verisi eth_call ile aynı biçimde döndürülür
+		if errors.Is(err, vm.ErrExecutionReverted) {
+			if result, callErr := api.blockchain.Call(msg, height); callErr == nil && result.Failed() {
+				return 0, executionError(result)
+			}
+		}
+		return 0, err
+	}
+	return hexutil.Uint64(gas), nil
+}
+
+// SendRawTransaction adds a signed Ethereum transaction to the mempool and returns its hash
+func (api *EthAPI) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	tx, err := api.blockchain.SendRawTransaction(input)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(tx.Hash), nil
+}
+
+// GetTransactionByHash returns an included or pending transaction, nil if it is unknown
+func (api *EthAPI) GetTransactionByHash(hash common.Hash) *RPCTransaction {
+	tx, block, index := api.blockchain.GetTransaction(hash.Bytes())
+	if tx == nil {
+		return nil
+	}
+	return newRPCTransaction(tx, block, index)
+}
+
+// GetTransactionReceipt returns the receipt of an included transaction, nil if it is not
+// included yet
+func (api *EthAPI) GetTransactionReceipt(hash common.Hash) map[string]interface{} {
+	tx, block, index := api.blockchain.GetTransaction(hash.Bytes())
+	if tx == nil || block == nil {
+		return nil
+	}
+	receipt, exists := api.blockchain.GetReceipt(receiptKey(tx.Hash))
+	if !exists {
+		return nil
+	}
+
+	// Kümülatif gas bloktaki önceki işlemlerin receipt'lerinden hesaplanır
+	cumulativeGas := uint64(0)
+	for _, previous := range block.Transactions[:index+1] {
+		if r, ok := api.blockchain.GetReceipt(receiptKey(previous.Hash)); ok {
+			cumulativeGas += r.GasUsed
+		}
+	}
+
+	logs := receipt.Logs
+	if logs == nil {
+		logs = []*types.Log{}
+	}
+	status := hexutil.Uint64(types.ReceiptStatusFailed)
+	if receipt.Status == blockchain.TxSuccess {
+		status = hexutil.Uint64(types.ReceiptStatusSuccessful)
+	}
+	result := map[string]interface{}{
+		"transactionHash":   common.BytesToHash(tx.Hash),
+		"transactionIndex":  hexutil.Uint64(index),
+		"blockHash":         common.BytesToHash(block.GetHash()),
+		"blockNumber":       hexutil.Uint64(block.Header.Height),
+		"from":              common.HexToAddress(tx.From),
+		"to":                nil,
+		"contractAddress":   nil,
+		"cumulativeGasUsed": hexutil.Uint64(cumulativeGas),
+		"gasUsed":           hexutil.Uint64(receipt.GasUsed),
+		"effectiveGasPrice": (*hexutil.Big)(new(big.Int).SetUint64(tx.GasPrice)),
+		"logs":              logs,
+		"logsBloom":         types.BytesToBloom(types.LogsBloom(logs)),
+		"status":            status,
+		"type":              hexutil.Uint64(transactionType(tx)),
+	}
+	if !tx.IsContractCreation() {
+		result["to"] = common.HexToAddress(tx.To)
+	}
+	if receipt.ContractAddress != "" {
+		result["contractAddress"] = common.HexToAddress(receipt.ContractAddress)
+	}
+	return result
+}
+
+// GetBlockByNumber returns a block with its transaction hashes, or the full transactions
+// if fullTx is set; nil if the block does not exist
+func (api *EthAPI) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) map[string]interface{} {
+	height, err := api.blockHeight(number)
+	if err != nil {
+		return nil
+	}
+	return api.marshalBlock(api.blockchain.GetBlockByHeight(height), fullTx)
+}
+
+// GetBlockByHash returns a block by hash; nil if it does not exist
+func (api *EthAPI) GetBlockByHash(hash common.Hash, fullTx bool) map[string]interface{} {
+	return api.marshalBlock(api.blockchain.GetBlock(hash.Bytes()), fullTx)
+}
+
+// GetBlockTransactionCountByNumber returns the number of transactions in a block
+func (api *EthAPI) GetBlockTransactionCountByNumber(number rpc.BlockNumber) *hexutil.Uint {
+	height, err := api.blockHeight(number)
+	if err != nil {
+		return nil
+	}
+	return transactionCount(api.blockchain.GetBlockByHeight(height))
+}
+
+// GetBlockTransactionCountByHash returns the number of transactions in a block
+func (api *EthAPI) GetBlockTransactionCountByHash(hash common.Hash) *hexutil.Uint {
+	return transactionCount(api.blockchain.GetBlock(hash.Bytes()))
+}
+
+// GetLogs returns the logs matching the filter
+func (api *EthAPI) GetLogs(query FilterQuery) ([]*types.Log, error) {
+	filter, err := api.logFilter(&query)
+	if err != nil {
+		return nil, err
+	}
+	return api.blockchain.GetLogs(filter)
+}
+
+// NewHeads notifies the subscriber of the header of every new block (eth_subscribe newHeads)
+func (api *EthAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, blockchain.EventNewBlock, func(event blockchain.Event) (interface{}, bool) {
+		block, ok := event.Data["block"].(*blockchain.Block)
+		if !ok {
+			return nil, false
+		}
+		return api.marshalHeader(block), true
+	})
+}
+
+// Logs notifies the subscriber of the logs of new blocks that match the filter
+// (eth_subscribe logs)
+func (api *EthAPI) Logs(ctx context.Context, query *FilterQuery) (*rpc.Subscription, error) {
+	filter := &blockchain.LogFilter{}
+	if query != nil {
+		filter.Addresses, filter.Topics = query.Addresses, query.Topics
+	}
+	return api.subscribe(ctx, blockchain.EventContractLog, func(event blockchain.Event) (interface{}, bool) {
+		log, ok := event.Data["log"].(*types.Log)
+		if !ok || !filter.Matches(log) {
+			return nil, false
+		}
+		return log, true
+	})
+}
+
+// subscribe forwards the blockchain events of the given type to a subscription until the
+// client unsubscribes or disconnects. Events convert returns false for are skipped.
+func (api *EthAPI) subscribe(ctx context.Context, eventType blockchain.EventType, convert func(blockchain.Event) (interface{}, bool)) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	subscription := notifier.CreateSubscription()
+	events := api.blockchain.EventEmitter.Subscribe(eventType)
+
+	go func() {
+		defer api.blockchain.EventEmitter.Unsubscribe(eventType, events)
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if data, ok := convert(event); ok {
+					notifier.Notify(subscription.ID, data)
+				}
+			case <-subscription.Err():
+				return
+			}
+		}
+	}()
+	return subscription, nil
+}
+
+// latestHeight returns the height of the latest block
+func (api *EthAPI) latestHeight() uint64 {
+	return api.blockchain.GetLatestBlock().Header.Height
+}
+
+// blockHeight resolves a block number or tag to a block height
+func (api *EthAPI) blockHeight(number rpc.BlockNumber) (uint64, error) {
+	latest := api.latestHeight()
+	switch number {
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber, rpc.SafeBlockNumber, rpc.FinalizedBlockNumber:
+		return latest, nil
+	case rpc.EarliestBlockNumber:
+		return 0, nil
+	}
+	if number < 0 || uint64(number) > latest {
+		return 0, fmt.Errorf("block %d not found", number)
+	}
+	return uint64(number), nil
+}
+
+// stateHeight resolves a block number, tag or hash to the height of the state after that
+// block, nil for the current state
+func (api *EthAPI) stateHeight(blockNrOrHash *rpc.BlockNumberOrHash) (*uint64, error) {
+	if blockNrOrHash == nil {
+		return nil, nil
+	}
+
+	var height uint64
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		block := api.blockchain.GetBlock(hash.Bytes())
+		if block == nil {
+			return nil, fmt.Errorf("block %s not found", hash.Hex())
+		}
+		height = block.Header.Height
+	} else {
+		number, _ := blockNrOrHash.Number()
+		resolved, err := api.blockHeight(number)
+		if err != nil {
+			return nil, err
+		}
+		height = resolved
+	}
+
+	if height == api.latestHeight() {
+		return nil, nil
+	}
+	return &height, nil
+}
+
+// stateAt returns the state after the given block, the current state if omitted
+func (api *EthAPI) stateAt(blockNrOrHash *rpc.BlockNumberOrHash) (*contracts.WorldState, error) {
+	height, err := api.stateHeight(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return api.blockchain.StateAt(height)
+}
+
+// logFilter converts a filter query to a log filter over block heights
+func (api *EthAPI) logFilter(query *FilterQuery) (*blockchain.LogFilter, error) {
+	filter := &blockchain.LogFilter{Addresses: query.Addresses, Topics: query.Topics}
+	if query.BlockHash != nil {
+		block := api.blockchain.GetBlock(query.BlockHash.Bytes())
+		if block == nil {
+			return nil, fmt.Errorf("block %s not found", query.BlockHash.Hex())
+		}
+		height := block.Header.Height
+		filter.FromBlock, filter.ToBlock = &height, &height
+		return filter, nil
+	}
+
+	if query.FromBlock != nil {
+		from, err := api.blockHeight(*query.FromBlock)
+		if err != nil {
+			return nil, err
+		}
+		filter.FromBlock = &from
+	} else {
+		// Başlangıç bloğu verilmezse yalnızca son blok taranır
+		latest := api.latestHeight()
+		filter.FromBlock = &latest
+	}
+	if query.ToBlock != nil && *query.ToBlock >= 0 {
+		to, err := api.blockHeight(*query.ToBlock)
+		if err != nil {
+			return nil, err
+		}
+		filter.ToBlock = &to
+	}
+	return filter, nil
+}
+
+// gasUsed returns the gas used by the transactions of a block
+func (api *EthAPI) gasUsed(block *blockchain.Block) uint64 {
+	gasUsed := uint64(0)
+	for _, tx := range block.Transactions {
+		if receipt, ok := api.blockchain.GetReceipt(receiptKey(tx.Hash)); ok {
+			gasUsed += receipt.GasUsed
+		}
+	}
+	return gasUsed
+}
+
+// marshalHeader returns the header fields of a block in the JSON-RPC format. Blocks have
+// no proof of work, uncles or base fee; those fields are zero.
+func (api *EthAPI) marshalHeader(block *blockchain.Block) map[string]interface{} {
+	header := block.Header
+	result := map[string]interface{}{
+		"number":           hexutil.Uint64(header.Height),
+		"hash":             common.BytesToHash(block.GetHash()),
+		"parentHash":       common.BytesToHash(header.PrevHash),
+		"nonce":            types.BlockNonce{},
+		"mixHash":          common.Hash{},
+		"sha3Uncles":       types.EmptyUncleHash,
+		"logsBloom":        types.BytesToBloom(header.LogsBloom),
+		"transactionsRoot": common.BytesToHash(header.TransactionRoot),
+		"stateRoot":        common.BytesToHash(header.StateRoot),
+		"receiptsRoot":     common.BytesToHash(header.ReceiptRoot),
+		"miner":            header.Coinbase(),
+		"difficulty":       (*hexutil.Big)(new(big.Int)),
+		"extraData":        hexutil.Bytes{},
+		"gasLimit":         hexutil.Uint64(header.GasLimit),
+		"gasUsed":          hexutil.Uint64(api.gasUsed(block)),
+		"timestamp":        hexutil.Uint64(header.Timestamp.Unix()),
+	}
+	if api.blockchain.ChainConfig().IsLondon(new(big.Int).SetUint64(header.Height)) {
+		result["baseFeePerGas"] = (*hexutil.Big)(new(big.Int))
+	}
+	return result
+}
+
+// marshalBlock returns a block in the JSON-RPC format, nil for a nil block
+func (api *EthAPI) marshalBlock(block *blockchain.Block, fullTx bool) map[string]interface{} {
+	if block == nil {
+		return nil
+	}
+	result := api.marshalHeader(block)
+	result["size"] = hexutil.Uint64(block.GetBlockSize())
+	result["totalDifficulty"] = (*hexutil.Big)(new(big.Int))
+	result["uncles"] = []common.Hash{}
+
+	transactions := make([]interface{}, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		if fullTx {
+			transactions[i] = newRPCTransaction(tx, block, i)
+		} else {
+			transactions[i] = common.BytesToHash(tx.Hash)
+		}
+	}
+	result["transactions"] = transactions
+	return result
+}
+
+// transactionCount returns the number of transactions in a block, nil for a nil block
+func transactionCount(block *blockchain.Block) *hexutil.Uint {
+	if block == nil {
+		return nil
+	}
+	count := hexutil.Uint(len(block.Transactions))
+	return &count
+}
+
+// executionError returns the error of a failed call; reverts carry the revert data
+func executionError(result *contracts.ExecutionResult) error {
+	if errors.Is(result.Err, vm.ErrExecutionReverted) {
+		return &revertError{error: result.Err, data: result.ReturnData}
+	}
+	return result.Err
+}
@@ -0,0 +1,237 @@
+package ethrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// answerInitCode 42 döndüren kontratı deploy eden init code
+var answerInitCode = common.FromHex("69" + "602a60005260206000f3" + "600052600a6016f3")
+
+// newTestChain bir validator'lı blockchain ve JSON-RPC sunucusu oluşturur
+func newTestChain(t *testing.T) (*blockchain.Blockchain, *validator.Authority, *httptest.Server) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	v, err := validator.NewAuthority(key)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	bc, err := blockchain.NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+	server, err := NewServer(bc, nil, DefaultConfig())
+	if err != nil {
+		t.Fatalf("JSON-RPC sunucusu oluşturulamadı: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return bc, v, httpServer
+}
+
+// produceBlock bekleyen işlemlerden bir blok üretip zincire ekler
+func produceBlock(t *testing.T, bc *blockchain.Blockchain, v *validator.Authority) {
+	block, err := bc.CreateBlock(v)
+	if err != nil {
+		t.Fatalf("Blok oluşturulamadı: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("Blok eklenemedi: %v", err)
+	}
+}
+
+// TestEthereumClient go-ethereum istemcisinin işlem gönderip sonuçlarını okuyabildiğini test eder
+func TestEthereumClient(t *testing.T) {
+	bc, v, server := newTestChain(t)
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatalf("İstemci bağlanamadı: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil || chainID.Cmp(contracts.DefaultChainID) != 0 {
+		t.Fatalf("Chain ID hatalı: %v %v", chainID, err)
+	}
+
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	bc.ContractManager.State().SetAccount(sender, &contracts.Account{Balance: big.NewInt(1e18)})
+
+	// Kontratı imzalı bir işlemle deploy et
+	gas, err := client.EstimateGas(ctx, ethereum.CallMsg{From: sender, Data: answerInitCode})
+	if err != nil {
+		t.Fatalf("Gas tahmin edilemedi: %v", err)
+	}
+	nonce, err := client.PendingNonceAt(ctx, sender)
+	if err != nil || nonce != 0 {
+		t.Fatalf("Nonce hatalı: %d %v", nonce, err)
+	}
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(1), Gas: gas, Data: answerInitCode})
+	if err != nil {
+		t.Fatalf("İşlem imzalanamadı: %v", err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("İşlem gönderilemedi: %v", err)
+	}
+	if _, pending, err := client.TransactionByHash(ctx, tx.Hash()); err != nil || !pending {
+		t.Errorf("Bekleyen işlem bulunamadı: %v", err)
+	}
+
+	produceBlock(t, bc, v)
+
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatalf("Receipt alınamadı: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful || receipt.BlockNumber.Uint64() != 1 {
+		t.Fatalf("Deploy başarısız: %+v", receipt)
+	}
+	if expected := crypto.CreateAddress(sender, 0); receipt.ContractAddress != expected {
+		t.Errorf("Kontrat adresi hatalı: %s", receipt.ContractAddress.Hex())
+	}
+
+	// Kontrat çağrısı ve state sorguları
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &receipt.ContractAddress}, nil)
+	if err != nil || new(big.Int).SetBytes(output).Int64() != 42 {
+		t.Errorf("Çağrı sonucu hatalı: %x %v", output, err)
+	}
+	code, err := client.CodeAt(ctx, receipt.ContractAddress, nil)
+	if err != nil || len(code) != 10 {
+		t.Errorf("Kontrat kodu hatalı: %x %v", code, err)
+	}
+	balance, err := client.BalanceAt(ctx, sender, nil)
+	expected := new(big.Int).Sub(big.NewInt(1e18), new(big.Int).SetUint64(receipt.GasUsed))
+	if err != nil || balance.Cmp(expected) != 0 {
+		t.Errorf("Bakiye hatalı: %s %v", balance, err)
+	}
+
+	// Blok Ethereum formatında okunabilmeli
+	block, err := client.BlockByNumber(ctx, nil)
+	if err != nil {
+		t.Fatalf("Blok alınamadı: %v", err)
+	}
+	if block.NumberU64() != 1 || len(block.Transactions()) != 1 || block.Transactions()[0].Hash() != tx.Hash() {
+		t.Errorf("Blok hatalı: %d %d", block.NumberU64(), len(block.Transactions()))
+	}
+	if number, err := client.BlockNumber(ctx); err != nil || number != 1 {
+		t.Errorf("Blok yüksekliği hatalı: %d %v", number, err)
+	}
+
+	// Önceki blok yüksekliğinde kontrat yoktu
+	if code, err := client.CodeAt(ctx, receipt.ContractAddress, big.NewInt(0)); err != nil || len(code) != 0 {
+		t.Errorf("Eski state'te kod bulundu: %x %v", code, err)
+	}
+}
+
+// TestRevertedCall revert edilen çağrıların revert verisiyle döndüğünü test eder
+func TestRevertedCall(t *testing.T) {
+	_, _, server := newTestChain(t)
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatalf("İstemci bağlanamadı: %v", err)
+	}
+	defer client.Close()
+
+	// Her çağrıyı revert eden init code
+	_, err = client.CallContract(context.Background(), ethereum.CallMsg{Data: common.FromHex("60006000fd")}, nil)
+	if err == nil || !strings.Contains(err.Error(), "execution reverted") {
+		t.Errorf("Revert hatası bekleniyordu: %v", err)
+	}
+}
+
+// TestNewHeadsSubscription WebSocket üzerinden yeni blok başlıklarına abone olunabildiğini test eder
+func TestNewHeadsSubscription(t *testing.T) {
+	bc, v, server := newTestChain(t)
+	client, err := ethclient.Dial("ws" + strings.TrimPrefix(server.URL, "http"))
+	if err != nil {
+		t.Fatalf("WebSocket bağlantısı kurulamadı: %v", err)
+	}
+	defer client.Close()
+
+	heads := make(chan *types.Header, 1)
+	subscription, err := client.SubscribeNewHead(context.Background(), heads)
+	if err != nil {
+		t.Fatalf("Abonelik oluşturulamadı: %v", err)
+	}
+	defer subscription.Unsubscribe()
+
+	produceBlock(t, bc, v)
+	select {
+	case head := <-heads:
+		if head.Number.Uint64() != 1 || head.Coinbase != bc.GetLatestBlock().Header.Coinbase() {
+			t.Errorf("Blok başlığı hatalı: %+v", head)
+		}
+	case err := <-subscription.Err():
+		t.Fatalf("Abonelik hatası: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Yeni blok bildirimi gelmedi")
+	}
+}
+
+// TestAllowedOrigins yalnızca izin verilen tarayıcı origin'lerinin API'yi kullanabildiğini test eder
+func TestAllowedOrigins(t *testing.T) {
+	_, _, server := newTestChain(t)
+	body := `{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`
+
+	tests := []struct {
+		origin string
+		status int
+	}{
+		{"", http.StatusOK},
+		{"http://localhost:3000", http.StatusOK},
+		{"http://127.0.0.1", http.StatusOK},
+		{"https://localhost", http.StatusForbidden},
+		{"http://evil.example", http.StatusForbidden},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if test.origin != "" {
+			req.Header.Set("Origin", test.origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("İstek gönderilemedi: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.status {
+			t.Errorf("%q: %d bekleniyordu, %d alındı", test.origin, test.status, resp.StatusCode)
+		}
+		if allowed := resp.Header.Get("Access-Control-Allow-Origin"); allowed != "" && allowed != test.origin {
+			t.Errorf("%q: CORS başlığı hatalı: %s", test.origin, allowed)
+		}
+	}
+
+	// İzin verilmeyen origin'den WebSocket bağlantısı kurulamaz
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	if _, err := rpc.DialWebsocket(context.Background(), wsURL, "http://evil.example"); err == nil {
+		t.Error("İzin verilmeyen origin'den WebSocket bağlantısı kuruldu")
+	}
+	client, err := rpc.DialWebsocket(context.Background(), wsURL, "http://localhost:3000")
+	if err != nil {
+		t.Fatalf("Yerel origin'den WebSocket bağlantısı kurulamadı: %v", err)
+	}
+	client.Close()
+}
@@ -0,0 +1,167 @@
+// Package ethrpc serves the Ethereum JSON-RPC API (eth_, net_ and web3_ namespaces) over
+// HTTP and WebSocket, so that Ethereum tooling such as Hardhat, ethers.js and MetaMask can
+// use the chain.
+package ethrpc
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+	"github.com/SolidityDevSK/Confirmix/pkg/network"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Config configures the JSON-RPC server
+type Config struct {
+	// AllowedOrigins are the browser origins that may use the API over CORS and WebSocket,
+	// e.g. "http://localhost" (any port) or "https://app.example.com:8443"; "*" allows
+	// every origin. Requests without an Origin header do not come from browsers and are
+	// always served.
+	AllowedOrigins []string
+}
+
+// DefaultConfig allows browser origins on localhost only
+func DefaultConfig() Config {
+	return Config{AllowedOrigins: []string{"http://localhost", "http://127.0.0.1"}}
+}
+
+// Server serves the JSON-RPC API of a blockchain
+type Server struct {
+	rpc            *rpc.Server
+	websocket      http.Handler
+	allowedOrigins []string
+}
+
+// NewServer creates a JSON-RPC server for the blockchain. The node is used for the peer
+// count and may be nil.
+func NewServer(bc *blockchain.Blockchain, node *network.Node, config Config) (*Server, error) {
+	server := rpc.NewServer()
+	services := map[string]interface{}{
+		"eth":  NewEthAPI(bc),
+		"net":  &NetAPI{blockchain: bc, node: node},
+		"web3": &Web3API{},
+	}
+	for namespace, service := range services {
+		if err := server.RegisterName(namespace, service); err != nil {
+			return nil, fmt.Errorf("%s API could not be registered: %v", namespace, err)
+		}
+	}
+	if len(config.AllowedOrigins) == 0 {
+		config = DefaultConfig()
+	}
+	return &Server{
+		rpc:            server,
+		websocket:      server.WebsocketHandler(config.AllowedOrigins),
+		allowedOrigins: config.AllowedOrigins,
+	}, nil
+}
+
+// ServeHTTP handles JSON-RPC requests over HTTP and upgrades WebSocket connections, which
+// also support eth_subscribe
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Tarayıcı cüzdanları ve dApp'ler yalnızca izin verilen origin'lerden bağlanabilir
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if !s.allowsOrigin(origin) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS")
+		w.Header().Add("Vary", "Origin")
+	}
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		s.websocket.ServeHTTP(w, r)
+		return
+	}
+	s.rpc.ServeHTTP(w, r)
+}
+
+// allowsOrigin reports whether a browser origin matches one of the allowed origins. The
+// scheme, host name and, if the allowed origin has one, the port must match.
+func (s *Server) allowsOrigin(origin string) bool {
+	browser, err := url.Parse(strings.ToLower(origin))
+	if err != nil || browser.Host == "" {
+		return false
+	}
+	for _, allowed := range s.allowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+		rule, err := url.Parse(strings.ToLower(allowed))
+		if err != nil {
+			continue
+		}
+		if rule.Scheme == browser.Scheme && rule.Hostname() == browser.Hostname() &&
+			(rule.Port() == "" || rule.Port() == browser.Port()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run serves the JSON-RPC API on the given address
+func (s *Server) Run(addr string) error {
+	return http.ListenAndServe(addr, s)
+}
+
+// Stop closes the open connections and subscriptions
+func (s *Server) Stop() {
+	s.rpc.Stop()
+}
+
+// NetAPI implements the net_ namespace
+type NetAPI struct {
+	blockchain *blockchain.Blockchain
+	node       *network.Node
+}
+
+// Version returns the chain ID as a decimal string
+func (api *NetAPI) Version() string {
+	return api.blockchain.ChainConfig().ChainID.String()
+}
+
+// Listening reports whether the node accepts peer connections
+func (api *NetAPI) Listening() bool {
+	return api.node != nil
+}
+
+// PeerCount returns the number of connected peers. Peers the node only keeps the score
+// of are not counted.
+func (api *NetAPI) PeerCount() hexutil.Uint {
+	if api.node == nil {
+		return 0
+	}
+
+	count := 0
+	for _, peer := range api.node.GetPeers() {
+		if peer.Connected {
+			count++
+		}
+	}
+	return hexutil.Uint(count)
+}
+
+// Web3API implements the web3_ namespace
+type Web3API struct{}
+
+// ClientVersion returns the name and platform of the node software
+func (api *Web3API) ClientVersion() string {
+	return fmt.Sprintf("Confirmix/%s-%s/%s", runtime.GOOS, runtime.GOARCH, runtime.Version())
+}
+
+// Sha3 returns the Keccak-256 hash of the input
+func (api *Web3API) Sha3(input hexutil.Bytes) hexutil.Bytes {
+	return crypto.Keccak256(input)
+}
@@ -0,0 +1,206 @@
+package ethrpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+	"github.com/SolidityDevSK/Confirmix/pkg/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CallArgs are the arguments of eth_call and eth_estimateGas
+type CallArgs struct {
+	From                 *common.Address `json:"from"`
+	To                   *common.Address `json:"to"` // nil simulates a deployment
+	Gas                  *hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 *hexutil.Bytes  `json:"data"`
+	Input                *hexutil.Bytes  `json:"input"` // Preferred over data if both are set
+}
+
+// message builds the message simulated for the arguments. Dynamic fee arguments pay the
+// lower of the fee cap and tip cap, like signed transactions.
+func (args *CallArgs) message() *contracts.Message {
+	msg := &contracts.Message{To: args.To, Value: new(big.Int), GasPrice: new(big.Int)}
+	if args.From != nil {
+		msg.From = *args.From
+	}
+	if args.Gas != nil {
+		msg.GasLimit = uint64(*args.Gas)
+	}
+	if args.Value != nil {
+		msg.Value = args.Value.ToInt()
+	}
+	if args.Input != nil {
+		msg.Data = *args.Input
+	} else if args.Data != nil {
+		msg.Data = *args.Data
+	}
+
+	switch {
+	case args.GasPrice != nil:
+		msg.GasPrice = args.GasPrice.ToInt()
+	case args.MaxPriorityFeePerGas != nil:
+		msg.GasPrice = args.MaxPriorityFeePerGas.ToInt()
+		if args.MaxFeePerGas != nil && args.MaxFeePerGas.ToInt().Cmp(msg.GasPrice) < 0 {
+			msg.GasPrice = args.MaxFeePerGas.ToInt()
+		}
+	case args.MaxFeePerGas != nil:
+		msg.GasPrice = args.MaxFeePerGas.ToInt()
+	}
+	return msg
+}
+
+// FilterQuery selects logs for eth_getLogs and logs subscriptions
+type FilterQuery struct {
+	BlockHash *common.Hash     // Only the logs of this block, excludes FromBlock and ToBlock
+	FromBlock *rpc.BlockNumber // Latest block if nil
+	ToBlock   *rpc.BlockNumber // Latest block if nil
+	Addresses []common.Address
+	Topics    [][]common.Hash
+}
+
+// UnmarshalJSON decodes a filter whose address is a single address or a list and whose
+// topics are each null, a single topic or a list of alternatives
+func (q *FilterQuery) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		BlockHash *common.Hash      `json:"blockHash"`
+		FromBlock *rpc.BlockNumber  `json:"fromBlock"`
+		ToBlock   *rpc.BlockNumber  `json:"toBlock"`
+		Address   json.RawMessage   `json:"address"`
+		Topics    []json.RawMessage `json:"topics"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.BlockHash != nil && (raw.FromBlock != nil || raw.ToBlock != nil) {
+		return fmt.Errorf("blockHash cannot be combined with fromBlock or toBlock")
+	}
+	q.BlockHash, q.FromBlock, q.ToBlock = raw.BlockHash, raw.FromBlock, raw.ToBlock
+
+	addresses, err := decodeOneOrMany[common.Address](raw.Address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %v", err)
+	}
+	q.Addresses = addresses
+
+	q.Topics = make([][]common.Hash, len(raw.Topics))
+	for i, topic := range raw.Topics {
+		if q.Topics[i], err = decodeOneOrMany[common.Hash](topic); err != nil {
+			return fmt.Errorf("invalid topic %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// decodeOneOrMany decodes null, a single value or a list of values
+func decodeOneOrMany[T any](data json.RawMessage) ([]T, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	if data[0] == '[' {
+		var values []T
+		err := json.Unmarshal(data, &values)
+		return values, err
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return []T{value}, nil
+}
+
+// RPCTransaction is a transaction in the JSON-RPC format
+type RPCTransaction struct {
+	BlockHash            *common.Hash      `json:"blockHash"`
+	BlockNumber          *hexutil.Big      `json:"blockNumber"`
+	TransactionIndex     *hexutil.Uint64   `json:"transactionIndex"`
+	Hash                 common.Hash       `json:"hash"`
+	Type                 hexutil.Uint64    `json:"type"`
+	From                 common.Address    `json:"from"`
+	To                   *common.Address   `json:"to"`
+	Nonce                hexutil.Uint64    `json:"nonce"`
+	Gas                  hexutil.Uint64    `json:"gas"`
+	GasPrice             *hexutil.Big      `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big      `json:"value"`
+	Input                hexutil.Bytes     `json:"input"`
+	AccessList           *types.AccessList `json:"accessList,omitempty"`
+	ChainID              *hexutil.Big      `json:"chainId,omitempty"`
+	V                    *hexutil.Big      `json:"v"`
+	R                    *hexutil.Big      `json:"r"`
+	S                    *hexutil.Big      `json:"s"`
+}
+
+// newRPCTransaction converts a transaction; block is nil for pending transactions.
+// Transactions submitted through the REST API are unsigned and have a zero signature.
+func newRPCTransaction(tx *blockchain.Transaction, block *blockchain.Block, index int) *RPCTransaction {
+	result := &RPCTransaction{
+		Hash:     common.BytesToHash(tx.Hash),
+		From:     common.HexToAddress(tx.From),
+		Nonce:    hexutil.Uint64(tx.Nonce),
+		Gas:      hexutil.Uint64(tx.GasLimit),
+		GasPrice: (*hexutil.Big)(new(big.Int).SetUint64(tx.GasPrice)),
+		Value:    (*hexutil.Big)(new(big.Int)),
+		Input:    tx.Data,
+		V:        (*hexutil.Big)(new(big.Int)),
+		R:        (*hexutil.Big)(new(big.Int)),
+		S:        (*hexutil.Big)(new(big.Int)),
+	}
+	if tx.Value != nil {
+		result.Value = (*hexutil.Big)(tx.Value)
+	}
+	if !tx.IsContractCreation() {
+		to := common.HexToAddress(tx.To)
+		result.To = &to
+	}
+	if block != nil {
+		hash := common.BytesToHash(block.GetHash())
+		i := hexutil.Uint64(index)
+		result.BlockHash = &hash
+		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(block.Header.Height))
+		result.TransactionIndex = &i
+	}
+
+	// İmza ve işlem tipi imzalı kodlamadan okunur
+	ethTx := new(types.Transaction)
+	if tx.IsEthereumTransaction() && ethTx.UnmarshalBinary(tx.RawTransaction) == nil {
+		v, r, s := ethTx.RawSignatureValues()
+		result.Type = hexutil.Uint64(ethTx.Type())
+		result.V, result.R, result.S = (*hexutil.Big)(v), (*hexutil.Big)(r), (*hexutil.Big)(s)
+		result.ChainID = (*hexutil.Big)(ethTx.ChainId())
+		if ethTx.Type() != types.LegacyTxType {
+			accessList := ethTx.AccessList()
+			result.AccessList = &accessList
+		}
+		if ethTx.Type() == types.DynamicFeeTxType {
+			result.MaxFeePerGas = (*hexutil.Big)(ethTx.GasFeeCap())
+			result.MaxPriorityFeePerGas = (*hexutil.Big)(ethTx.GasTipCap())
+		}
+	}
+	return result
+}
+
+// transactionType returns the EIP-2718 type of a transaction, legacy for unsigned ones
+func transactionType(tx *blockchain.Transaction) uint8 {
+	ethTx := new(types.Transaction)
+	if tx.IsEthereumTransaction() && ethTx.UnmarshalBinary(tx.RawTransaction) == nil {
+		return ethTx.Type()
+	}
+	return types.LegacyTxType
+}
+
+// receiptKey returns the key receipts are stored under for a transaction hash
+func receiptKey(hash []byte) string {
+	return hex.EncodeToString(hash)
+}
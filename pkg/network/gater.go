@@ -0,0 +1,64 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// ConnectionGater rejects connections from and to banned peers and,
+// in permissioned mode, from and to peers that are not allowed
+type ConnectionGater struct {
+	scorer      *PeerScorer
+	permissions *PeerPermissions
+}
+
+// NewConnectionGater creates a new connection gater backed by the given peer scorer.
+// permissions may be nil, in which case the network is open to everyone.
+func NewConnectionGater(scorer *PeerScorer, permissions *PeerPermissions) *ConnectionGater {
+	return &ConnectionGater{
+		scorer:      scorer,
+		permissions: permissions,
+	}
+}
+
+// InterceptPeerDial checks whether we may dial the given peer
+func (g *ConnectionGater) InterceptPeerDial(p peer.ID) bool {
+	return g.allowPeer(p)
+}
+
+// InterceptAddrDial checks whether we may dial the given peer at the given address
+func (g *ConnectionGater) InterceptAddrDial(p peer.ID, _ multiaddr.Multiaddr) bool {
+	return g.allowPeer(p)
+}
+
+// InterceptAccept accepts all inbound connections, the peer is not known until the handshake
+func (g *ConnectionGater) InterceptAccept(network.ConnMultiaddrs) bool {
+	return true
+}
+
+// InterceptSecured checks the remote peer once the security handshake is done
+func (g *ConnectionGater) InterceptSecured(_ network.Direction, p peer.ID, _ network.ConnMultiaddrs) bool {
+	return g.allowPeer(p)
+}
+
+// InterceptUpgraded accepts all fully upgraded connections
+func (g *ConnectionGater) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+// allowPeer reports whether a connection with the given peer is allowed
+func (g *ConnectionGater) allowPeer(p peer.ID) bool {
+	if g.scorer.IsBanned(p) {
+		fmt.Printf("Rejecting connection with banned peer %s\n", p)
+		return false
+	}
+	if g.permissions != nil && !g.permissions.IsAllowed(p) {
+		fmt.Printf("Rejecting connection with unauthorized peer %s\n", p)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+package network
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// handshakeTimeout limits how long a handshake may take
+const handshakeTimeout = 10 * time.Second
+
+// localHandshake returns the handshake message describing this node
+func (n *Node) localHandshake() *HandshakeMessage {
+	var genesisHash string
+	if genesis := n.blockchain.GetBlockByHeight(0); genesis != nil {
+		genesisHash = hex.EncodeToString(genesis.GetHash())
+	}
+
+	var height uint64
+	if latest := n.blockchain.GetLatestBlock(); latest != nil {
+		height = latest.Header.Height
+	}
+
+	return &HandshakeMessage{
+		ChainID:         n.chainID,
+		GenesisHash:     genesisHash,
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    []string{CapabilitySync, CapabilityBlockAnnouncement, CapabilityValidatorAnnounce, CapabilitySnapshot},
+		Height:          height,
+	}
+}
+
+// checkHandshake verifies that the remote peer is on the same network
+func (n *Node) checkHandshake(remote *HandshakeMessage) error {
+	local := n.localHandshake()
+
+	if remote.ProtocolVersion < MinProtocolVersion {
+		return fmt.Errorf("unsupported protocol version %d (minimum %d)", remote.ProtocolVersion, MinProtocolVersion)
+	}
+	if remote.ChainID != local.ChainID {
+		return fmt.Errorf("chain ID mismatch: local %d, remote %d", local.ChainID, remote.ChainID)
+	}
+	if remote.GenesisHash != local.GenesisHash {
+		return fmt.Errorf("genesis hash mismatch: local %s, remote %s", local.GenesisHash, remote.GenesisHash)
+	}
+	return nil
+}
+
+// performHandshake runs the handshake with a peer we connected to
+func (n *Node) performHandshake(ctx context.Context, peerID peer.ID) error {
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+
+	stream, err := n.host.NewStream(ctx, peerID, protocol.ID(Handshake))
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	stream.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	if err := writeMessage(stream, Handshake, n.localHandshake()); err != nil {
+		return err
+	}
+
+	var remote HandshakeMessage
+	if err := readMessage(stream, Handshake, &remote); err != nil {
+		n.penalize(peerID, n.scorer.RecordMalformedMessage(peerID))
+		return err
+	}
+
+	if err := n.checkHandshake(&remote); err != nil {
+		n.disconnectPeer(peerID)
+		return fmt.Errorf("incompatible peer %s: %v", peerID, err)
+	}
+
+	n.registerPeer(peerID, &remote)
+	return nil
+}
+
+// handleHandshake answers the handshake of a peer that connected to us
+func (n *Node) handleHandshake(stream network.Stream) {
+	defer stream.Close()
+	stream.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	remotePeer := stream.Conn().RemotePeer()
+
+	var remote HandshakeMessage
+	if err := readMessage(stream, Handshake, &remote); err != nil {
+		fmt.Printf("Failed to read handshake from peer %s: %s\n", remotePeer, err)
+		n.penalize(remotePeer, n.scorer.RecordMalformedMessage(remotePeer))
+		return
+	}
+
+	// Uyumsuz olsa bile karşı tarafın sebebi görebilmesi için kendi bilgimizi gönder
+	if err := writeMessage(stream, Handshake, n.localHandshake()); err != nil {
+		fmt.Printf("Failed to write handshake to peer %s: %s\n", remotePeer, err)
+		return
+	}
+
+	if err := n.checkHandshake(&remote); err != nil {
+		fmt.Printf("Disconnecting incompatible peer %s: %s\n", remotePeer, err)
+
+		// Bağlantıyı kesmeden önce karşı tarafın yanıtı okumasını bekle
+		stream.CloseWrite()
+		io.Copy(io.Discard, stream)
+		n.disconnectPeer(remotePeer)
+		return
+	}
+
+	n.registerPeer(remotePeer, &remote)
+}
+
+// registerPeer records a peer that completed the handshake
+func (n *Node) registerPeer(peerID peer.ID, handshake *HandshakeMessage) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.peers[peerID] = handshake
+	fmt.Printf("Handshake completed with peer %s (protocol v%d, height %d)\n", peerID, handshake.ProtocolVersion, handshake.Height)
+}
+
+// peerSupports reports whether a peer that completed the handshake supports the given protocol
+func (n *Node) peerSupports(peerID peer.ID, protocolID string) bool {
+	n.mu.RLock()
+	handshake, exists := n.peers[peerID]
+	n.mu.RUnlock()
+
+	if !exists {
+		return false
+	}
+
+	capability, required := protocolCapabilities[protocolID]
+	if !required {
+		return true
+	}
+	for _, c := range handshake.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
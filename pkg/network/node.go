@@ -3,8 +3,12 @@ package network
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p/core/host"
@@ -13,56 +17,99 @@ import (
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/multiformats/go-multiaddr"
 
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
 	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
 )
 
-const (
-	ProtocolID          = "/confirmix/1.0.0"
-	BlockchainSync      = "/blockchain/sync"
-	BlockAnnouncement   = "/block/announcement"
-	ValidatorAnnouncement = "/validator/announcement"
-)
-
-// Message represents a network message
-type Message struct {
-	Type    string      `json:"type"`
-	Payload interface{} `json:"payload"`
+// DefaultChainID is the chain ID used when none is configured
+const DefaultChainID uint64 = 1337
+
+// NodeConfig represents the configuration of a P2P node
+type NodeConfig struct {
+	ListenPort int
+	// ChainID identifies the network, peers on other chains are disconnected
+	ChainID uint64
+	// SyncMode selects how a fresh node catches up, SyncModeFull or SyncModeSnapshot
+	SyncMode string
+	// Permissions restricts the network to allowed peers. Nil means an open network.
+	Permissions *PeerPermissions
 }
 
 // Node represents a P2P network node
 type Node struct {
-	host       host.Host
-	blockchain *blockchain.Blockchain
-	peers      map[peer.ID]struct{}
-	mu         sync.RWMutex
+	host        host.Host
+	blockchain  *blockchain.Blockchain
+	chainID     uint64
+	syncMode    string
+	peers       map[peer.ID]*HandshakeMessage
+	scorer      *PeerScorer
+	permissions *PeerPermissions
+	syncing     map[peer.ID]bool // Arka planda senkronizasyonu süren peer'lar
+	mu          sync.RWMutex
+
+	// Authority'lerin imzalı kayıtları (adres -> kayıt)
+	validatorRecords map[string]*ValidatorRecord
+	recordSequence   uint64
 }
 
 // NewNode creates a new P2P node
-func NewNode(listenPort int, bc *blockchain.Blockchain) (*Node, error) {
+func NewNode(config NodeConfig, bc *blockchain.Blockchain) (*Node, error) {
 	// Listen adresi oluştur
-	sourceMultiAddr, err := multiaddr.NewMultiaddr(fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", listenPort))
+	sourceMultiAddr, err := multiaddr.NewMultiaddr(fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", config.ListenPort))
 	if err != nil {
 		return nil, err
 	}
 
+	// Peer puanlama ve bağlantı filtresi
+	scorer := NewPeerScorer(DefaultPeerScoreConfig())
+
 	// Host oluştur
 	host, err := libp2p.New(
 		libp2p.ListenAddrs(sourceMultiAddr),
+		libp2p.ConnectionGater(NewConnectionGater(scorer, config.Permissions)),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	chainID := config.ChainID
+	if chainID == 0 {
+		chainID = DefaultChainID
+	}
+
 	node := &Node{
-		host:       host,
-		blockchain: bc,
-		peers:      make(map[peer.ID]struct{}),
+		host:        host,
+		blockchain:  bc,
+		chainID:     chainID,
+		syncMode:    config.SyncMode,
+		peers:       make(map[peer.ID]*HandshakeMessage),
+		syncing:     make(map[peer.ID]bool),
+		scorer:      scorer,
+		permissions: config.Permissions,
+
+		validatorRecords: make(map[string]*ValidatorRecord),
+		recordSequence:   uint64(time.Now().Unix()),
 	}
 
+	// Bağlantısı kopan peer'ların puanları bir süre sonra silinir
+	node.host.Network().Notify(&network.NotifyBundle{
+		ConnectedF: func(_ network.Network, conn network.Conn) {
+			node.scorer.PeerConnected(conn.RemotePeer())
+		},
+		DisconnectedF: func(net network.Network, conn network.Conn) {
+			if net.Connectedness(conn.RemotePeer()) != network.Connected {
+				node.scorer.PeerDisconnected(conn.RemotePeer())
+			}
+		},
+	})
+
 	// Stream handler'ları ayarla
-	node.host.SetStreamHandler(protocol.ID(BlockchainSync), node.handleBlockchainSync)
-	node.host.SetStreamHandler(protocol.ID(BlockAnnouncement), node.handleBlockAnnouncement)
-	node.host.SetStreamHandler(protocol.ID(ValidatorAnnouncement), node.handleValidatorAnnouncement)
+	node.host.SetStreamHandler(protocol.ID(Handshake), node.limitStream(Handshake, node.handleHandshake))
+	node.host.SetStreamHandler(protocol.ID(BlockchainSync), node.limitStream(BlockchainSync, node.handleBlockchainSync))
+	node.host.SetStreamHandler(protocol.ID(BlockAnnouncement), node.limitStream(BlockAnnouncement, node.handleBlockAnnouncement))
+	node.host.SetStreamHandler(protocol.ID(ValidatorAnnouncement), node.limitStream(ValidatorAnnouncement, node.handleValidatorAnnouncement))
+	node.host.SetStreamHandler(protocol.ID(SnapshotList), node.limitStream(SnapshotList, node.handleSnapshotList))
+	node.host.SetStreamHandler(protocol.ID(SnapshotChunk), node.limitStream(SnapshotChunk, node.handleSnapshotChunk))
 
 	return node, nil
 }
@@ -86,10 +133,17 @@ func (n *Node) Connect(ctx context.Context, peerAddr string) error {
 		return err
 	}
 
-	// Peer'ı listeye ekle
-	n.mu.Lock()
-	n.peers[peerInfo.ID] = struct{}{}
-	n.mu.Unlock()
+	// Handshake ile aynı ağda olduğumuzu doğrula, peer'ı listeye ekle
+	if err := n.performHandshake(ctx, peerInfo.ID); err != nil {
+		return err
+	}
+
+	// Yeni node'lar snapshot modunda önce state'i indirir
+	if n.syncMode == SyncModeSnapshot && n.blockchain.GetBlockCount() <= 1 {
+		if err := n.syncSnapshot(ctx, peerInfo.ID); err != nil {
+			fmt.Printf("Snapshot sync failed, falling back to full sync: %s\n", err)
+		}
+	}
 
 	// Blockchain senkronizasyonunu başlat
 	return n.syncBlockchain(ctx, peerInfo.ID)
@@ -97,9 +151,9 @@ func (n *Node) Connect(ctx context.Context, peerAddr string) error {
 
 // Broadcast broadcasts a message to all peers
 func (n *Node) Broadcast(ctx context.Context, msgType string, payload interface{}) error {
-	msg := Message{
-		Type:    msgType,
-		Payload: payload,
+	msg, err := NewMessage(msgType, payload)
+	if err != nil {
+		return err
 	}
 
 	// Mesajı JSON'a çevir
@@ -108,22 +162,30 @@ func (n *Node) Broadcast(ctx context.Context, msgType string, payload interface{
 		return err
 	}
 
-	// Tüm peer'lara gönder
+	// Mesaj türünü destekleyen peer'ları seç
 	n.mu.RLock()
-	defer n.mu.RUnlock()
-
+	peers := make([]peer.ID, 0, len(n.peers))
 	for peerID := range n.peers {
+		peers = append(peers, peerID)
+	}
+	n.mu.RUnlock()
+
+	for _, peerID := range peers {
+		if !n.peerSupports(peerID, msgType) {
+			continue
+		}
+
 		stream, err := n.host.NewStream(ctx, peerID, protocol.ID(msgType))
 		if err != nil {
 			fmt.Printf("Failed to create stream to peer %s: %s\n", peerID, err)
 			continue
 		}
-		defer stream.Close()
 
 		_, err = stream.Write(data)
 		if err != nil {
 			fmt.Printf("Failed to write to stream: %s\n", err)
 		}
+		stream.Close()
 	}
 
 	return nil
@@ -133,17 +195,34 @@ func (n *Node) Broadcast(ctx context.Context, msgType string, payload interface{
 func (n *Node) handleBlockchainSync(stream network.Stream) {
 	defer stream.Close()
 
-	// Blockchain'i JSON'a çevir
-	data, err := json.Marshal(n.blockchain)
-	if err != nil {
-		fmt.Printf("Failed to marshal blockchain: %s\n", err)
+	remotePeer := stream.Conn().RemotePeer()
+
+	// İsteği oku
+	var req SyncRequest
+	if err := readMessage(stream, BlockchainSync, &req); err != nil {
+		fmt.Printf("Failed to read sync request: %s\n", err)
+		n.penalize(remotePeer, n.scorer.RecordMalformedMessage(remotePeer))
 		return
 	}
 
-	// Blockchain'i gönder
-	_, err = stream.Write(data)
-	if err != nil {
-		fmt.Printf("Failed to write blockchain: %s\n", err)
+	limit := req.Limit
+	if limit <= 0 || limit > maxSyncBlocks {
+		limit = maxSyncBlocks
+	}
+
+	// İstenen aralıktaki blokları topla
+	resp := SyncResponse{Blocks: make([]*blockchain.Block, 0, limit)}
+	for height := req.FromHeight; len(resp.Blocks) < limit; height++ {
+		block := n.blockchain.GetBlockByHeight(height)
+		if block == nil {
+			break
+		}
+		resp.Blocks = append(resp.Blocks, block)
+	}
+
+	// Blokları gönder
+	if err := writeMessage(stream, BlockchainSync, &resp); err != nil {
+		fmt.Printf("Failed to write sync response: %s\n", err)
 	}
 }
 
@@ -151,72 +230,380 @@ func (n *Node) handleBlockchainSync(stream network.Stream) {
 func (n *Node) handleBlockAnnouncement(stream network.Stream) {
 	defer stream.Close()
 
-	// Mesajı oku
-	var msg Message
-	decoder := json.NewDecoder(stream)
-	if err := decoder.Decode(&msg); err != nil {
-		fmt.Printf("Failed to decode message: %s\n", err)
-		return
-	}
+	remotePeer := stream.Conn().RemotePeer()
 
-	// Blok verisini parse et
-	blockData, err := json.Marshal(msg.Payload)
-	if err != nil {
-		fmt.Printf("Failed to marshal block data: %s\n", err)
+	// Mesajı oku
+	var msg BlockAnnouncementMessage
+	if err := readMessage(stream, BlockAnnouncement, &msg); err != nil || msg.Block == nil || msg.Block.Header == nil {
+		fmt.Printf("Failed to read block announcement: %v\n", err)
+		n.penalize(remotePeer, n.scorer.RecordMalformedMessage(remotePeer))
 		return
 	}
-
-	var block blockchain.Block
-	if err := json.Unmarshal(blockData, &block); err != nil {
-		fmt.Printf("Failed to unmarshal block: %s\n", err)
+	block := msg.Block
+
+	// Bloğu doğrula ve blockchain'e ekle
+	if err := n.blockchain.AddBlock(block); err != nil {
+		switch {
+		case errors.Is(err, blockchain.ErrStaleBlock):
+			// Zaten eklenmiş veya eski bloklar geçersiz sayılmaz
+		case errors.Is(err, blockchain.ErrUnknownProducer):
+			// Üreticisi aradaki bloklarda eklenmiş olabilir; cezalandırılmaz ama senkronizasyon da başlatmaz
+		case errors.Is(err, blockchain.ErrFutureBlock):
+			// İmzası doğrulanan bloğun öncesindeki bloklar peer'dan senkronize edilir
+			n.syncInBackground(remotePeer)
+		default:
+			fmt.Printf("Rejected block %d from peer %s: %v\n", block.Header.Height, remotePeer, err)
+			n.penalize(remotePeer, n.scorer.RecordInvalidBlock(remotePeer))
+		}
 		return
 	}
 
-	// TODO: Bloğu doğrula ve blockchain'e ekle
+	n.scorer.RecordValidBlock(remotePeer)
 }
 
 // handleValidatorAnnouncement handles new validator announcements
 func (n *Node) handleValidatorAnnouncement(stream network.Stream) {
 	defer stream.Close()
 
+	remotePeer := stream.Conn().RemotePeer()
+
 	// Mesajı oku
-	var msg Message
-	decoder := json.NewDecoder(stream)
-	if err := decoder.Decode(&msg); err != nil {
-		fmt.Printf("Failed to decode message: %s\n", err)
+	var msg ValidatorAnnouncementMessage
+	if err := readMessage(stream, ValidatorAnnouncement, &msg); err != nil || msg.Record == nil {
+		fmt.Printf("Failed to read validator announcement: %v\n", err)
+		n.penalize(remotePeer, n.scorer.RecordMalformedMessage(remotePeer))
+		return
+	}
+	record := msg.Record
+
+	// Kaydı zincirdeki validator setine göre doğrula
+	if err := record.Verify(n.blockchain.GetValidator(record.Address)); err != nil {
+		fmt.Printf("Rejected validator record for %s from peer %s: %s\n", record.Address, remotePeer, err)
+		n.penalize(remotePeer, n.scorer.RecordMalformedMessage(remotePeer))
+		return
+	}
+
+	// Eski veya zaten bilinen kayıtları yok say
+	if !n.storeValidatorRecord(record) {
+		return
+	}
+	fmt.Printf("Validator record for %s (sequence %d) received from peer %s\n", record.Address, record.Sequence, remotePeer)
+
+	// Authority'ye doğrudan bağlan ve kaydı diğer peer'lara yay
+	go n.connectToAuthority(context.Background(), record)
+	go n.Broadcast(context.Background(), ValidatorAnnouncement, &msg)
+}
+
+// storeValidatorRecord stores the record if it is newer than the known one
+func (n *Node) storeValidatorRecord(record *ValidatorRecord) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if existing, exists := n.validatorRecords[record.Address]; exists && existing.Sequence >= record.Sequence {
+		return false
+	}
+	n.validatorRecords[record.Address] = record
+	return true
+}
+
+// connectToAuthority opens and protects a direct connection to an authority's node
+func (n *Node) connectToAuthority(ctx context.Context, record *ValidatorRecord) {
+	info, err := record.AddrInfo()
+	if err != nil || info.ID == n.host.ID() {
+		return
+	}
+
+	// Authority bağlantıları bağlantı yöneticisi tarafından kapatılmaz
+	n.host.ConnManager().Protect(info.ID, "authority")
+
+	n.mu.RLock()
+	_, known := n.peers[info.ID]
+	n.mu.RUnlock()
+	if known {
 		return
 	}
 
-	// Validator verisini parse et
-	validatorData, err := json.Marshal(msg.Payload)
+	if err := n.host.Connect(ctx, *info); err != nil {
+		fmt.Printf("Failed to connect to authority %s at %s: %s\n", record.Address, record.Endpoint, err)
+		return
+	}
+	if err := n.performHandshake(ctx, info.ID); err != nil {
+		fmt.Printf("Handshake with authority %s failed: %s\n", record.Address, err)
+	}
+}
+
+// AnnounceValidator publishes a signed record of this node for the given authority
+func (n *Node) AnnounceValidator(ctx context.Context, authority *validator.Authority) error {
+	n.mu.Lock()
+	n.recordSequence++
+	sequence := n.recordSequence
+	n.mu.Unlock()
+
+	record, err := NewValidatorRecord(authority, n.host.ID(), n.GetMultiaddr(), sequence)
 	if err != nil {
-		fmt.Printf("Failed to marshal validator data: %s\n", err)
+		return err
+	}
+
+	n.storeValidatorRecord(record)
+	return n.Broadcast(ctx, ValidatorAnnouncement, &ValidatorAnnouncementMessage{Record: record})
+}
+
+// StartValidatorAnnouncements periodically announces the authority until the context is done
+func (n *Node) StartValidatorAnnouncements(ctx context.Context, authority *validator.Authority, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := n.AnnounceValidator(ctx, authority); err != nil {
+				fmt.Printf("Failed to announce validator %s: %s\n", authority.Address, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// GetValidatorRecords returns the known records of authorities in the current validator set
+func (n *Node) GetValidatorRecords() []*ValidatorRecord {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	records := make([]*ValidatorRecord, 0, len(n.validatorRecords))
+	for address, record := range n.validatorRecords {
+		if n.blockchain.GetValidator(address) != nil {
+			records = append(records, record)
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Address < records[j].Address
+	})
+	return records
+}
+
+// authorityOf returns the address of the authority that announced the given peer
+func (n *Node) authorityOf(peerID peer.ID) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	for address, record := range n.validatorRecords {
+		if record.PeerID == peerID.String() {
+			return address
+		}
+	}
+	return ""
+}
+
+// syncInBackground syncs the blockchain with a peer in the background, unless a
+// background sync with the peer is already running
+func (n *Node) syncInBackground(peerID peer.ID) {
+	n.mu.Lock()
+	if n.syncing[peerID] {
+		n.mu.Unlock()
 		return
 	}
+	n.syncing[peerID] = true
+	n.mu.Unlock()
+
+	go func() {
+		defer func() {
+			n.mu.Lock()
+			delete(n.syncing, peerID)
+			n.mu.Unlock()
+		}()
 
-	// TODO: Validator'ı doğrula ve ekle
+		if err := n.syncBlockchain(context.Background(), peerID); err != nil {
+			fmt.Printf("Sync with peer %s failed: %s\n", peerID, err)
+		}
+	}()
 }
 
 // syncBlockchain syncs the blockchain with a peer
 func (n *Node) syncBlockchain(ctx context.Context, peerID peer.ID) error {
-	// Blockchain sync stream'i oluştur
-	stream, err := n.host.NewStream(ctx, peerID, protocol.ID(BlockchainSync))
-	if err != nil {
-		return err
+	return n.syncBlocks(ctx, peerID, math.MaxUint64)
+}
+
+// syncBlocks adds the blocks of a peer up to the given height
+func (n *Node) syncBlocks(ctx context.Context, peerID peer.ID, last uint64) error {
+	if !n.peerSupports(peerID, BlockchainSync) {
+		return fmt.Errorf("peer %s does not support sync", peerID)
 	}
-	defer stream.Close()
 
-	// Blockchain verisini oku
-	var remoteChain blockchain.Blockchain
-	decoder := json.NewDecoder(stream)
-	if err := decoder.Decode(&remoteChain); err != nil {
+	for {
+		from := n.blockchain.GetBlockCount()
+		if from > last {
+			return nil
+		}
+		blocks, err := n.requestBlocks(ctx, peerID, from)
+		if err != nil {
+			return err
+		}
+		if len(blocks) == 0 {
+			return nil
+		}
+		if remaining := last - from + 1; uint64(len(blocks)) > remaining {
+			blocks = blocks[:remaining]
+		}
+
+		// Alınan blokları sırayla doğrula ve ekle
+		for _, block := range blocks {
+			if block == nil || block.Header == nil {
+				n.penalize(peerID, n.scorer.RecordMalformedMessage(peerID))
+				return fmt.Errorf("peer %s sent a malformed block", peerID)
+			}
+			if err := n.blockchain.AddBlock(block); err != nil {
+				// Aynı anda duyurulan bloklar zaten eklenmiş olabilir
+				if errors.Is(err, blockchain.ErrStaleBlock) {
+					continue
+				}
+				if !errors.Is(err, blockchain.ErrFutureBlock) {
+					n.penalize(peerID, n.scorer.RecordInvalidBlock(peerID))
+				}
+				return fmt.Errorf("invalid block %d from peer %s: %v", block.Header.Height, peerID, err)
+			}
+		}
+	}
+}
+
+// requestBlocks requests a batch of blocks starting at the given height from a peer
+func (n *Node) requestBlocks(ctx context.Context, peerID peer.ID, from uint64) ([]*blockchain.Block, error) {
+	var resp SyncResponse
+	if err := n.request(ctx, peerID, BlockchainSync, &SyncRequest{FromHeight: from, Limit: maxSyncBlocks}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Blocks, nil
+}
+
+// limitStream wraps a stream handler with ban and rate limit checks for the remote peer
+func (n *Node) limitStream(protocolID string, handler network.StreamHandler) network.StreamHandler {
+	return func(stream network.Stream) {
+		remotePeer := stream.Conn().RemotePeer()
+
+		if n.scorer.IsBanned(remotePeer) {
+			fmt.Printf("Dropping %s stream from banned peer %s\n", protocolID, remotePeer)
+			stream.Reset()
+			n.disconnectPeer(remotePeer)
+			return
+		}
+
+		if !n.scorer.AllowRequest(remotePeer, protocolID) {
+			fmt.Printf("Rate limit exceeded on %s by peer %s\n", protocolID, remotePeer)
+			stream.Reset()
+			n.penalize(remotePeer, n.scorer.IsBanned(remotePeer))
+			return
+		}
+
+		// Handshake tamamlanmadan diğer protokoller kullanılamaz
+		if protocolID != Handshake && !n.peerSupports(remotePeer, protocolID) {
+			fmt.Printf("Rejecting %s stream from peer %s without handshake\n", protocolID, remotePeer)
+			stream.Reset()
+			n.penalize(remotePeer, n.scorer.RecordMalformedMessage(remotePeer))
+			return
+		}
+
+		handler(stream)
+	}
+}
+
+// penalize disconnects the peer if the last penalty got it banned
+func (n *Node) penalize(peerID peer.ID, banned bool) {
+	if !banned {
+		return
+	}
+
+	fmt.Printf("Peer %s banned\n", peerID)
+	n.disconnectPeer(peerID)
+}
+
+// disconnectPeer closes all connections to a peer and forgets it
+func (n *Node) disconnectPeer(peerID peer.ID) {
+	n.mu.Lock()
+	delete(n.peers, peerID)
+	n.mu.Unlock()
+
+	if err := n.host.Network().ClosePeer(peerID); err != nil {
+		fmt.Printf("Failed to disconnect peer %s: %s\n", peerID, err)
+	}
+}
+
+// BanPeer bans a peer for the given duration and disconnects it
+func (n *Node) BanPeer(peerID peer.ID, duration time.Duration) {
+	n.scorer.Ban(peerID, duration)
+	n.disconnectPeer(peerID)
+}
+
+// UnbanPeer lifts the ban of a peer
+func (n *Node) UnbanPeer(peerID peer.ID) {
+	n.scorer.Unban(peerID)
+}
+
+// IsPermissioned reports whether the node only accepts allowed peers
+func (n *Node) IsPermissioned() bool {
+	return n.permissions != nil
+}
+
+// ReloadPermissions reloads the allowlist and disconnects peers that are no longer allowed
+func (n *Node) ReloadPermissions() error {
+	if n.permissions == nil {
+		return errors.New("node is not running in permissioned mode")
+	}
+
+	if err := n.permissions.Reload(); err != nil {
 		return err
 	}
 
-	// TODO: Blockchain'leri karşılaştır ve senkronize et
+	for _, peerID := range n.host.Network().Peers() {
+		if !n.permissions.IsAllowed(peerID) {
+			fmt.Printf("Peer %s is no longer allowed, disconnecting\n", peerID)
+			n.disconnectPeer(peerID)
+		}
+	}
 	return nil
 }
 
+// PeerInfo represents a known peer with its connection state and score
+type PeerInfo struct {
+	PeerScore
+	Connected bool   `json:"connected"`
+	Authority string `json:"authority,omitempty"`
+}
+
+// GetPeers returns all known peers with their scores
+func (n *Node) GetPeers() []PeerInfo {
+	scores := n.scorer.GetScores()
+
+	// Henüz puanı olmayan bağlı peer'ları da listele
+	known := make(map[peer.ID]struct{}, len(scores))
+	for _, score := range scores {
+		known[score.ID] = struct{}{}
+	}
+	for _, peerID := range n.host.Network().Peers() {
+		if _, exists := known[peerID]; !exists {
+			scores = append(scores, PeerScore{ID: peerID})
+		}
+	}
+
+	peers := make([]PeerInfo, 0, len(scores))
+	for _, score := range scores {
+		peers = append(peers, PeerInfo{
+			PeerScore: score,
+			Connected: n.host.Network().Connectedness(score.ID) == network.Connected,
+			Authority: n.authorityOf(score.ID),
+		})
+	}
+	return peers
+}
+
+// ID returns the node's peer ID
+func (n *Node) ID() peer.ID {
+	return n.host.ID()
+}
+
 // GetMultiaddr returns the node's multiaddress
 func (n *Node) GetMultiaddr() string {
 	return fmt.Sprintf("%s/p2p/%s", n.host.Addrs()[0], n.host.ID())
@@ -0,0 +1,295 @@
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// PeerScoreConfig represents the configuration for peer scoring and banning
+type PeerScoreConfig struct {
+	InvalidBlockPenalty     int
+	MalformedMessagePenalty int
+	RateLimitPenalty        int
+	ValidBlockReward        int
+	MaxScore                int
+	BanThreshold            int
+	BanDuration             time.Duration
+	RequestsPerInterval     int
+	RateInterval            time.Duration
+	DisconnectedPeerTTL     time.Duration
+}
+
+// DefaultPeerScoreConfig returns the default peer scoring configuration
+func DefaultPeerScoreConfig() PeerScoreConfig {
+	return PeerScoreConfig{
+		InvalidBlockPenalty:     20,
+		MalformedMessagePenalty: 10,
+		RateLimitPenalty:        5,
+		ValidBlockReward:        1,
+		MaxScore:                100,
+		BanThreshold:            -100,
+		BanDuration:             30 * time.Minute,
+		RequestsPerInterval:     20,
+		RateInterval:            time.Minute,
+		DisconnectedPeerTTL:     time.Hour,
+	}
+}
+
+// PeerScore represents the current reputation of a peer
+type PeerScore struct {
+	ID                peer.ID   `json:"id"`
+	Score             int       `json:"score"`
+	InvalidBlocks     uint64    `json:"invalid_blocks"`
+	MalformedMessages uint64    `json:"malformed_messages"`
+	RateLimited       uint64    `json:"rate_limited"`
+	Banned            bool      `json:"banned"`
+	BannedUntil       time.Time `json:"banned_until"`
+	LastSeen          time.Time `json:"last_seen"`
+}
+
+// rateWindow counts the requests of a peer for a protocol in the current window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// peerState holds the scoring state of a single peer
+type peerState struct {
+	score          PeerScore
+	requests       map[string]*rateWindow
+	disconnectedAt time.Time // Bağlıysa sıfır
+}
+
+// PeerScorer tracks peer scores, enforces per-peer rate limits and bans misbehaving peers
+type PeerScorer struct {
+	config PeerScoreConfig
+	peers  map[peer.ID]*peerState
+	mu     sync.RWMutex
+}
+
+// NewPeerScorer creates a new peer scorer
+func NewPeerScorer(config PeerScoreConfig) *PeerScorer {
+	return &PeerScorer{
+		config: config,
+		peers:  make(map[peer.ID]*peerState),
+	}
+}
+
+// getState returns the state of a peer, creating it if necessary. Caller must hold the lock.
+func (s *PeerScorer) getState(id peer.ID) *peerState {
+	state, exists := s.peers[id]
+	if !exists {
+		state = &peerState{
+			score:    PeerScore{ID: id},
+			requests: make(map[string]*rateWindow),
+		}
+		s.peers[id] = state
+		s.pruneLocked()
+	}
+
+	// Ban süresi dolan peer sıfır puanla yeniden başlar
+	now := time.Now()
+	if !state.score.BannedUntil.IsZero() && !now.Before(state.score.BannedUntil) {
+		state.score.BannedUntil = time.Time{}
+		state.score.Score = 0
+	}
+
+	state.score.LastSeen = now
+	return state
+}
+
+// AllowRequest reports whether the peer may make another request on the given protocol.
+// Requests above the limit are rejected and penalized.
+func (s *PeerScorer) AllowRequest(id peer.ID, protocolID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.getState(id)
+	if s.isBannedLocked(state) {
+		return false
+	}
+
+	now := time.Now()
+	window, exists := state.requests[protocolID]
+	if !exists || now.Sub(window.start) >= s.config.RateInterval {
+		window = &rateWindow{start: now}
+		state.requests[protocolID] = window
+	}
+
+	window.count++
+	if window.count > s.config.RequestsPerInterval {
+		state.score.RateLimited++
+		s.penalizeLocked(state, s.config.RateLimitPenalty)
+		return false
+	}
+
+	return true
+}
+
+// RecordInvalidBlock penalizes a peer for sending an invalid block.
+// It returns true if the peer got banned as a result.
+func (s *PeerScorer) RecordInvalidBlock(id peer.ID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.getState(id)
+	state.score.InvalidBlocks++
+	return s.penalizeLocked(state, s.config.InvalidBlockPenalty)
+}
+
+// RecordMalformedMessage penalizes a peer for sending a message that cannot be decoded.
+// It returns true if the peer got banned as a result.
+func (s *PeerScorer) RecordMalformedMessage(id peer.ID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.getState(id)
+	state.score.MalformedMessages++
+	return s.penalizeLocked(state, s.config.MalformedMessagePenalty)
+}
+
+// RecordValidBlock rewards a peer for sending a valid block
+func (s *PeerScorer) RecordValidBlock(id peer.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.getState(id)
+	state.score.Score += s.config.ValidBlockReward
+	if state.score.Score > s.config.MaxScore {
+		state.score.Score = s.config.MaxScore
+	}
+}
+
+// PeerConnected records that a peer has an open connection again
+func (s *PeerScorer) PeerConnected(id peer.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, exists := s.peers[id]; exists {
+		state.disconnectedAt = time.Time{}
+	}
+}
+
+// PeerDisconnected records that the last connection to a peer was closed. The peer's score
+// is dropped once it has been disconnected for longer than the TTL and is not banned.
+func (s *PeerScorer) PeerDisconnected(id peer.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, exists := s.peers[id]; exists {
+		state.disconnectedAt = time.Now()
+	}
+	s.pruneLocked()
+}
+
+// pruneLocked drops the peers disconnected for longer than the TTL. Banned peers are kept
+// until their ban expires. Caller must hold the lock.
+func (s *PeerScorer) pruneLocked() {
+	if s.config.DisconnectedPeerTTL <= 0 {
+		return
+	}
+
+	now := time.Now()
+	for id, state := range s.peers {
+		if state.disconnectedAt.IsZero() || now.Sub(state.disconnectedAt) < s.config.DisconnectedPeerTTL {
+			continue
+		}
+		if s.isBannedLocked(state) {
+			continue
+		}
+		delete(s.peers, id)
+	}
+}
+
+// Ban bans a peer for the given duration
+func (s *PeerScorer) Ban(id peer.ID, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.getState(id)
+	state.score.BannedUntil = time.Now().Add(duration)
+}
+
+// Unban lifts the ban of a peer and resets its score
+func (s *PeerScorer) Unban(id peer.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, exists := s.peers[id]; exists {
+		state.score.BannedUntil = time.Time{}
+		state.score.Score = 0
+	}
+}
+
+// IsBanned reports whether the peer is currently banned
+func (s *PeerScorer) IsBanned(id peer.ID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state, exists := s.peers[id]
+	if !exists {
+		return false
+	}
+	return s.isBannedLocked(state)
+}
+
+// GetScore returns the score of a peer
+func (s *PeerScorer) GetScore(id peer.ID) (PeerScore, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state, exists := s.peers[id]
+	if !exists {
+		return PeerScore{}, false
+	}
+	return s.snapshotLocked(state), true
+}
+
+// GetScores returns the scores of all known peers, highest score first
+func (s *PeerScorer) GetScores() []PeerScore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scores := make([]PeerScore, 0, len(s.peers))
+	for _, state := range s.peers {
+		scores = append(scores, s.snapshotLocked(state))
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].ID < scores[j].ID
+	})
+	return scores
+}
+
+// snapshotLocked returns a copy of the peer's score. Caller must hold the lock.
+func (s *PeerScorer) snapshotLocked(state *peerState) PeerScore {
+	score := state.score
+	score.Banned = s.isBannedLocked(state)
+	if !score.Banned {
+		score.BannedUntil = time.Time{}
+	}
+	return score
+}
+
+// isBannedLocked reports whether the peer is banned. Caller must hold the lock.
+func (s *PeerScorer) isBannedLocked(state *peerState) bool {
+	return time.Now().Before(state.score.BannedUntil)
+}
+
+// penalizeLocked lowers the peer's score and bans it once the threshold is reached.
+// Caller must hold the lock.
+func (s *PeerScorer) penalizeLocked(state *peerState, penalty int) bool {
+	state.score.Score -= penalty
+	if state.score.Score > s.config.BanThreshold || s.isBannedLocked(state) {
+		return false
+	}
+
+	state.score.BannedUntil = time.Now().Add(s.config.BanDuration)
+	return true
+}
@@ -0,0 +1,120 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+func testScoreConfig() PeerScoreConfig {
+	config := DefaultPeerScoreConfig()
+	config.BanThreshold = -30
+	config.RequestsPerInterval = 2
+	config.RateInterval = time.Minute
+	return config
+}
+
+// TestPeerScorerBansAfterInvalidBlocks geçersiz bloklardan sonra banlanmayı test eder
+func TestPeerScorerBansAfterInvalidBlocks(t *testing.T) {
+	scorer := NewPeerScorer(testScoreConfig())
+	id := peer.ID("peer-1")
+
+	if scorer.RecordInvalidBlock(id) {
+		t.Fatal("Peer ilk geçersiz blokta banlanmamalı")
+	}
+	if !scorer.RecordInvalidBlock(id) {
+		t.Fatal("Peer eşik değer aşıldığında banlanmalı")
+	}
+	if !scorer.IsBanned(id) {
+		t.Error("Peer banlı görünmeli")
+	}
+
+	score, ok := scorer.GetScore(id)
+	if !ok {
+		t.Fatal("Peer puanı bulunamadı")
+	}
+	if score.InvalidBlocks != 2 || !score.Banned {
+		t.Errorf("Beklenmeyen puan durumu: %+v", score)
+	}
+
+	scorer.Unban(id)
+	if scorer.IsBanned(id) {
+		t.Error("Ban kaldırıldıktan sonra peer banlı olmamalı")
+	}
+}
+
+// TestPeerScorerRateLimit istek sınırlamasını test eder
+func TestPeerScorerRateLimit(t *testing.T) {
+	scorer := NewPeerScorer(testScoreConfig())
+	id := peer.ID("peer-2")
+
+	for i := 0; i < 2; i++ {
+		if !scorer.AllowRequest(id, BlockchainSync) {
+			t.Fatalf("İstek %d reddedilmemeli", i)
+		}
+	}
+	if scorer.AllowRequest(id, BlockchainSync) {
+		t.Error("Sınırı aşan istek reddedilmeli")
+	}
+
+	// Farklı protokolün kendi sınırı vardır
+	if !scorer.AllowRequest(id, BlockAnnouncement) {
+		t.Error("Farklı protokol için istek reddedilmemeli")
+	}
+
+	score, _ := scorer.GetScore(id)
+	if score.RateLimited != 1 {
+		t.Errorf("Beklenen rate limit sayısı 1, alınan: %d", score.RateLimited)
+	}
+}
+
+// TestPeerScorerPrunesDisconnectedPeers bağlantısı kopan peer'ların süre dolunca silindiğini test eder
+func TestPeerScorerPrunesDisconnectedPeers(t *testing.T) {
+	config := testScoreConfig()
+	config.DisconnectedPeerTTL = 50 * time.Millisecond
+	scorer := NewPeerScorer(config)
+	gone, banned, connected := peer.ID("peer-gone"), peer.ID("peer-banned"), peer.ID("peer-connected")
+
+	scorer.RecordValidBlock(gone)
+	scorer.RecordValidBlock(connected)
+	scorer.Ban(banned, time.Hour)
+	scorer.PeerDisconnected(gone)
+	scorer.PeerDisconnected(banned)
+	scorer.PeerDisconnected(connected)
+	scorer.PeerConnected(connected)
+
+	// Süre dolmadan puanlar korunur
+	scorer.PeerDisconnected(peer.ID("other"))
+	if _, ok := scorer.GetScore(gone); !ok {
+		t.Fatal("Yeni kopan peer'ın puanı silindi")
+	}
+
+	time.Sleep(2 * config.DisconnectedPeerTTL)
+	scorer.RecordValidBlock(peer.ID("new-peer"))
+	if _, ok := scorer.GetScore(gone); ok {
+		t.Error("Süresi dolan peer silinmedi")
+	}
+	if !scorer.IsBanned(banned) {
+		t.Error("Banlı peer ban süresi dolmadan silindi")
+	}
+	if _, ok := scorer.GetScore(connected); !ok {
+		t.Error("Yeniden bağlanan peer silindi")
+	}
+}
+
+// TestPeerScorerGater banlı peer'ların bağlantı filtresini test eder
+func TestPeerScorerGater(t *testing.T) {
+	scorer := NewPeerScorer(testScoreConfig())
+	gater := NewConnectionGater(scorer, nil)
+	id := peer.ID("peer-3")
+
+	if !gater.InterceptPeerDial(id) {
+		t.Error("Banlı olmayan peer'a bağlanılabilmeli")
+	}
+
+	scorer.Ban(id, time.Minute)
+	if gater.InterceptPeerDial(id) {
+		t.Error("Banlı peer'a bağlanılmamalı")
+	}
+}
@@ -0,0 +1,163 @@
+package network
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+)
+
+// AuthorityPeer binds a libp2p peer to an authority of the validator set.
+// If Signature is set it must be the authority's signature of the peer ID.
+type AuthorityPeer struct {
+	PeerID    string `json:"peer_id"`
+	Authority string `json:"authority"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// Allowlist represents the on-disk allowlist of a permissioned network
+type Allowlist struct {
+	Peers       []string        `json:"peers"`
+	Authorities []AuthorityPeer `json:"authorities"`
+}
+
+// PeerPermissions decides which peers may connect in permissioned mode
+type PeerPermissions struct {
+	path           string
+	blockchain     *blockchain.Blockchain
+	peers          map[peer.ID]struct{}
+	authorityPeers map[peer.ID]AuthorityPeer
+	mu             sync.RWMutex
+}
+
+// NewPeerPermissions creates the permission set and loads the allowlist file if given
+func NewPeerPermissions(path string, bc *blockchain.Blockchain) (*PeerPermissions, error) {
+	if bc == nil {
+		return nil, errors.New("blockchain is required")
+	}
+
+	p := &PeerPermissions{
+		path:           path,
+		blockchain:     bc,
+		peers:          make(map[peer.ID]struct{}),
+		authorityPeers: make(map[peer.ID]AuthorityPeer),
+	}
+
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload re-reads the allowlist file. The current set is kept if the file is invalid.
+func (p *PeerPermissions) Reload() error {
+	if p.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read allowlist: %v", err)
+	}
+
+	var list Allowlist
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("failed to parse allowlist: %v", err)
+	}
+
+	peers := make(map[peer.ID]struct{}, len(list.Peers))
+	for _, entry := range list.Peers {
+		id, err := peer.Decode(entry)
+		if err != nil {
+			return fmt.Errorf("invalid peer ID %q in allowlist: %v", entry, err)
+		}
+		peers[id] = struct{}{}
+	}
+
+	authorityPeers := make(map[peer.ID]AuthorityPeer, len(list.Authorities))
+	for _, entry := range list.Authorities {
+		id, err := peer.Decode(entry.PeerID)
+		if err != nil {
+			return fmt.Errorf("invalid peer ID %q in allowlist: %v", entry.PeerID, err)
+		}
+		if entry.Authority == "" {
+			return fmt.Errorf("authority missing for peer %s in allowlist", entry.PeerID)
+		}
+		authorityPeers[id] = entry
+	}
+
+	p.mu.Lock()
+	p.peers = peers
+	p.authorityPeers = authorityPeers
+	p.mu.Unlock()
+
+	fmt.Printf("Allowlist loaded: %d peers, %d authority peers\n", len(peers), len(authorityPeers))
+	return nil
+}
+
+// AddPeer allows a peer at runtime without touching the allowlist file
+func (p *PeerPermissions) AddPeer(id peer.ID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.peers[id] = struct{}{}
+}
+
+// IsAllowed reports whether the peer may connect. Authority peers are only
+// allowed while their authority is part of the validator set.
+func (p *PeerPermissions) IsAllowed(id peer.ID) bool {
+	p.mu.RLock()
+	_, listed := p.peers[id]
+	entry, isAuthorityPeer := p.authorityPeers[id]
+	p.mu.RUnlock()
+
+	if listed {
+		return true
+	}
+	if !isAuthorityPeer {
+		return false
+	}
+
+	authority := p.blockchain.GetValidator(entry.Authority)
+	if authority == nil {
+		return false
+	}
+
+	// Sadece listelenmiş peer'lar için imza zorunlu değildir
+	if entry.Signature == "" {
+		return true
+	}
+
+	signature, err := hex.DecodeString(entry.Signature)
+	if err != nil {
+		return false
+	}
+	return authority.Verify(peerIDHash(id), signature)
+}
+
+// SignPeerID signs a peer ID with the authority's key so it can be listed in the allowlist
+func SignPeerID(authority *validator.Authority, id peer.ID) (string, error) {
+	if authority == nil {
+		return "", errors.New("authority is nil")
+	}
+
+	signature, err := authority.Sign(peerIDHash(id))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(signature), nil
+}
+
+// peerIDHash returns the hash of the peer ID that authorities sign
+func peerIDHash(id peer.ID) []byte {
+	hash := sha256.Sum256([]byte(id))
+	return hash[:]
+}
@@ -0,0 +1,97 @@
+package network
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+)
+
+// createTestPeerID test için rastgele peer ID oluşturur
+func createTestPeerID(t *testing.T) peer.ID {
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatalf("Peer anahtarı oluşturulamadı: %v", err)
+	}
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Peer ID oluşturulamadı: %v", err)
+	}
+	return id
+}
+
+// writeAllowlist allowlist dosyasını yazar
+func writeAllowlist(t *testing.T, path string, list Allowlist) {
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatalf("Allowlist oluşturulamadı: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Allowlist yazılamadı: %v", err)
+	}
+}
+
+// TestPeerPermissions allowlist ve authority imzalı peer izinlerini test eder
+func TestPeerPermissions(t *testing.T) {
+	authority, err := validator.NewAuthority(nil)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	bc, err := blockchain.NewBlockchain(authority)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	listedPeer := createTestPeerID(t)
+	signedPeer := createTestPeerID(t)
+	forgedPeer := createTestPeerID(t)
+	unknownPeer := createTestPeerID(t)
+
+	signature, err := SignPeerID(authority, signedPeer)
+	if err != nil {
+		t.Fatalf("Peer ID imzalanamadı: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "allowlist.json")
+	writeAllowlist(t, path, Allowlist{
+		Peers: []string{listedPeer.String()},
+		Authorities: []AuthorityPeer{
+			{PeerID: signedPeer.String(), Authority: authority.Address, Signature: signature},
+			{PeerID: forgedPeer.String(), Authority: authority.Address, Signature: signature},
+		},
+	})
+
+	permissions, err := NewPeerPermissions(path, bc)
+	if err != nil {
+		t.Fatalf("Peer izinleri yüklenemedi: %v", err)
+	}
+
+	if !permissions.IsAllowed(listedPeer) {
+		t.Error("Allowlist'teki peer kabul edilmeli")
+	}
+	if !permissions.IsAllowed(signedPeer) {
+		t.Error("Authority tarafından imzalanmış peer kabul edilmeli")
+	}
+	if permissions.IsAllowed(forgedPeer) {
+		t.Error("Başka peer için verilmiş imza kabul edilmemeli")
+	}
+	if permissions.IsAllowed(unknownPeer) {
+		t.Error("Bilinmeyen peer kabul edilmemeli")
+	}
+
+	// Allowlist yeniden yüklendiğinde değişiklikler uygulanmalı
+	writeAllowlist(t, path, Allowlist{Peers: []string{unknownPeer.String()}})
+	if err := permissions.Reload(); err != nil {
+		t.Fatalf("Allowlist yeniden yüklenemedi: %v", err)
+	}
+	if permissions.IsAllowed(listedPeer) || !permissions.IsAllowed(unknownPeer) {
+		t.Error("Yeniden yüklenen allowlist uygulanmadı")
+	}
+}
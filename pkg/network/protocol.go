@@ -0,0 +1,157 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+)
+
+// ProtocolVersion is the wire protocol version spoken by this node
+const ProtocolVersion uint32 = 1
+
+// MinProtocolVersion is the oldest wire protocol version this node accepts
+const MinProtocolVersion uint32 = 1
+
+const (
+	ProtocolID            = "/confirmix/1.0.0"
+	Handshake             = ProtocolID + "/handshake"
+	BlockchainSync        = ProtocolID + "/blockchain/sync"
+	BlockAnnouncement     = ProtocolID + "/block/announcement"
+	ValidatorAnnouncement = ProtocolID + "/validator/announcement"
+	SnapshotList          = ProtocolID + "/snapshot/list"
+	SnapshotChunk         = ProtocolID + "/snapshot/chunk"
+)
+
+// Capabilities advertised during the handshake
+const (
+	CapabilitySync              = "sync"
+	CapabilityBlockAnnouncement = "block-announcement"
+	CapabilityValidatorAnnounce = "validator-announcement"
+	CapabilitySnapshot          = "snapshot"
+)
+
+// protocolCapabilities maps each protocol to the capability the remote peer must support
+var protocolCapabilities = map[string]string{
+	BlockchainSync:        CapabilitySync,
+	BlockAnnouncement:     CapabilityBlockAnnouncement,
+	ValidatorAnnouncement: CapabilityValidatorAnnounce,
+	SnapshotList:          CapabilitySnapshot,
+	SnapshotChunk:         CapabilitySnapshot,
+}
+
+// maxMessageSize limits the size of a single decoded message
+const maxMessageSize = 16 << 20
+
+// maxSyncBlocks limits the number of blocks returned for a single sync request
+const maxSyncBlocks = 256
+
+// maxSnapshotChunks limits the number of chunks returned for a single snapshot chunk request
+const maxSnapshotChunks = 32
+
+// maxSnapshotChunkBytes limits the chunk data of a single snapshot chunk response, which
+// grows by a third when encoded, below maxMessageSize
+const maxSnapshotChunkBytes = 8 << 20
+
+// Message represents a network message
+type Message struct {
+	Type    string          `json:"type"`
+	Version uint32          `json:"version"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// HandshakeMessage is exchanged when two peers connect
+type HandshakeMessage struct {
+	ChainID         uint64   `json:"chain_id"`
+	GenesisHash     string   `json:"genesis_hash"`
+	ProtocolVersion uint32   `json:"protocol_version"`
+	Capabilities    []string `json:"capabilities"`
+	Height          uint64   `json:"height"`
+}
+
+// SyncRequest asks a peer for blocks starting at the given height
+type SyncRequest struct {
+	FromHeight uint64 `json:"from_height"`
+	Limit      int    `json:"limit"`
+}
+
+// SyncResponse carries a batch of consecutive blocks
+type SyncResponse struct {
+	Blocks []*blockchain.Block `json:"blocks"`
+}
+
+// SnapshotListRequest asks a peer for its available snapshots
+type SnapshotListRequest struct{}
+
+// SnapshotListResponse lists the snapshots a peer can serve
+type SnapshotListResponse struct {
+	Snapshots []*blockchain.Snapshot `json:"snapshots"`
+}
+
+// SnapshotChunkRequest asks a peer for consecutive snapshot chunks starting at the index
+type SnapshotChunkRequest struct {
+	Height uint64 `json:"height"`
+	Index  int    `json:"index"`
+	Count  int    `json:"count"`
+}
+
+// SnapshotChunkResponse carries consecutive snapshot chunks. It holds fewer chunks than
+// requested at the end of the snapshot or when the response would get too large.
+type SnapshotChunkResponse struct {
+	Chunks [][]byte `json:"chunks"`
+}
+
+// BlockAnnouncementMessage announces a newly produced block
+type BlockAnnouncementMessage struct {
+	Block *blockchain.Block `json:"block"`
+}
+
+// ValidatorAnnouncementMessage announces the signed record of an authority
+type ValidatorAnnouncementMessage struct {
+	Record *ValidatorRecord `json:"record"`
+}
+
+// NewMessage wraps a typed payload into a message envelope
+func NewMessage(msgType string, payload interface{}) (*Message, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %v", msgType, err)
+	}
+
+	return &Message{
+		Type:    msgType,
+		Version: ProtocolVersion,
+		Payload: data,
+	}, nil
+}
+
+// writeMessage writes a typed payload to the stream
+func writeMessage(w io.Writer, msgType string, payload interface{}) error {
+	msg, err := NewMessage(msgType, payload)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(msg)
+}
+
+// readMessage reads a message of the expected type from the stream and decodes its payload
+func readMessage(r io.Reader, msgType string, payload interface{}) error {
+	var msg Message
+	decoder := json.NewDecoder(io.LimitReader(r, maxMessageSize))
+	if err := decoder.Decode(&msg); err != nil {
+		return fmt.Errorf("failed to decode message: %v", err)
+	}
+
+	if msg.Type != msgType {
+		return fmt.Errorf("unexpected message type %q, expected %q", msg.Type, msgType)
+	}
+	if msg.Version < MinProtocolVersion {
+		return fmt.Errorf("unsupported message version %d", msg.Version)
+	}
+
+	if err := json.Unmarshal(msg.Payload, payload); err != nil {
+		return fmt.Errorf("failed to decode %s payload: %v", msgType, err)
+	}
+	return nil
+}
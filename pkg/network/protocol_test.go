@@ -0,0 +1,120 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+)
+
+// createTestNode test için rastgele portta dinleyen node oluşturur
+func createTestNode(t *testing.T, bc *blockchain.Blockchain, chainID uint64) *Node {
+	node, err := NewNode(NodeConfig{ListenPort: 0, ChainID: chainID}, bc)
+	if err != nil {
+		t.Fatalf("Node oluşturulamadı: %v", err)
+	}
+	t.Cleanup(func() { node.Close() })
+	return node
+}
+
+// createTestChain test için blockchain oluşturur
+func createTestChain(t *testing.T) *blockchain.Blockchain {
+	v, err := validator.NewAuthority(nil)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	bc, err := blockchain.NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+	return bc
+}
+
+// TestMessageRoundTrip tipli mesajların yazılıp okunmasını test eder
+func TestMessageRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	req := SyncRequest{FromHeight: 5, Limit: 10}
+	if err := writeMessage(&buf, BlockchainSync, &req); err != nil {
+		t.Fatalf("Mesaj yazılamadı: %v", err)
+	}
+
+	var decoded SyncRequest
+	if err := readMessage(bytes.NewReader(buf.Bytes()), BlockchainSync, &decoded); err != nil {
+		t.Fatalf("Mesaj okunamadı: %v", err)
+	}
+	if decoded != req {
+		t.Errorf("Beklenen %+v, alınan %+v", req, decoded)
+	}
+
+	// Farklı türde mesaj beklenirken hata alınmalı
+	if err := readMessage(bytes.NewReader(buf.Bytes()), BlockAnnouncement, &decoded); err == nil {
+		t.Error("Yanlış mesaj türü kabul edildi")
+	}
+}
+
+// TestHandshake aynı ve farklı ağdaki peer'larla handshake'i test eder
+func TestHandshake(t *testing.T) {
+	bc := createTestChain(t)
+	local := createTestNode(t, bc, DefaultChainID)
+
+	// Aynı zinciri paylaşan peer kabul edilmeli
+	compatible := createTestNode(t, bc, DefaultChainID)
+	if err := local.Connect(context.Background(), compatible.GetMultiaddr()); err != nil {
+		t.Fatalf("Uyumlu peer'a bağlanılamadı: %v", err)
+	}
+	if !local.peerSupports(compatible.ID(), BlockchainSync) {
+		t.Error("Uyumlu peer handshake sonrası kayıtlı olmalı")
+	}
+
+	// Farklı chain ID'ye sahip peer reddedilmeli
+	otherChainID := createTestNode(t, bc, DefaultChainID+1)
+	err := local.Connect(context.Background(), otherChainID.GetMultiaddr())
+	if err == nil || !strings.Contains(err.Error(), "chain ID mismatch") {
+		t.Errorf("Farklı chain ID reddedilmeli, alınan hata: %v", err)
+	}
+
+	// Validator'ları listeleyen aynı genesis ile başlayan ama farklı yerel validator'a
+	// sahip zincirler kabul edilmeli
+	v, err := validator.NewAuthority(nil)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	observer, err := validator.NewAuthority(nil)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	genesis := blockchain.DefaultGenesis()
+	genesis.Validators = []hexutil.Bytes{v.PublicKeyBytes()}
+	var sharedNodes []*Node
+	for _, authority := range []*validator.Authority{v, observer} {
+		chain, err := blockchain.NewBlockchainWithGenesis(authority, genesis)
+		if err != nil {
+			t.Fatalf("Blockchain oluşturulamadı: %v", err)
+		}
+		sharedNodes = append(sharedNodes, createTestNode(t, chain, DefaultChainID))
+	}
+	if err := sharedNodes[0].Connect(context.Background(), sharedNodes[1].GetMultiaddr()); err != nil {
+		t.Errorf("Aynı genesis'e sahip peer'a bağlanılamadı: %v", err)
+	}
+
+	// Farklı genesis bloğuna sahip peer reddedilmeli
+	genesis = blockchain.DefaultGenesis()
+	genesis.Timestamp = 1
+	otherChain, err := blockchain.NewBlockchainWithGenesis(v, genesis)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+	otherGenesis := createTestNode(t, otherChain, DefaultChainID)
+	err = local.Connect(context.Background(), otherGenesis.GetMultiaddr())
+	if err == nil || !strings.Contains(err.Error(), "genesis hash mismatch") {
+		t.Errorf("Farklı genesis reddedilmeli, alınan hata: %v", err)
+	}
+	if local.peerSupports(otherGenesis.ID(), BlockchainSync) {
+		t.Error("Uyumsuz peer kayıtlı olmamalı")
+	}
+}
@@ -0,0 +1,181 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+)
+
+// Sync modes
+const (
+	SyncModeFull     = "full"
+	SyncModeSnapshot = "snapshot"
+)
+
+// handleSnapshotList answers with the snapshots this node can serve
+func (n *Node) handleSnapshotList(stream network.Stream) {
+	defer stream.Close()
+
+	remotePeer := stream.Conn().RemotePeer()
+
+	var req SnapshotListRequest
+	if err := readMessage(stream, SnapshotList, &req); err != nil {
+		fmt.Printf("Failed to read snapshot list request: %s\n", err)
+		n.penalize(remotePeer, n.scorer.RecordMalformedMessage(remotePeer))
+		return
+	}
+
+	resp := SnapshotListResponse{Snapshots: n.blockchain.ListSnapshots()}
+	if err := writeMessage(stream, SnapshotList, &resp); err != nil {
+		fmt.Printf("Failed to write snapshot list: %s\n", err)
+	}
+}
+
+// handleSnapshotChunk answers with consecutive snapshot chunks
+func (n *Node) handleSnapshotChunk(stream network.Stream) {
+	defer stream.Close()
+
+	remotePeer := stream.Conn().RemotePeer()
+
+	var req SnapshotChunkRequest
+	if err := readMessage(stream, SnapshotChunk, &req); err != nil {
+		fmt.Printf("Failed to read snapshot chunk request: %s\n", err)
+		n.penalize(remotePeer, n.scorer.RecordMalformedMessage(remotePeer))
+		return
+	}
+
+	count := req.Count
+	if count <= 0 || count > maxSnapshotChunks {
+		count = maxSnapshotChunks
+	}
+
+	// İlk parça her zaman gönderilir, sonrakiler snapshot sonuna veya boyut sınırına kadar eklenir
+	var resp SnapshotChunkResponse
+	size := 0
+	for i := 0; i < count; i++ {
+		chunk, err := n.blockchain.GetSnapshotChunk(req.Height, req.Index+i)
+		if err != nil {
+			if i > 0 {
+				break
+			}
+			fmt.Printf("Snapshot chunk request from peer %s failed: %s\n", remotePeer, err)
+			stream.Reset()
+			return
+		}
+		if i > 0 && size+len(chunk) > maxSnapshotChunkBytes {
+			break
+		}
+		resp.Chunks = append(resp.Chunks, chunk)
+		size += len(chunk)
+	}
+
+	if err := writeMessage(stream, SnapshotChunk, &resp); err != nil {
+		fmt.Printf("Failed to write snapshot chunk: %s\n", err)
+	}
+}
+
+// syncSnapshot downloads the latest snapshot of a peer, syncs the blocks up to its height
+// without executing them and restores the state from it. On failure the chain is reset
+// to the genesis block for a full sync.
+func (n *Node) syncSnapshot(ctx context.Context, peerID peer.ID) error {
+	if !n.peerSupports(peerID, SnapshotList) {
+		return fmt.Errorf("peer %s does not serve snapshots", peerID)
+	}
+
+	var list SnapshotListResponse
+	if err := n.request(ctx, peerID, SnapshotList, &SnapshotListRequest{}, &list); err != nil {
+		return err
+	}
+	if len(list.Snapshots) == 0 || list.Snapshots[0] == nil {
+		return errors.New("peer has no snapshots")
+	}
+
+	// Liste en yeni snapshot ile başlar
+	snapshot := list.Snapshots[0]
+	fmt.Printf("Downloading snapshot at height %d from peer %s (%d chunks)\n", snapshot.Height, peerID, len(snapshot.ChunkHashes))
+
+	// Parçalar gruplar halinde istenir ve istekler peer'ın hız sınırına takılmayacak şekilde aralıklandırılır
+	chunks := make([][]byte, 0, len(snapshot.ChunkHashes))
+	interval := n.requestInterval()
+	for len(chunks) < len(snapshot.ChunkHashes) {
+		if len(chunks) > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		var resp SnapshotChunkResponse
+		req := SnapshotChunkRequest{Height: snapshot.Height, Index: len(chunks), Count: maxSnapshotChunks}
+		if err := n.request(ctx, peerID, SnapshotChunk, &req, &resp); err != nil {
+			return err
+		}
+		if len(resp.Chunks) == 0 || len(resp.Chunks) > req.Count {
+			n.penalize(peerID, n.scorer.RecordMalformedMessage(peerID))
+			return fmt.Errorf("peer %s returned %d snapshot chunks for %d requested", peerID, len(resp.Chunks), req.Count)
+		}
+
+		// Her parça indirildiği anda doğrulanır
+		for _, chunk := range resp.Chunks {
+			if err := blockchain.VerifySnapshotChunk(snapshot, len(chunks), chunk); err != nil {
+				n.penalize(peerID, n.scorer.RecordMalformedMessage(peerID))
+				return err
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	// Snapshot yüksekliğine kadar bloklar doğrulanıp eklenir, state bu bloğun state root'u ile doğrulanır
+	if err := n.blockchain.BeginSnapshotSync(snapshot); err != nil {
+		return err
+	}
+	if err := n.syncBlocks(ctx, peerID, snapshot.Height); err != nil {
+		n.blockchain.AbortSnapshotSync()
+		return err
+	}
+	if err := n.blockchain.RestoreSnapshot(snapshot, chunks); err != nil {
+		n.blockchain.AbortSnapshotSync()
+		n.penalize(peerID, n.scorer.RecordMalformedMessage(peerID))
+		return err
+	}
+	return nil
+}
+
+// requestInterval returns the time to wait between consecutive requests to a peer on the
+// same protocol so that they stay within the request rate limit, assuming the peer uses
+// the same limit as this node. One request of the limit is left as a margin.
+func (n *Node) requestInterval() time.Duration {
+	config := n.scorer.config
+	if config.RequestsPerInterval <= 1 {
+		return config.RateInterval
+	}
+	return config.RateInterval / time.Duration(config.RequestsPerInterval-1)
+}
+
+// request sends a request to a peer on the given protocol and decodes the response
+func (n *Node) request(ctx context.Context, peerID peer.ID, protocolID string, req, resp interface{}) error {
+	stream, err := n.host.NewStream(ctx, peerID, protocol.ID(protocolID))
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	if err := writeMessage(stream, protocolID, req); err != nil {
+		return err
+	}
+	if err := readMessage(stream, protocolID, resp); err != nil {
+		n.penalize(peerID, n.scorer.RecordMalformedMessage(peerID))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package network
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+	"github.com/SolidityDevSK/Confirmix/pkg/blockchain"
+)
+
+// TestSnapshotSync snapshot modunda yeni node'un state ve blokları senkronize etmesini test eder
+func TestSnapshotSync(t *testing.T) {
+	v, err := validator.NewAuthority(nil)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	source, err := blockchain.NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+	source.EnableSnapshots(blockchain.SnapshotConfig{Interval: 2, Keep: 1})
+
+	// İlk blokta kontrat deploy edilir, sonraki bloklar state root'u taşır
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Anahtar oluşturulamadı: %v", err)
+	}
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	tx := &blockchain.Transaction{From: owner.Hex(), Data: common.FromHex("600a600c600039600a6000f3602a60005260206000f3"), GasLimit: 200000}
+	if err := tx.Sign(key); err != nil {
+		t.Fatalf("İşlem imzalanamadı: %v", err)
+	}
+	if err := source.SubmitTransaction(tx); err != nil {
+		t.Fatalf("İşlem gönderilemedi: %v", err)
+	}
+	for height := uint64(1); height <= 3; height++ {
+		block, err := source.CreateBlock(v)
+		if err != nil {
+			t.Fatalf("Blok oluşturulamadı: %v", err)
+		}
+		if err := source.AddBlock(block); err != nil {
+			t.Fatalf("Blok eklenemedi: %v", err)
+		}
+	}
+	receipt, exists := source.GetReceipt(hex.EncodeToString(tx.Hash))
+	if !exists || receipt.Status != blockchain.TxSuccess {
+		t.Fatalf("Deploy başarısız: %+v", receipt)
+	}
+	contractAddress := common.HexToAddress(receipt.ContractAddress)
+
+	// Aynı genesis ile başlayan yeni zincir
+	fresh, err := blockchain.NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	sourceNode := createTestNode(t, source, DefaultChainID)
+	freshNode, err := NewNode(NodeConfig{ListenPort: 0, ChainID: DefaultChainID, SyncMode: SyncModeSnapshot}, fresh)
+	if err != nil {
+		t.Fatalf("Node oluşturulamadı: %v", err)
+	}
+	defer freshNode.Close()
+
+	if err := freshNode.Connect(context.Background(), sourceNode.GetMultiaddr()); err != nil {
+		t.Fatalf("Senkronizasyon başarısız: %v", err)
+	}
+
+	if fresh.GetBlockCount() != source.GetBlockCount() {
+		t.Errorf("Beklenen blok sayısı %d, alınan: %d", source.GetBlockCount(), fresh.GetBlockCount())
+	}
+	if _, err := fresh.GetContract(contractAddress); err != nil {
+		t.Errorf("Snapshot state'i yüklenmedi: %v", err)
+	}
+	if fresh.GetStateRoot() != source.GetStateRoot() {
+		t.Error("State root kaynak zincirle aynı olmalı")
+	}
+
+	// Snapshot yüksekliğine kadar olan bloklar çalıştırılmaz
+	if _, exists := fresh.GetReceipt(receipt.TxHash); exists {
+		t.Error("Snapshot öncesi blok yeniden çalıştırıldı")
+	}
+}
+
+// TestSnapshotSyncRateLimit hız sınırından fazla parçası olan snapshot'ın peer cezalandırılmadan indirilmesini test eder
+func TestSnapshotSyncRateLimit(t *testing.T) {
+	v, err := validator.NewAuthority(nil)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	source, err := blockchain.NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+	source.EnableSnapshots(blockchain.SnapshotConfig{Interval: 2, Keep: 1, ChunkSize: 16})
+	for height := uint64(1); height <= 2; height++ {
+		block, err := source.CreateBlock(v)
+		if err != nil {
+			t.Fatalf("Blok oluşturulamadı: %v", err)
+		}
+		if err := source.AddBlock(block); err != nil {
+			t.Fatalf("Blok eklenemedi: %v", err)
+		}
+	}
+
+	fresh, err := blockchain.NewBlockchain(v)
+	if err != nil {
+		t.Fatalf("Blockchain oluşturulamadı: %v", err)
+	}
+
+	sourceNode := createTestNode(t, source, DefaultChainID)
+	freshNode, err := NewNode(NodeConfig{ListenPort: 0, ChainID: DefaultChainID, SyncMode: SyncModeSnapshot}, fresh)
+	if err != nil {
+		t.Fatalf("Node oluşturulamadı: %v", err)
+	}
+	defer freshNode.Close()
+
+	// Düşük hız sınırı ile parça istekleri sınırı aşar
+	for _, node := range []*Node{sourceNode, freshNode} {
+		node.scorer.config.RequestsPerInterval = 3
+		node.scorer.config.RateInterval = 300 * time.Millisecond
+	}
+	snapshots := source.ListSnapshots()
+	if len(snapshots) == 0 {
+		t.Fatal("Snapshot oluşturulmadı")
+	}
+	if requests := (len(snapshots[0].ChunkHashes) + maxSnapshotChunks - 1) / maxSnapshotChunks; requests <= 3 {
+		t.Fatalf("Parça istekleri hız sınırını aşmalı, istek sayısı: %d", requests)
+	}
+
+	if err := freshNode.Connect(context.Background(), sourceNode.GetMultiaddr()); err != nil {
+		t.Fatalf("Senkronizasyon başarısız: %v", err)
+	}
+
+	if fresh.GetBlockCount() != source.GetBlockCount() {
+		t.Errorf("Beklenen blok sayısı %d, alınan: %d", source.GetBlockCount(), fresh.GetBlockCount())
+	}
+	if fresh.GetStateRoot() != source.GetStateRoot() {
+		t.Error("State root kaynak zincirle aynı olmalı")
+	}
+	if score, exists := sourceNode.scorer.GetScore(freshNode.host.ID()); exists && score.RateLimited > 0 {
+		t.Errorf("Senkronize olan node hız sınırına takılmamalı: %+v", score)
+	}
+}
@@ -0,0 +1,123 @@
+package network
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+)
+
+// ValidatorRecord is a signed statement by an authority about how to reach its node
+type ValidatorRecord struct {
+	Address   string `json:"address"`
+	PublicKey string `json:"public_key"`
+	PeerID    string `json:"peer_id"`
+	Endpoint  string `json:"endpoint"`
+	Sequence  uint64 `json:"sequence"`
+	Signature string `json:"signature"`
+}
+
+// NewValidatorRecord creates a validator record signed by the authority
+func NewValidatorRecord(authority *validator.Authority, peerID peer.ID, endpoint string, sequence uint64) (*ValidatorRecord, error) {
+	if authority == nil {
+		return nil, errors.New("authority is nil")
+	}
+
+	record := &ValidatorRecord{
+		Address:   authority.Address,
+		PublicKey: hex.EncodeToString(authority.PublicKeyBytes()),
+		PeerID:    peerID.String(),
+		Endpoint:  endpoint,
+		Sequence:  sequence,
+	}
+
+	hash, err := record.signingHash()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := authority.Sign(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign validator record: %v", err)
+	}
+	record.Signature = hex.EncodeToString(signature)
+
+	return record, nil
+}
+
+// signingHash returns the hash of all record fields except the signature
+func (r *ValidatorRecord) signingHash() ([]byte, error) {
+	unsigned := *r
+	unsigned.Signature = ""
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal validator record: %v", err)
+	}
+
+	hash := sha256.Sum256(data)
+	return hash[:], nil
+}
+
+// Verify checks the record against the authority registered in the validator set
+func (r *ValidatorRecord) Verify(authority *validator.Authority) error {
+	if authority == nil {
+		return fmt.Errorf("validator %s is not in the validator set", r.Address)
+	}
+	if authority.Address != r.Address {
+		return errors.New("validator address mismatch")
+	}
+
+	publicKey, err := hex.DecodeString(r.PublicKey)
+	if err != nil || !bytes.Equal(publicKey, authority.PublicKeyBytes()) {
+		return errors.New("public key does not match the validator set")
+	}
+
+	if _, err := peer.Decode(r.PeerID); err != nil {
+		return fmt.Errorf("invalid peer ID: %v", err)
+	}
+	if _, err := multiaddr.NewMultiaddr(r.Endpoint); err != nil {
+		return fmt.Errorf("invalid endpoint: %v", err)
+	}
+
+	signature, err := hex.DecodeString(r.Signature)
+	if err != nil {
+		return errors.New("invalid signature encoding")
+	}
+
+	hash, err := r.signingHash()
+	if err != nil {
+		return err
+	}
+	if !authority.Verify(hash, signature) {
+		return errors.New("invalid validator record signature")
+	}
+	return nil
+}
+
+// AddrInfo returns the peer address information of the record
+func (r *ValidatorRecord) AddrInfo() (*peer.AddrInfo, error) {
+	id, err := peer.Decode(r.PeerID)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := multiaddr.NewMultiaddr(r.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Endpoint /p2p/ bileşeni içerebilir
+	if transport, _ := peer.SplitAddr(addr); transport != nil {
+		addr = transport
+	}
+
+	return &peer.AddrInfo{ID: id, Addrs: []multiaddr.Multiaddr{addr}}, nil
+}
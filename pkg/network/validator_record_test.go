@@ -0,0 +1,72 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/SolidityDevSK/Confirmix/internal/validator"
+)
+
+// TestValidatorRecord imzalı validator kayıtlarının doğrulanmasını test eder
+func TestValidatorRecord(t *testing.T) {
+	authority, err := validator.NewAuthority(nil)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	other, err := validator.NewAuthority(nil)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+
+	peerID := createTestPeerID(t)
+	endpoint := "/ip4/127.0.0.1/tcp/9000/p2p/" + peerID.String()
+
+	record, err := NewValidatorRecord(authority, peerID, endpoint, 1)
+	if err != nil {
+		t.Fatalf("Validator kaydı oluşturulamadı: %v", err)
+	}
+	if err := record.Verify(authority); err != nil {
+		t.Fatalf("Geçerli kayıt reddedildi: %v", err)
+	}
+
+	info, err := record.AddrInfo()
+	if err != nil {
+		t.Fatalf("Adres bilgisi alınamadı: %v", err)
+	}
+	if info.ID != peerID || len(info.Addrs) != 1 {
+		t.Errorf("Beklenmeyen adres bilgisi: %v", info)
+	}
+
+	// Validator setinde olmayan authority
+	if err := record.Verify(nil); err == nil {
+		t.Error("Validator setinde olmayan kayıt kabul edildi")
+	}
+
+	// Başka bir authority'ye ait anahtar
+	if err := record.Verify(other); err == nil {
+		t.Error("Farklı authority için kayıt kabul edildi")
+	}
+
+	// İmzadan sonra değiştirilmiş kayıt
+	tampered := *record
+	tampered.Sequence = 2
+	if err := tampered.Verify(authority); err == nil {
+		t.Error("Değiştirilmiş kayıt kabul edildi")
+	}
+
+	// secp256k1 authority'nin kaydı yalnızca public key'i bilinen authority ile doğrulanır
+	secp, err := validator.GenerateAuthority(validator.KeyTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("Validator oluşturulamadı: %v", err)
+	}
+	record, err = NewValidatorRecord(secp, peerID, endpoint, 1)
+	if err != nil {
+		t.Fatalf("Validator kaydı oluşturulamadı: %v", err)
+	}
+	known, err := validator.NewAuthorityFromPublicKey(secp.PublicKeyBytes())
+	if err != nil || known.Address != secp.Address {
+		t.Fatalf("Public key'den authority oluşturulamadı: %v", err)
+	}
+	if err := record.Verify(known); err != nil {
+		t.Errorf("secp256k1 kaydı reddedildi: %v", err)
+	}
+}